@@ -0,0 +1,185 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: iam/v1/iam.proto
+
+package v1
+
+import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ErrResponse 错误返回包，REST 接口出错时返回，与 pkg/core/http.ErrResponse 保持一致
+type ErrResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// code 业务错误码
+	Code      int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Msg       string `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Reference string `protobuf:"bytes,3,opt,name=reference,proto3" json:"reference,omitempty"`
+}
+
+func (x *ErrResponse) Reset() {
+	*x = ErrResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_iam_v1_iam_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ErrResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrResponse) ProtoMessage() {}
+
+func (x *ErrResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrResponse.ProtoReflect.Descriptor instead.
+func (*ErrResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ErrResponse) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ErrResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *ErrResponse) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+var File_iam_v1_iam_proto protoreflect.FileDescriptor
+
+var file_iam_v1_iam_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x69, 0x61, 0x6d, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x61, 0x6d, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x06, 0x69, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x6f, 0x70, 0x65, 0x6e, 0x61, 0x70, 0x69, 0x76, 0x32,
+	0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x51, 0x0a, 0x0b, 0x45, 0x72,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x6d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x12,
+	0x1c, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x42, 0xa9, 0x02,
+	0x92, 0x41, 0xfd, 0x01, 0x12, 0x0d, 0x0a, 0x07, 0x49, 0x41, 0x4d, 0x20, 0x41, 0x50, 0x49, 0x32,
+	0x02, 0x76, 0x31, 0x2a, 0x02, 0x01, 0x02, 0x32, 0x10, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x3a, 0x10, 0x61, 0x70, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x52, 0x5c, 0x0a, 0x07, 0x64,
+	0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x51, 0x0a, 0x36, 0xe9, 0x94, 0x99, 0xe8, 0xaf, 0xaf,
+	0xe8, 0xbf, 0x94, 0xe5, 0x9b, 0x9e, 0xe5, 0x8c, 0x85, 0xef, 0xbc, 0x8c, 0xe4, 0xb8, 0x8e, 0x20,
+	0x70, 0x6b, 0x67, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x68, 0x74, 0x74, 0x70, 0x2e, 0x45, 0x72,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x20, 0xe4, 0xb8, 0x80, 0xe8, 0x87, 0xb4,
+	0x12, 0x17, 0x0a, 0x15, 0x1a, 0x13, 0x2e, 0x69, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5a, 0x58, 0x0a, 0x56, 0x0a, 0x06, 0x42,
+	0x65, 0x61, 0x72, 0x65, 0x72, 0x12, 0x4c, 0x08, 0x02, 0x12, 0x37, 0x42, 0x65, 0x61, 0x72, 0x65,
+	0x72, 0x20, 0x3c, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x3e, 0xef, 0xbc, 0x8c, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x20, 0xe7, 0x94, 0xb1, 0x20, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x20, 0xe6, 0x88, 0x96,
+	0xe7, 0x94, 0xa8, 0xe6, 0x88, 0xb7, 0xe5, 0xaf, 0x86, 0xe9, 0x92, 0xa5, 0xe7, 0xad, 0xbe, 0xe5,
+	0x8f, 0x91, 0x1a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x20, 0x02, 0x62, 0x0c, 0x0a, 0x0a, 0x0a, 0x06, 0x42, 0x65, 0x61, 0x72, 0x65, 0x72, 0x12,
+	0x00, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x68,
+	0x61, 0x6e, 0x67, 0x37, 0x2f, 0x67, 0x6f, 0x2d, 0x49, 0x41, 0x4d, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x69, 0x61, 0x6d, 0x2f, 0x76, 0x31, 0x3b, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_iam_v1_iam_proto_rawDescOnce sync.Once
+	file_iam_v1_iam_proto_rawDescData = file_iam_v1_iam_proto_rawDesc
+)
+
+func file_iam_v1_iam_proto_rawDescGZIP() []byte {
+	file_iam_v1_iam_proto_rawDescOnce.Do(func() {
+		file_iam_v1_iam_proto_rawDescData = protoimpl.X.CompressGZIP(file_iam_v1_iam_proto_rawDescData)
+	})
+	return file_iam_v1_iam_proto_rawDescData
+}
+
+var file_iam_v1_iam_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_iam_v1_iam_proto_goTypes = []any{
+	(*ErrResponse)(nil), // 0: iam.v1.ErrResponse
+}
+var file_iam_v1_iam_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_iam_v1_iam_proto_init() }
+func file_iam_v1_iam_proto_init() {
+	if File_iam_v1_iam_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_iam_v1_iam_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*ErrResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_iam_v1_iam_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_iam_v1_iam_proto_goTypes,
+		DependencyIndexes: file_iam_v1_iam_proto_depIdxs,
+		MessageInfos:      file_iam_v1_iam_proto_msgTypes,
+	}.Build()
+	File_iam_v1_iam_proto = out.File
+	file_iam_v1_iam_proto_rawDesc = nil
+	file_iam_v1_iam_proto_goTypes = nil
+	file_iam_v1_iam_proto_depIdxs = nil
+}
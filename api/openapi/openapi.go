@@ -0,0 +1,9 @@
+// Package openapi 内嵌由 api/iam 下的 proto 生成的 OpenAPI 文档，使用 make proto 重新生成
+package openapi
+
+import _ "embed"
+
+// Spec 合并后的 OpenAPI v2 文档
+//
+//go:embed iam.swagger.json
+var Spec []byte
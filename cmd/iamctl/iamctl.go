@@ -0,0 +1,7 @@
+package main
+
+import "github.com/ahang7/go-IAM/internal/iamctl"
+
+func main() {
+	iamctl.NewApp().Run()
+}
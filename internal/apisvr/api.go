@@ -0,0 +1,53 @@
+package apisvr
+
+import (
+	"time"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/internal/apisvr/service"
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/pkg/authz"
+	"github.com/ahang7/go-IAM/internal/pkg/metrics"
+	"github.com/ahang7/go-IAM/internal/pkg/transcoding"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+)
+
+// apiService 由 proto 定义的服务，同时以 gRPC 和 REST 方式提供，REST 路由由 google.api.http 注解生成
+type apiService struct {
+	desc *grpc.ServiceDesc
+	impl any
+}
+
+// policyCacheTTL 授权判定使用的策略缓存的有效期
+const policyCacheTTL = 30 * time.Second
+
+func newAPIServices(s store.Factory) []apiService {
+	policies := authz.NewCache(service.NewPolicyGetter(s), policyCacheTTL)
+	metrics.RegisterPolicyCache(policies.Stats)
+
+	return []apiService{
+		{desc: &v1.UserService_ServiceDesc, impl: service.NewUserService(s)},
+		{desc: &v1.SecretService_ServiceDesc, impl: service.NewSecretService(s)},
+		{desc: &v1.PolicyService_ServiceDesc, impl: service.NewPolicyService(s, policies)},
+		{desc: &v1.AuthzService_ServiceDesc, impl: service.NewAuthzService(policies)},
+	}
+}
+
+// registerGRPCServices 注册 gRPC 服务
+func registerGRPCServices(srv *grpc.Server, services []apiService) {
+	for _, svc := range services {
+		srv.RegisterService(svc.desc, svc.impl)
+	}
+}
+
+// registerRESTServices 将服务按照 HTTP 注解转换为 REST 路由
+func registerRESTServices(r gin.IRoutes, services []apiService) error {
+	for _, svc := range services {
+		if _, err := transcoding.Register(r, svc.desc, svc.impl, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package apisvr
 
 import (
 	"github.com/ahang7/go-IAM/internal/apisvr/options"
+	"github.com/ahang7/go-IAM/internal/pkg/server"
 	"github.com/ahang7/go-IAM/pkg/app"
 	"github.com/ahang7/go-IAM/pkg/log"
 )
@@ -11,20 +12,38 @@ const commandDesc = `The IAM API server validates and configures data ...`
 func NewApp() *app.App {
 	// 配置appoptions
 	opts := options.NewOptions()
+	reloader := app.NewReloader(func() app.FlagsOptions { return options.NewOptions() })
 	a := app.NewApp(
 		"IAM",
 		"iamsvr",
 		app.WithFlags(opts),
 		app.WithDescription(commandDesc),
 		app.WithDefaultValidArgs(),
-		app.WithRunFunc(run(opts)),
+		app.WithReloader(reloader),
+		app.WithCommands(newMigrateCommand()),
+		app.WithRunFunc(run(opts, reloader)),
 	)
 	return a
 }
 
-func run(opts *options.Options) app.RunFunc {
+func run(opts *options.Options, reloader *app.Reloader) app.RunFunc {
 	return func(app string) error {
 		log.Infof("opts: %v", opts)
-		return nil
+
+		apiServer, err := createAPIServer(opts)
+		if err != nil {
+			return err
+		}
+
+		prepared, err := apiServer.PrepareRun()
+		if err != nil {
+			_ = apiServer.store.Close()
+
+			return err
+		}
+		reloader.Subscribe(prepared.reload, reloadableSections...)
+		reloader.Subscribe(reloadLogLevels, logLevelSections...)
+
+		return prepared.Run(server.SetUpSignalHandler())
 	}
 }
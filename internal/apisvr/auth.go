@@ -1,16 +1,24 @@
 package apisvr
 
 import (
+	"context"
 	"encoding/base64"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/metrics"
 	"github.com/ahang7/go-IAM/internal/pkg/middleware"
 	"github.com/ahang7/go-IAM/internal/pkg/middleware/auth"
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/errors"
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
-	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const (
@@ -21,31 +29,65 @@ const (
 )
 
 type loginInfo struct {
-	Username string `form:"username" json:"username" binding:"required,username"`
-	Password string `form:"password" json:"password" binding:"required,password"`
+	Username string `form:"username" json:"username" binding:"required"`
+	Password string `form:"password" json:"password" binding:"required"`
 }
 
-func newAutoAuth() middleware.AuthStrategy {
+// newAutoAuth Basic 认证校验用户名和密码，Bearer 认证同时支持登录获得的 token 和使用用户密钥签发的 token
+func newAutoAuth(info *server.JWTInfo, s store.Factory) (middleware.AuthStrategy, error) {
+	jwtStrategy, err := newJWTAuth(info, s)
+	if err != nil {
+		return nil, err
+	}
+
 	return auth.NewAutoStrategy(
-		newBasicAuth().(auth.BasicStrategy),
-		newJWTAuth().(auth.JWTStrategy),
-	)
+		newBasicAuth(s),
+		auth.NewBearerStrategy(jwtStrategy, newSecretAuth(s)),
+	), nil
+}
+
+// installAdminAuth 管理接口只允许认证通过的管理员访问
+func installAdminAuth(cfg *server.Config, s store.Factory) error {
+	auto, err := newAutoAuth(cfg.JWT, s)
+	if err != nil {
+		return err
+	}
+	cfg.AdminMiddlewares = append(cfg.AdminMiddlewares, auto.AuthExecute(), func(c *gin.Context) {
+		user, err := s.Users().Get(c, middleware.UsernameFrom(c))
+		if err == nil && !user.IsAdmin {
+			err = errors.WithCode(code.ErrPermissionDenied, "user %s is not an administrator", user.Name)
+		}
+		if err != nil {
+			httpcore.WriteResponse(c, err, nil)
+			c.Abort()
+
+			return
+		}
+		c.Next()
+	})
+
+	return nil
 }
 
-func newBasicAuth() middleware.AuthStrategy {
+func newBasicAuth(s store.Factory) auth.BasicStrategy {
 	return auth.NewBasicStrategy(func(username, password string) bool {
-		return true
+		return checkPassword(context.Background(), s, username, password) == nil
 	})
 }
 
-func newJWTAuth() middleware.AuthStrategy {
+// newJWTAuth 使用校验通过的 jwt 配置创建登录 token 的认证策略，info 中的密钥引用已经在加载配置时解析，
+// jwt.key 为空等配置错误时返回错误
+func newJWTAuth(info *server.JWTInfo, s store.Factory) (auth.JWTStrategy, error) {
+	if info == nil {
+		return auth.JWTStrategy{}, fmt.Errorf("create jwt auth: jwt config is missing")
+	}
 	ginJWTMiddleware, err := jwt.New(&jwt.GinJWTMiddleware{
-		Realm:            viper.GetString("jwt.Realm"),
+		Realm:            info.Realm,
 		SigningAlgorithm: "HS256",
-		Key:              []byte(viper.GetString("jwt.Key")),
-		Timeout:          viper.GetDuration("jwt.Timeout"),
-		MaxRefresh:       viper.GetDuration("jwt.MaxRefresh"),
-		Authenticator:    authenticator(),
+		Key:              []byte(info.Key),
+		Timeout:          info.Timeout,
+		MaxRefresh:       info.MaxRefresh,
+		Authenticator:    authenticator(s),
 		Authorizator:     authorizator(),
 		PayloadFunc:      payloadFunc(),
 		Unauthorized:     Unauthorized(),
@@ -60,12 +102,13 @@ func newJWTAuth() middleware.AuthStrategy {
 		SendCookie:       true,
 	})
 	if err != nil {
-		return nil
+		return auth.JWTStrategy{}, fmt.Errorf("create jwt auth: %w", err)
 	}
-	return auth.NewJWTStrategy(*ginJWTMiddleware)
+
+	return auth.NewJWTStrategy(*ginJWTMiddleware), nil
 }
 
-func authenticator() func(c *gin.Context) (interface{}, error) {
+func authenticator(s store.Factory) func(c *gin.Context) (interface{}, error) {
 	return func(c *gin.Context) (interface{}, error) {
 		var login loginInfo
 		var err error
@@ -78,13 +121,30 @@ func authenticator() func(c *gin.Context) (interface{}, error) {
 		if err != nil {
 			return "", err
 		}
-		// TODO: get user information by the login username. db/cache
-		// compare username and password
-		// set user login time at
-		// cache update
+		err = checkPassword(c.Request.Context(), s, login.Username, login.Password)
+		metrics.ObserveLogin("password", err)
+		if err != nil {
+			return "", jwt.ErrFailedAuthentication
+		}
+
+		return login.Username, nil
+	}
+}
 
-		return login.Username, err
+// checkPassword 校验用户密码，用户不存在或已禁用时同样返回错误
+func checkPassword(ctx context.Context, s store.Factory, username, password string) error {
+	user, err := s.Users().Get(ctx, username)
+	if err != nil {
+		return err
+	}
+	if user.Status != 1 {
+		return errors.WithCode(code.ErrPermissionDenied, "user %s is disabled", username)
 	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errors.WrapC(err, code.ErrPasswordIncorrect, "password of user %s is incorrect", username)
+	}
+
+	return nil
 }
 
 // 获取Authorization头的值，并调用strings.SplitN函数，获取一个切片变量auth，其值为 ["Basic","YWRtaW46QWRtaW5AMjAyMQ=="]
@@ -134,10 +194,10 @@ func payloadFunc() func(data interface{}) jwt.MapClaims {
 			"iss": APIServerIssuer,
 			"aud": APIServerAudience,
 		}
-		// TODO: set claims[jwt.IdentityKey] = u.Name and ["sub"] = u.Name
-		//if u, ok != data; ok {
-		//	claims["username"] = u
-		//}
+		if username, ok := data.(string); ok {
+			claims[jwt.IdentityKey] = username
+			claims["sub"] = username
+		}
 		return claims
 	}
 }
@@ -152,6 +212,7 @@ func Unauthorized() func(c *gin.Context, code int, message string) {
 
 func loginResponse() func(c *gin.Context, code int, token string, expire time.Time) {
 	return func(c *gin.Context, code int, token string, expire time.Time) {
+		metrics.ObserveTokenIssued(metrics.TokenLogin)
 		c.JSON(http.StatusOK, gin.H{
 			"token":  token,
 			"expire": expire.Format(time.RFC3339),
@@ -169,6 +230,7 @@ func logoutResponse() func(c *gin.Context, code int) {
 
 func refreshResponse() func(c *gin.Context, code int, token string, expire time.Time) {
 	return func(c *gin.Context, code int, token string, expire time.Time) {
+		metrics.ObserveTokenIssued(metrics.TokenRefresh)
 		c.JSON(http.StatusOK, gin.H{
 			"token":  token,
 			"expire": expire.Format(time.RFC3339),
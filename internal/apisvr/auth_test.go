@@ -0,0 +1,142 @@
+package apisvr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahang7/go-IAM/internal/apisvr/options"
+	"github.com/ahang7/go-IAM/internal/apisvr/store/migrations"
+	"github.com/ahang7/go-IAM/internal/apisvr/store/mysql"
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	pkgdb "github.com/ahang7/go-IAM/pkg/db"
+	"github.com/ahang7/go-IAM/pkg/secret"
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestInstallAdminAuth(t *testing.T) {
+	ctx := context.Background()
+	db, err := pkgdb.NewClient(&pkgdb.Options{Driver: pkgdb.DriverSQLite, Database: ":memory:", LogLevel: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s := mysql.NewFactory(db)
+	defer s.Close()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("Passw0rd!"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []*model.User{
+		{ObjectMeta: model.ObjectMeta{Name: "admin"}, Password: string(hashed), Status: 1, IsAdmin: true},
+		{ObjectMeta: model.ObjectMeta{Name: "alice"}, Password: string(hashed), Status: 1},
+	} {
+		if err := s.Users().Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := server.NewNilConfig()
+	cfg.JWT.Key = strings.Repeat("k", 32)
+	if err := installAdminAuth(cfg, s); err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Group("/admin", cfg.AdminMiddlewares...).GET("/loglevel", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name      string
+		username  string
+		password  string
+		status    int
+		errorCode int
+	}{
+		{"admin", "admin", "Passw0rd!", http.StatusOK, 0},
+		{"not admin", "alice", "Passw0rd!", http.StatusForbidden, code.ErrPermissionDenied},
+		{"wrong password", "admin", "wrong", http.StatusUnauthorized, 0},
+		{"anonymous", "", "", http.StatusUnauthorized, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/loglevel", nil)
+			if tt.username != "" {
+				req.SetBasicAuth(tt.username, tt.password)
+			}
+			w := httptest.NewRecorder()
+			e.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("GET as %q = %d %s, want %d", tt.username, w.Code, w.Body.String(), tt.status)
+			}
+			if tt.errorCode != 0 {
+				var resp httpcore.ErrResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.ErrorCode != tt.errorCode {
+					t.Errorf("GET as %q = %s, want error code %d", tt.username, w.Body.String(), tt.errorCode)
+				}
+			}
+		})
+	}
+}
+
+func TestNewJWTAuth_FileKey(t *testing.T) {
+	key := strings.Repeat("s", 40)
+	file := filepath.Join(t.TempDir(), "jwt")
+	if err := os.WriteFile(file, []byte(key+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ref := "${file:" + file + "}"
+	// viper 中保留的是未解析的密钥引用，签名密钥必须来自加载配置时解析过的配置
+	viper.Set("jwt.key", ref)
+	defer viper.Set("jwt.key", nil)
+
+	opts := options.NewOptions()
+	resolved, err := secret.Expand(context.Background(), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.JwtOptions.Key = resolved
+	cfg, err := buildGenericConfig(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strategy, err := newJWTAuth(cfg.JWT, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _, err := strategy.TokenGenerator(map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyFunc := func(k string) gojwt.Keyfunc {
+		return func(*gojwt.Token) (interface{}, error) { return []byte(k), nil }
+	}
+	if _, err := gojwt.Parse(token, keyFunc(key)); err != nil {
+		t.Errorf("token is not signed with the file contents: %v", err)
+	}
+	if _, err := gojwt.Parse(token, keyFunc(ref)); err == nil {
+		t.Error("token is signed with the unresolved secret reference")
+	}
+}
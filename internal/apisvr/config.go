@@ -0,0 +1,31 @@
+package apisvr
+
+import (
+	"github.com/ahang7/go-IAM/internal/apisvr/options"
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+)
+
+// buildGenericConfig 根据命令行及配置文件选项构建通用服务器配置
+func buildGenericConfig(opts *options.Options) (*server.Config, error) {
+	cfg := server.NewNilConfig()
+	if err := opts.GenericServerRunOptions.ApplyTo(cfg); err != nil {
+		return nil, err
+	}
+	if err := opts.InsecureServing.ApplyTo(cfg); err != nil {
+		return nil, err
+	}
+	if err := opts.SecureServing.ApplyTo(cfg); err != nil {
+		return nil, err
+	}
+	if err := opts.GRPCOptions.ApplyTo(cfg); err != nil {
+		return nil, err
+	}
+	if err := opts.JwtOptions.ApplyTo(cfg); err != nil {
+		return nil, err
+	}
+	if err := opts.MiddlewareOptions.ApplyTo(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
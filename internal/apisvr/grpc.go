@@ -0,0 +1,57 @@
+package apisvr
+
+import (
+	"context"
+
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/pkg/interceptor"
+	"github.com/ahang7/go-IAM/internal/pkg/middleware/auth"
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+)
+
+// grpcSkipAuthMethods 不需要认证的 gRPC 方法
+var grpcSkipAuthMethods = []string{
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+}
+
+// installGRPCInterceptors 安装 gRPC 拦截器，顺序为 错误转换 -> panic 恢复 -> 认证
+func installGRPCInterceptors(cfg *server.Config, s store.Factory) error {
+	jwtStrategy, err := newJWTAuth(cfg.JWT, s)
+	if err != nil {
+		return err
+	}
+	authenticator := interceptor.NewAuthenticator(grpcSkipAuthMethods...).
+		WithVerifier("Bearer", jwtStrategy).
+		WithVerifier("Bearer", newSecretAuth(s))
+
+	cfg.GRPCUnaryInterceptors = append(cfg.GRPCUnaryInterceptors,
+		interceptor.UnaryErrors(),
+		interceptor.UnaryRecovery(),
+		authenticator.Unary(),
+	)
+	cfg.GRPCStreamInterceptors = append(cfg.GRPCStreamInterceptors,
+		interceptor.StreamErrors(),
+		interceptor.StreamRecovery(),
+		authenticator.Stream(),
+	)
+
+	return nil
+}
+
+// newSecretAuth 使用用户密钥签发的 token 进行认证
+func newSecretAuth(s store.Factory) auth.CacheStrategy {
+	return auth.NewCacheStrategy(func(ctx context.Context, kid string) (auth.Secret, error) {
+		secret, err := s.Secrets().GetBySecretID(ctx, kid)
+		if err != nil {
+			return auth.Secret{}, err
+		}
+
+		return auth.Secret{
+			Username: secret.Username,
+			ID:       secret.SecretID,
+			Key:      secret.SecretKey,
+			Expires:  secret.Expires,
+		}, nil
+	})
+}
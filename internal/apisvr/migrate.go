@@ -0,0 +1,145 @@
+package apisvr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/apisvr/options"
+	"github.com/ahang7/go-IAM/internal/apisvr/store/migrations"
+	"github.com/ahang7/go-IAM/pkg/app"
+	pkgdb "github.com/ahang7/go-IAM/pkg/db"
+	"github.com/ahang7/go-IAM/pkg/db/migrate"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+const migrateDesc = `Manage the database schema with the versioned migrations embedded in the binary.
+
+The database is configured by the database section of the configuration file, the same as the server.
+Concurrent runs against the same database take turns through an advisory lock (not needed for sqlite).`
+
+// newMigrateCommand 返回 migrate 命令，包含 up、down、status 和 to 子命令
+func newMigrateCommand() *app.Command {
+	opts := options.NewMigrateOptions()
+
+	cmd := app.NewCommand("migrate", "Manage the database schema", app.WithCommandLong(migrateDesc))
+	cmd.AddCommands(
+		app.NewCommand("up", "Apply all pending migrations",
+			app.WithCommandFlags(opts),
+			app.WithCommandConfig(),
+			app.WithCommandArgs(cobra.NoArgs),
+			app.WithCommandRunFunc(runMigrate(opts, func(ctx context.Context, m *migrate.Migrator, _ []string) ([]migrate.Migration, error) {
+				return m.Up(ctx)
+			})),
+		),
+		app.NewCommand("down", "Roll back the latest applied migration",
+			app.WithCommandFlags(opts),
+			app.WithCommandConfig(),
+			app.WithCommandArgs(cobra.NoArgs),
+			app.WithCommandRunFunc(runMigrate(opts, func(ctx context.Context, m *migrate.Migrator, _ []string) ([]migrate.Migration, error) {
+				return m.Down(ctx)
+			})),
+		),
+		app.NewCommand("to VERSION", "Migrate up or down to VERSION, 0 rolls back all migrations",
+			app.WithCommandFlags(opts),
+			app.WithCommandConfig(),
+			app.WithCommandArgs(cobra.ExactArgs(1)),
+			app.WithCommandRunFunc(runMigrate(opts, func(ctx context.Context, m *migrate.Migrator, args []string) ([]migrate.Migration, error) {
+				version, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid version %q: %w", args[0], err)
+				}
+
+				return m.To(ctx, version)
+			})),
+		),
+		app.NewCommand("status", "Show the applied and pending migrations",
+			app.WithCommandFlags(opts),
+			app.WithCommandConfig(),
+			app.WithCommandArgs(cobra.NoArgs),
+			app.WithCommandRunFunc(migrateStatus(opts)),
+		),
+	)
+
+	return cmd
+}
+
+// withMigrator 连接数据库并创建 Migrator，fn 返回后关闭连接
+func withMigrator(opts *options.MigrateOptions, fn func(m *migrate.Migrator) error) error {
+	gdb, err := opts.DatabaseOpts.NewClient()
+	if err != nil {
+		return err
+	}
+	defer closeDB(gdb)
+
+	m, err := migrations.NewMigrator(gdb)
+	if err != nil {
+		return err
+	}
+
+	return fn(m)
+}
+
+func runMigrate(opts *options.MigrateOptions,
+	fn func(ctx context.Context, m *migrate.Migrator, args []string) ([]migrate.Migration, error)) app.RunCommandFunc {
+	return func(args []string) error {
+		return withMigrator(opts, func(m *migrate.Migrator) error {
+			done, err := fn(context.Background(), m, args)
+			for _, mg := range done {
+				fmt.Printf("%04d %s\n", mg.Version, mg.Name)
+			}
+			if err == nil && len(done) == 0 {
+				fmt.Println("no change")
+			}
+
+			return err
+		})
+	}
+}
+
+func migrateStatus(opts *options.MigrateOptions) app.RunCommandFunc {
+	return func([]string) error {
+		return withMigrator(opts, func(m *migrate.Migrator) error {
+			statuses, err := m.Status(context.Background())
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+			for _, s := range statuses {
+				appliedAt := "pending"
+				if s.Applied {
+					appliedAt = s.AppliedAt.Local().Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+			}
+
+			return w.Flush()
+		})
+	}
+}
+
+// migrateOnStartup 在启动时执行未执行的迁移
+func migrateOnStartup(gdb *gorm.DB) error {
+	m, err := migrations.NewMigrator(gdb)
+	if err != nil {
+		return err
+	}
+	done, err := m.Up(context.Background())
+	if err != nil {
+		return err
+	}
+	log.Infof("applied %d database migration(s), schema version %d", len(done), m.Latest())
+
+	return nil
+}
+
+func closeDB(gdb *gorm.DB) {
+	_ = pkgdb.Close(gdb)
+}
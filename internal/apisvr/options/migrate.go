@@ -0,0 +1,47 @@
+package options
+
+import (
+	pkgoptions "github.com/ahang7/go-IAM/internal/pkg/options"
+	"github.com/ahang7/go-IAM/pkg/app"
+	"github.com/ahang7/go-IAM/pkg/log"
+)
+
+// MigrateOptions migrate 命令的配置，与 apisvr 读取同一个配置文件中的 database、log 配置段
+type MigrateOptions struct {
+	DatabaseOpts *pkgoptions.DatabaseOptions `json:"database" mapstructure:"database"`
+	Log          *log.Options                `json:"log" mapstructure:"log"`
+}
+
+var (
+	_ app.FlagsOptions    = (*MigrateOptions)(nil)
+	_ app.LoggableOptions = (*MigrateOptions)(nil)
+)
+
+// NewMigrateOptions 创建默认的 migrate 命令配置
+func NewMigrateOptions() *MigrateOptions {
+	return &MigrateOptions{
+		DatabaseOpts: pkgoptions.NewDatabaseOptions(),
+		Log:          log.NewOptions(),
+	}
+}
+
+func (o *MigrateOptions) Flags() (fs app.FlagSet) {
+	o.DatabaseOpts.AddFlags(fs.Flags("database"))
+	o.Log.AddFlags(fs.Flags("log"))
+
+	return
+}
+
+func (o *MigrateOptions) Validate() []error {
+	errs := []error{}
+
+	errs = append(errs, o.DatabaseOpts.Validate()...)
+	errs = append(errs, o.Log.Validate()...)
+
+	return errs
+}
+
+// LogOptions 返回日志配置
+func (o *MigrateOptions) LogOptions() *log.Options {
+	return o.Log
+}
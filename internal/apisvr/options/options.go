@@ -2,42 +2,130 @@ package options
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	pkgoptions "github.com/ahang7/go-IAM/internal/pkg/options"
 	"github.com/ahang7/go-IAM/pkg/app"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/ahang7/go-IAM/pkg/secret"
+	"github.com/ahang7/go-IAM/pkg/tracing"
+	"github.com/spf13/viper"
 )
 
 type Options struct {
-	GenericServerRunOptions *pkgoptions.ServerRunOptions `json:"server" mapstructure:"server"`
-	MySQLOpts               *pkgoptions.MySQLOptions     `json:"mysql" mapstructure:"mysql"`
+	GenericServerRunOptions *pkgoptions.ServerRunOptions       `json:"server" mapstructure:"server"`
+	InsecureServing         *pkgoptions.InsecureServingOptions `json:"insecure" mapstructure:"insecure"`
+	SecureServing           *pkgoptions.SecureServingOptions   `json:"secure" mapstructure:"secure"`
+	GRPCOptions             *pkgoptions.GRPCOptions            `json:"grpc" mapstructure:"grpc"`
+	JwtOptions              *pkgoptions.JWTOptions             `json:"jwt" mapstructure:"jwt"`
+	MiddlewareOptions       *pkgoptions.MiddlewareOptions      `json:"middleware" mapstructure:"middleware"`
+	DatabaseOpts            *pkgoptions.DatabaseOptions        `json:"database" mapstructure:"database"`
+	Log                     *log.Options                       `json:"log" mapstructure:"log"`
+	Tracing                 *tracing.Options                   `json:"tracing" mapstructure:"tracing"`
+	// MySQLOptions 已弃用的 mysql 配置段，Complete 时合并到 DatabaseOpts
+	MySQLOptions *pkgoptions.MySQLOptions `json:"-" mapstructure:"mysql"`
 }
 
+// Complete 规范化配置：去除中间件名称两端的空白及空名称，服务器模式转为小写，
+// 已弃用的 mysql 配置合并到 database 配置中
 func (o *Options) Complete() error {
+	o.GenericServerRunOptions.Mode = strings.ToLower(strings.TrimSpace(o.GenericServerRunOptions.Mode))
 
-	return nil
+	middlewares := make([]string, 0, len(o.GenericServerRunOptions.Middlewares))
+	for _, m := range o.GenericServerRunOptions.Middlewares {
+		if m = strings.TrimSpace(m); m != "" {
+			middlewares = append(middlewares, m)
+		}
+	}
+	o.GenericServerRunOptions.Middlewares = middlewares
+
+	warnings, err := o.MySQLOptions.ApplyTo(o.DatabaseOpts, viper.InConfig)
+	for _, w := range warnings {
+		log.Warn(w)
+	}
+
+	return err
 }
 
+// String 返回 JSON 格式的配置，标签为 `log:"redact"` 的字段及由密钥引用解析出的密钥会被隐藏
 func (o *Options) String() string {
-	data, _ := json.Marshal(o)
+	data, _ := json.Marshal(log.Redact(o))
 
-	return string(data)
+	return secret.Redact(string(data))
 }
 
+// ApplyFlags 校验跨配置段的约束：HTTP、HTTPS 和 gRPC 不能监听同一个端口
 func (o *Options) ApplyFlags() []error {
-	return nil
+	var errs []error
+
+	listeners := []struct {
+		flag string
+		port int
+	}{
+		{"insecure.bind-port", o.InsecureServing.BindPort},
+		{"secure.bind-port", o.SecureServing.BindPort},
+		{"grpc.bind-port", o.GRPCOptions.BindPort},
+	}
+	used := make(map[int]string, len(listeners))
+	for _, l := range listeners {
+		if l.port == 0 {
+			continue
+		}
+		if flag, ok := used[l.port]; ok {
+			errs = append(errs, fmt.Errorf("--%s %d conflicts with --%s", l.flag, l.port, flag))
+
+			continue
+		}
+		used[l.port] = l.flag
+	}
+
+	return errs
 }
 
 func (o *Options) Flags() (fs app.FlagSet) {
-	o.MySQLOpts.AddFlags(fs.Flags("mysql"))
+	o.GenericServerRunOptions.AddFlags(fs.Flags("server"))
+	o.InsecureServing.AddFlags(fs.Flags("insecure serving"))
+	o.SecureServing.AddFlags(fs.Flags("secure serving"))
+	o.GRPCOptions.AddFlags(fs.Flags("grpc"))
+	o.JwtOptions.AddFlags(fs.Flags("jwt"))
+	o.MiddlewareOptions.AddFlags(fs.Flags("middleware"))
+	o.DatabaseOpts.AddFlags(fs.Flags("database"))
+	o.MySQLOptions.AddFlags(fs.Flags("database"))
+	o.Log.AddFlags(fs.Flags("log"))
+	o.Tracing.AddFlags(fs.Flags("tracing"))
 
 	return
 }
 
-var _ app.OptionsIntf = (*Options)(nil)
+// LogOptions 返回日志配置，App 在运行前使用该配置初始化日志
+func (o *Options) LogOptions() *log.Options {
+	return o.Log
+}
+
+// TracingOptions 返回链路追踪配置，App 在运行前使用该配置初始化链路追踪
+func (o *Options) TracingOptions() *tracing.Options {
+	return o.Tracing
+}
+
+var (
+	_ app.OptionsIntf      = (*Options)(nil)
+	_ app.LoggableOptions  = (*Options)(nil)
+	_ app.TraceableOptions = (*Options)(nil)
+)
 
 func NewOptions() *Options {
 	o := &Options{
-		MySQLOpts: pkgoptions.NewMySQLOptionsNil(),
+		GenericServerRunOptions: pkgoptions.NewServerRunOptions(),
+		InsecureServing:         pkgoptions.NewInsecureServingOptions(),
+		SecureServing:           pkgoptions.NewSecureServingOptions(),
+		GRPCOptions:             pkgoptions.NewGRPCOptions(),
+		JwtOptions:              pkgoptions.NewJWTOptions(),
+		MiddlewareOptions:       pkgoptions.NewMiddlewareOptions(),
+		DatabaseOpts:            pkgoptions.NewDatabaseOptions(),
+		MySQLOptions:            pkgoptions.NewMySQLOptions(),
+		Log:                     log.NewOptions(),
+		Tracing:                 tracing.NewOptions(),
 	}
 	return o
 }
@@ -3,5 +3,15 @@ package options
 func (o *Options) Validate() []error {
 	errs := []error{}
 
+	errs = append(errs, o.GenericServerRunOptions.Validate()...)
+	errs = append(errs, o.InsecureServing.Validate()...)
+	errs = append(errs, o.SecureServing.Validate()...)
+	errs = append(errs, o.GRPCOptions.Validate()...)
+	errs = append(errs, o.JwtOptions.Validate()...)
+	errs = append(errs, o.MiddlewareOptions.Validate()...)
+	errs = append(errs, o.DatabaseOpts.Validate()...)
+	errs = append(errs, o.Log.Validate()...)
+	errs = append(errs, o.Tracing.Validate()...)
+
 	return errs
 }
@@ -1,32 +1,56 @@
 package apisvr
 
 import (
+	"net/http"
+
+	"github.com/ahang7/go-IAM/api/openapi"
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
 	"github.com/ahang7/go-IAM/internal/pkg/code"
-	"github.com/ahang7/go-IAM/internal/pkg/middleware/auth"
+	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	"github.com/ahang7/go-IAM/internal/pkg/server"
 	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
 	"github.com/ahang7/go-IAM/pkg/errors"
 	"github.com/gin-gonic/gin"
 )
 
-func router(g *gin.Engine) {
+func router(g *gin.Engine, jwtInfo *server.JWTInfo, s store.Factory, services []apiService) error {
 	installMiddleware(g)
-	restController(g)
+
+	return restController(g, jwtInfo, s, services)
 }
 
 func installMiddleware(g *gin.Engine) {}
 
-func restController(g *gin.Engine) {
+func restController(g *gin.Engine, jwtInfo *server.JWTInfo, s store.Factory, services []apiService) error {
 	// Middlewares
-	strategy := newJWTAuth().(auth.JWTStrategy)
+	strategy, err := newJWTAuth(jwtInfo, s)
+	if err != nil {
+		return err
+	}
 	g.POST("/login", strategy.LoginHandler)
 	g.POST("/logout", strategy.LogoutHandler)
 	g.POST("/refresh", strategy.RefreshHandler)
 
-	auto := newAutoAuth()
+	// 由 proto 生成的 OpenAPI 文档
+	g.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", openapi.Spec)
+	})
+
+	auto, err := newAutoAuth(jwtInfo, s)
+	if err != nil {
+		return err
+	}
+	// v1 REST 接口由 api/iam/v1 中的 HTTP 注解生成，认证之后按用户限流
+	if err := registerRESTServices(g.Group("", auto.AuthExecute(), middleware.RateLimitByUser()), services); err != nil {
+		return err
+	}
+
 	g.NoRoute(auto.AuthExecute(), func(ctx *gin.Context) {
 		httpcore.WriteResponse(ctx,
 			errors.WithCode(code.ErrPageNotFound, "page not found"),
 			nil,
 		)
 	})
+
+	return nil
 }
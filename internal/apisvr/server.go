@@ -0,0 +1,119 @@
+package apisvr
+
+import (
+	"github.com/ahang7/go-IAM/internal/apisvr/options"
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/apisvr/store/mysql"
+	"github.com/ahang7/go-IAM/internal/pkg/metrics"
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+	"github.com/ahang7/go-IAM/pkg/app"
+	pkgdb "github.com/ahang7/go-IAM/pkg/db"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+)
+
+type apiServer struct {
+	genericServer *server.GenericServer
+	store         store.Factory
+}
+
+type preparedAPIServer struct {
+	*apiServer
+}
+
+func createAPIServer(opts *options.Options) (*apiServer, error) {
+	cfg, err := buildGenericConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := opts.DatabaseOpts.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	if opts.DatabaseOpts.MigrateOnStartup {
+		if err := migrateOnStartup(db); err != nil {
+			closeDB(db)
+
+			return nil, err
+		}
+	}
+	if cfg.EnableMetrics {
+		collector, err := pkgdb.NewStatsCollector(db, opts.DatabaseOpts.Database)
+		if err != nil {
+			closeDB(db)
+
+			return nil, err
+		}
+		metrics.Register(collector)
+	}
+	storeIns := mysql.NewFactory(db)
+	cfg.ReadinessChecks = append(cfg.ReadinessChecks, server.ReadinessCheck{Name: "database", Check: storeIns.Ping})
+	if err := installGRPCInterceptors(cfg, storeIns); err != nil {
+		_ = storeIns.Close()
+		return nil, err
+	}
+	if err := installAdminAuth(cfg, storeIns); err != nil {
+		_ = storeIns.Close()
+		return nil, err
+	}
+
+	genericServer, err := cfg.Complete().NewServer()
+	if err != nil {
+		_ = storeIns.Close()
+		return nil, err
+	}
+
+	return &apiServer{genericServer: genericServer, store: storeIns}, nil
+}
+
+// PrepareRun 安装 REST 路由并注册 gRPC 服务
+func (s *apiServer) PrepareRun() (preparedAPIServer, error) {
+	services := newAPIServices(s.store)
+	err := s.genericServer.InstallRoutes(func(g *gin.Engine) error {
+		return router(g, s.genericServer.JWT, s.store, services)
+	})
+	if err != nil {
+		return preparedAPIServer{}, err
+	}
+	if srv := s.genericServer.GRPCServer(); srv != nil {
+		registerGRPCServices(srv, services)
+	}
+
+	return preparedAPIServer{s}, nil
+}
+
+// reloadableSections 可以热加载的配置段
+var reloadableSections = []string{"server.middlewares", "middleware"}
+
+// reload 热加载中间件列表及中间件配置
+func (s preparedAPIServer) reload(o app.FlagsOptions) error {
+	opts := o.(*options.Options)
+	mwConfig := opts.MiddlewareOptions.Config
+
+	return s.genericServer.ReloadMiddlewares(opts.GenericServerRunOptions.Middlewares, &mwConfig)
+}
+
+// logLevelSections 可以热加载的日志级别配置
+var logLevelSections = []string{"log.level", "log.named-levels"}
+
+// reloadLogLevels 热加载日志级别，通过管理接口设置的临时级别会被取消
+func reloadLogLevels(o app.FlagsOptions) error {
+	return o.(*options.Options).Log.ApplyLevels()
+}
+
+// Run 启动服务，stopCh 关闭时优雅关闭服务
+func (s preparedAPIServer) Run(stopCh <-chan struct{}) error {
+	go func() {
+		<-stopCh
+		log.Info("shutting down api server")
+		if err := s.genericServer.Shutdown(); err != nil {
+			log.Errorf("failed to shutdown api server: %s", err.Error())
+		}
+		if err := s.store.Close(); err != nil {
+			log.Errorf("failed to close store: %s", err.Error())
+		}
+	}()
+
+	return s.genericServer.Run()
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/pkg/authz"
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	"github.com/ahang7/go-IAM/pkg/errors"
+)
+
+// AuthzService 实现 v1.AuthzServiceServer
+type AuthzService struct {
+	v1.UnimplementedAuthzServiceServer
+
+	authorizer *authz.Authorizer
+}
+
+var _ v1.AuthzServiceServer = (*AuthzService)(nil)
+
+// NewAuthzService 创建授权服务，policies 通常为 NewPolicyGetter 外包装的 authz.Cache
+func NewAuthzService(policies authz.PolicyGetter) *AuthzService {
+	return &AuthzService{authorizer: authz.NewAuthorizer(policies)}
+}
+
+func (a *AuthzService) Authorize(ctx context.Context, req *v1.AuthorizeRequest) (*v1.AuthorizeResponse, error) {
+	if req.GetSubject() == "" || req.GetResource() == "" || req.GetAction() == "" {
+		return nil, errors.WithCode(code.ErrValidation, "subject, resource and action are required")
+	}
+
+	decision, err := a.authorizer.Authorize(ctx, middleware.UsernameFrom(ctx), &authz.Request{
+		Subject:  req.GetSubject(),
+		Resource: req.GetResource(),
+		Action:   req.GetAction(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.AuthorizeResponse{
+		Allowed: decision.Allowed,
+		Effect:  decision.Effect,
+		Policy:  decision.Policy,
+		Reason:  decision.Reason,
+	}, nil
+}
+
+// policyGetter 从 store 中获取用户的全部策略
+type policyGetter struct {
+	store store.Factory
+}
+
+// NewPolicyGetter 返回从 store 中获取用户全部策略的 authz.PolicyGetter
+func NewPolicyGetter(s store.Factory) authz.PolicyGetter {
+	return policyGetter{store: s}
+}
+
+func (g policyGetter) GetPolicies(ctx context.Context, username string) ([]*model.Policy, error) {
+	policies, err := g.store.Policies().List(ctx, username, model.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return policies.Items, nil
+}
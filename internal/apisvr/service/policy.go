@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/pkg/authz"
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// PolicyService 实现 v1.PolicyServiceServer
+type PolicyService struct {
+	v1.UnimplementedPolicyServiceServer
+
+	store store.Factory
+	// cache 授权判定使用的策略缓存，修改策略后删除对应用户的缓存
+	cache *authz.Cache
+}
+
+var _ v1.PolicyServiceServer = (*PolicyService)(nil)
+
+// NewPolicyService 创建授权策略服务
+func NewPolicyService(s store.Factory, cache *authz.Cache) *PolicyService {
+	return &PolicyService{store: s, cache: cache}
+}
+
+func (p *PolicyService) CreatePolicy(ctx context.Context, req *v1.CreatePolicyRequest) (*v1.Policy, error) {
+	username, err := resolveOwner(ctx, p.store, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	if err := requireName(req.GetName()); err != nil {
+		return nil, err
+	}
+	statement, err := fromStatement(req.GetStatement())
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &model.Policy{
+		ObjectMeta: model.ObjectMeta{Name: req.GetName()},
+		Username:   username,
+		Statement:  statement,
+	}
+	if err := p.store.Policies().Create(ctx, policy); err != nil {
+		return nil, err
+	}
+	p.cache.Invalidate(username)
+
+	return toPolicy(policy), nil
+}
+
+func (p *PolicyService) UpdatePolicy(ctx context.Context, req *v1.UpdatePolicyRequest) (*v1.Policy, error) {
+	username, err := resolveOwner(ctx, p.store, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	statement, err := fromStatement(req.GetPolicy().GetStatement())
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := p.store.Policies().Get(ctx, username, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	policy.Statement = statement
+	if err := p.store.Policies().Update(ctx, policy); err != nil {
+		return nil, err
+	}
+	p.cache.Invalidate(username)
+
+	return toPolicy(policy), nil
+}
+
+func (p *PolicyService) DeletePolicy(ctx context.Context, req *v1.DeletePolicyRequest) (*emptypb.Empty, error) {
+	username, err := resolveOwner(ctx, p.store, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.store.Policies().Delete(ctx, username, req.GetName()); err != nil {
+		return nil, err
+	}
+	p.cache.Invalidate(username)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *PolicyService) GetPolicy(ctx context.Context, req *v1.GetPolicyRequest) (*v1.Policy, error) {
+	username, err := resolveOwner(ctx, p.store, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := p.store.Policies().Get(ctx, username, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	return toPolicy(policy), nil
+}
+
+func (p *PolicyService) ListPolicies(ctx context.Context, req *v1.ListPoliciesRequest) (*v1.ListPoliciesResponse, error) {
+	username, err := resolveOwner(ctx, p.store, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := p.store.Policies().List(ctx, username, listOptions(req.GetOffset(), req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1.ListPoliciesResponse{
+		TotalCount: policies.TotalCount,
+		Items:      make([]*v1.Policy, 0, len(policies.Items)),
+	}
+	for _, policy := range policies.Items {
+		resp.Items = append(resp.Items, toPolicy(policy))
+	}
+
+	return resp, nil
+}
+
+// fromStatement 校验并转换策略内容
+func fromStatement(s *v1.PolicyStatement) (model.PolicyStatement, error) {
+	if s == nil {
+		return model.PolicyStatement{}, errors.WithCode(code.ErrValidation, "statement cannot be empty")
+	}
+	if s.GetEffect() != model.AllowAccess && s.GetEffect() != model.DenyAccess {
+		return model.PolicyStatement{}, errors.WithCode(code.ErrValidation,
+			"effect must be %s or %s, got %q", model.AllowAccess, model.DenyAccess, s.GetEffect())
+	}
+	if len(s.GetResources()) == 0 || len(s.GetActions()) == 0 {
+		return model.PolicyStatement{}, errors.WithCode(code.ErrValidation, "resources and actions cannot be empty")
+	}
+
+	return model.PolicyStatement{
+		Description: s.GetDescription(),
+		Effect:      s.GetEffect(),
+		Subjects:    s.GetSubjects(),
+		Resources:   s.GetResources(),
+		Actions:     s.GetActions(),
+	}, nil
+}
+
+func toPolicy(p *model.Policy) *v1.Policy {
+	created, updated := timestamp(p.ObjectMeta)
+
+	return &v1.Policy{
+		Id:       p.ID,
+		Name:     p.Name,
+		Username: p.Username,
+		Statement: &v1.PolicyStatement{
+			Description: p.Statement.Description,
+			Effect:      p.Statement.Effect,
+			Subjects:    p.Statement.Subjects,
+			Resources:   p.Statement.Resources,
+			Actions:     p.Statement.Actions,
+		},
+		CreatedAt: created,
+		UpdatedAt: updated,
+	}
+}
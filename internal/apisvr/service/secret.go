@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// maxSecretCount 每个用户最多拥有的密钥数量
+const maxSecretCount = 10
+
+// SecretService 实现 v1.SecretServiceServer
+type SecretService struct {
+	v1.UnimplementedSecretServiceServer
+
+	store store.Factory
+}
+
+var _ v1.SecretServiceServer = (*SecretService)(nil)
+
+// NewSecretService 创建密钥服务
+func NewSecretService(s store.Factory) *SecretService {
+	return &SecretService{store: s}
+}
+
+func (s *SecretService) CreateSecret(ctx context.Context, req *v1.CreateSecretRequest) (*v1.Secret, error) {
+	username, err := resolveOwner(ctx, s.store, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	if err := requireName(req.GetName()); err != nil {
+		return nil, err
+	}
+
+	secrets, err := s.store.Secrets().List(ctx, username, model.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if secrets.TotalCount >= maxSecretCount {
+		return nil, errors.WithCode(code.ErrReachMaxCount, "user %s already has %d secrets", username, secrets.TotalCount)
+	}
+
+	secretID, err := randomString(18)
+	if err != nil {
+		return nil, errors.WrapC(err, code.ErrUnknown, "generate secret id")
+	}
+	secretKey, err := randomString(16)
+	if err != nil {
+		return nil, errors.WrapC(err, code.ErrUnknown, "generate secret key")
+	}
+
+	secret := &model.Secret{
+		ObjectMeta:  model.ObjectMeta{Name: req.GetName()},
+		Username:    username,
+		SecretID:    secretID,
+		SecretKey:   secretKey,
+		Expires:     req.GetExpires(),
+		Description: req.GetDescription(),
+	}
+	if err := s.store.Secrets().Create(ctx, secret); err != nil {
+		return nil, err
+	}
+
+	return toSecret(secret), nil
+}
+
+func (s *SecretService) UpdateSecret(ctx context.Context, req *v1.UpdateSecretRequest) (*v1.Secret, error) {
+	username, err := resolveOwner(ctx, s.store, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := s.store.Secrets().Get(ctx, username, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	secret.Expires = req.GetSecret().GetExpires()
+	secret.Description = req.GetSecret().GetDescription()
+	if err := s.store.Secrets().Update(ctx, secret); err != nil {
+		return nil, err
+	}
+
+	return toSecret(secret), nil
+}
+
+func (s *SecretService) DeleteSecret(ctx context.Context, req *v1.DeleteSecretRequest) (*emptypb.Empty, error) {
+	username, err := resolveOwner(ctx, s.store, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Secrets().Delete(ctx, username, req.GetName()); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *SecretService) GetSecret(ctx context.Context, req *v1.GetSecretRequest) (*v1.Secret, error) {
+	username, err := resolveOwner(ctx, s.store, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := s.store.Secrets().Get(ctx, username, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	return toSecret(secret), nil
+}
+
+func (s *SecretService) ListSecrets(ctx context.Context, req *v1.ListSecretsRequest) (*v1.ListSecretsResponse, error) {
+	username, err := resolveOwner(ctx, s.store, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := s.store.Secrets().List(ctx, username, listOptions(req.GetOffset(), req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1.ListSecretsResponse{
+		TotalCount: secrets.TotalCount,
+		Items:      make([]*v1.Secret, 0, len(secrets.Items)),
+	}
+	for _, secret := range secrets.Items {
+		resp.Items = append(resp.Items, toSecret(secret))
+	}
+
+	return resp, nil
+}
+
+func toSecret(s *model.Secret) *v1.Secret {
+	created, updated := timestamp(s.ObjectMeta)
+
+	return &v1.Secret{
+		Id:          s.ID,
+		Name:        s.Name,
+		Username:    s.Username,
+		SecretId:    s.SecretID,
+		SecretKey:   s.SecretKey,
+		Expires:     s.Expires,
+		Description: s.Description,
+		CreatedAt:   created,
+		UpdatedAt:   updated,
+	}
+}
@@ -0,0 +1,95 @@
+// Package service 实现 api/iam/v1 中定义的 gRPC 服务
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// currentUser 返回当前认证的用户
+func currentUser(ctx context.Context, s store.Factory) (*model.User, error) {
+	username := middleware.UsernameFrom(ctx)
+	if username == "" {
+		return nil, errors.WithCode(code.ErrTokenInvalid, "unauthenticated request")
+	}
+
+	return s.Users().Get(ctx, username)
+}
+
+// requireAdmin 校验当前用户是否为管理员
+func requireAdmin(ctx context.Context, s store.Factory) error {
+	user, err := currentUser(ctx, s)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.WithCode(code.ErrPermissionDenied, "user %s is not an administrator", user.Name)
+	}
+
+	return nil
+}
+
+// authorizeOwner 校验当前用户是否可以访问 owner 的资源：只有管理员可以访问其他用户的资源
+func authorizeOwner(ctx context.Context, s store.Factory, owner string) error {
+	user, err := currentUser(ctx, s)
+	if err != nil {
+		return err
+	}
+	if user.IsAdmin || user.Name == owner {
+		return nil
+	}
+
+	return errors.WithCode(code.ErrPermissionDenied, "user %s cannot access resources of %s", user.Name, owner)
+}
+
+// resolveOwner 返回资源所属的用户，username 为空时为当前认证用户，并校验访问权限
+func resolveOwner(ctx context.Context, s store.Factory, username string) (string, error) {
+	if username == "" {
+		username = middleware.UsernameFrom(ctx)
+	}
+	if err := authorizeOwner(ctx, s, username); err != nil {
+		return "", err
+	}
+
+	return username, nil
+}
+
+// requireName 校验资源名称不能为空
+func requireName(name string) error {
+	if name == "" {
+		return errors.WithCode(code.ErrValidation, "name cannot be empty")
+	}
+
+	return nil
+}
+
+func listOptions(offset, limit int64) model.ListOptions {
+	opts := model.ListOptions{Offset: &offset}
+	if limit > 0 {
+		opts.Limit = &limit
+	}
+
+	return opts
+}
+
+func timestamp(m model.ObjectMeta) (created, updated *timestamppb.Timestamp) {
+	return timestamppb.New(m.CreatedAt), timestamppb.New(m.UpdatedAt)
+}
+
+// randomString 返回长度为 2*n 的随机十六进制字符串
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
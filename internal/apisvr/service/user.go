@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// UserService 实现 v1.UserServiceServer
+type UserService struct {
+	v1.UnimplementedUserServiceServer
+
+	store store.Factory
+}
+
+var _ v1.UserServiceServer = (*UserService)(nil)
+
+// NewUserService 创建用户服务
+func NewUserService(s store.Factory) *UserService {
+	return &UserService{store: s}
+}
+
+func (u *UserService) CreateUser(ctx context.Context, req *v1.CreateUserRequest) (*v1.User, error) {
+	if err := requireAdmin(ctx, u.store); err != nil {
+		return nil, err
+	}
+	if err := requireName(req.GetName()); err != nil {
+		return nil, err
+	}
+	if req.GetPassword() == "" {
+		return nil, errors.WithCode(code.ErrValidation, "password cannot be empty")
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.GetPassword()), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.WrapC(err, code.ErrEncrypt, "encrypt password of user %s", req.GetName())
+	}
+
+	user := &model.User{
+		ObjectMeta: model.ObjectMeta{Name: req.GetName()},
+		Status:     1,
+		Nickname:   req.GetNickname(),
+		Password:   string(hashed),
+		Email:      req.GetEmail(),
+		Phone:      req.GetPhone(),
+		IsAdmin:    req.GetIsAdmin(),
+	}
+	if err := u.store.Users().Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return toUser(user), nil
+}
+
+func (u *UserService) UpdateUser(ctx context.Context, req *v1.UpdateUserRequest) (*v1.User, error) {
+	current, err := currentUser(ctx, u.store)
+	if err != nil {
+		return nil, err
+	}
+	if !current.IsAdmin && current.Name != req.GetName() {
+		return nil, errors.WithCode(code.ErrPermissionDenied, "user %s cannot update user %s", current.Name, req.GetName())
+	}
+
+	user, err := u.store.Users().Get(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	in := req.GetUser()
+	user.Nickname = in.GetNickname()
+	user.Email = in.GetEmail()
+	user.Phone = in.GetPhone()
+	if current.IsAdmin {
+		user.IsAdmin = in.GetIsAdmin()
+	} else if in.GetIsAdmin() {
+		return nil, errors.WithCode(code.ErrPermissionDenied, "only administrators can grant administrator")
+	}
+	if err := u.store.Users().Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return toUser(user), nil
+}
+
+func (u *UserService) DeleteUser(ctx context.Context, req *v1.DeleteUserRequest) (*emptypb.Empty, error) {
+	if err := requireAdmin(ctx, u.store); err != nil {
+		return nil, err
+	}
+	if err := u.store.Users().Delete(ctx, req.GetName()); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (u *UserService) GetUser(ctx context.Context, req *v1.GetUserRequest) (*v1.User, error) {
+	if err := authorizeOwner(ctx, u.store, req.GetName()); err != nil {
+		return nil, err
+	}
+
+	user, err := u.store.Users().Get(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	return toUser(user), nil
+}
+
+func (u *UserService) ListUsers(ctx context.Context, req *v1.ListUsersRequest) (*v1.ListUsersResponse, error) {
+	if err := requireAdmin(ctx, u.store); err != nil {
+		return nil, err
+	}
+
+	users, err := u.store.Users().List(ctx, listOptions(req.GetOffset(), req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1.ListUsersResponse{
+		TotalCount: users.TotalCount,
+		Items:      make([]*v1.User, 0, len(users.Items)),
+	}
+	for _, user := range users.Items {
+		resp.Items = append(resp.Items, toUser(user))
+	}
+
+	return resp, nil
+}
+
+// toUser 转换为 API 对象，不包含密码
+func toUser(u *model.User) *v1.User {
+	created, updated := timestamp(u.ObjectMeta)
+
+	return &v1.User{
+		Id:        u.ID,
+		Name:      u.Name,
+		Nickname:  u.Nickname,
+		Email:     u.Email,
+		Phone:     u.Phone,
+		IsAdmin:   u.IsAdmin,
+		Status:    int32(u.Status),
+		CreatedAt: created,
+		UpdatedAt: updated,
+	}
+}
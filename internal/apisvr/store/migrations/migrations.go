@@ -0,0 +1,36 @@
+// Package migrations 嵌入 apisvr 的数据库迁移文件，每个驱动一个目录，文件命名规则见 pkg/db/migrate。
+// 新增迁移时需要为每个驱动添加相同版本及名称的文件
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"github.com/ahang7/go-IAM/pkg/db"
+	"github.com/ahang7/go-IAM/pkg/db/migrate"
+	"gorm.io/gorm"
+)
+
+//go:embed mysql postgres sqlite
+var files embed.FS
+
+// FS 返回 driver 的迁移文件
+func FS(driver string) (fs.FS, error) {
+	if !slices.Contains(db.Drivers(), driver) {
+		return nil, fmt.Errorf("no migrations for database driver %q", driver)
+	}
+
+	return fs.Sub(files, driver)
+}
+
+// NewMigrator 根据 gorm 连接的驱动创建 Migrator
+func NewMigrator(gdb *gorm.DB, opts ...migrate.Option) (*migrate.Migrator, error) {
+	fsys, err := FS(gdb.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.New(gdb, fsys, opts...)
+}
@@ -0,0 +1,15 @@
+CREATE TABLE `user` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(64) NOT NULL,
+  `createdAt` DATETIME(3) NULL,
+  `updatedAt` DATETIME(3) NULL,
+  `status` BIGINT NOT NULL DEFAULT 0,
+  `nickname` VARCHAR(30) NULL,
+  `password` VARCHAR(255) NOT NULL,
+  `email` VARCHAR(256) NULL,
+  `phone` VARCHAR(20) NULL,
+  `isAdmin` TINYINT(1) NOT NULL DEFAULT 0,
+  `loginedAt` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_user_name` (`name`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
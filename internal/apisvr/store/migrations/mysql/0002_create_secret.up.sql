@@ -0,0 +1,15 @@
+CREATE TABLE `secret` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(64) NOT NULL,
+  `createdAt` DATETIME(3) NULL,
+  `updatedAt` DATETIME(3) NULL,
+  `username` VARCHAR(64) NULL,
+  `secretID` VARCHAR(36) NULL,
+  `secretKey` VARCHAR(255) NULL,
+  `expires` BIGINT NOT NULL DEFAULT 0,
+  `description` VARCHAR(255) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_secret_name` (`name`),
+  UNIQUE KEY `idx_secret_secretID` (`secretID`),
+  KEY `idx_secret_username` (`username`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,11 @@
+CREATE TABLE `policy` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(64) NOT NULL,
+  `createdAt` DATETIME(3) NULL,
+  `updatedAt` DATETIME(3) NULL,
+  `username` VARCHAR(64) NULL,
+  `statement` TEXT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_policy_name` (`name`),
+  KEY `idx_policy_username` (`username`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
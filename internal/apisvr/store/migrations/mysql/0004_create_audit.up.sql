@@ -0,0 +1,16 @@
+-- 审计日志，记录对用户、密钥、策略的变更及授权决策
+CREATE TABLE `audit` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `createdAt` DATETIME(3) NOT NULL,
+  `requestID` VARCHAR(64) NULL,
+  `username` VARCHAR(64) NULL,
+  `clientIP` VARCHAR(64) NULL,
+  `action` VARCHAR(64) NOT NULL,
+  `resourceType` VARCHAR(32) NOT NULL,
+  `resource` VARCHAR(255) NULL,
+  `result` VARCHAR(16) NOT NULL,
+  `detail` TEXT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_audit_createdAt` (`createdAt`),
+  KEY `idx_audit_username` (`username`, `createdAt`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
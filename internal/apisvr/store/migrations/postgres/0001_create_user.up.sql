@@ -0,0 +1,14 @@
+CREATE TABLE "user" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" VARCHAR(64) NOT NULL,
+  "createdAt" TIMESTAMPTZ NULL,
+  "updatedAt" TIMESTAMPTZ NULL,
+  "status" BIGINT NOT NULL DEFAULT 0,
+  "nickname" VARCHAR(30) NULL,
+  "password" VARCHAR(255) NOT NULL,
+  "email" VARCHAR(256) NULL,
+  "phone" VARCHAR(20) NULL,
+  "isAdmin" BOOLEAN NOT NULL DEFAULT FALSE,
+  "loginedAt" TIMESTAMPTZ NULL
+);
+CREATE UNIQUE INDEX "idx_user_name" ON "user" ("name");
@@ -0,0 +1,14 @@
+CREATE TABLE "secret" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" VARCHAR(64) NOT NULL,
+  "createdAt" TIMESTAMPTZ NULL,
+  "updatedAt" TIMESTAMPTZ NULL,
+  "username" VARCHAR(64) NULL,
+  "secretID" VARCHAR(36) NULL,
+  "secretKey" VARCHAR(255) NULL,
+  "expires" BIGINT NOT NULL DEFAULT 0,
+  "description" VARCHAR(255) NULL
+);
+CREATE UNIQUE INDEX "idx_secret_name" ON "secret" ("name");
+CREATE UNIQUE INDEX "idx_secret_secretID" ON "secret" ("secretID");
+CREATE INDEX "idx_secret_username" ON "secret" ("username");
@@ -0,0 +1,10 @@
+CREATE TABLE "policy" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" VARCHAR(64) NOT NULL,
+  "createdAt" TIMESTAMPTZ NULL,
+  "updatedAt" TIMESTAMPTZ NULL,
+  "username" VARCHAR(64) NULL,
+  "statement" TEXT NULL
+);
+CREATE UNIQUE INDEX "idx_policy_name" ON "policy" ("name");
+CREATE INDEX "idx_policy_username" ON "policy" ("username");
@@ -0,0 +1,15 @@
+-- 审计日志，记录对用户、密钥、策略的变更及授权决策
+CREATE TABLE "audit" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "createdAt" TIMESTAMPTZ NOT NULL,
+  "requestID" VARCHAR(64) NULL,
+  "username" VARCHAR(64) NULL,
+  "clientIP" VARCHAR(64) NULL,
+  "action" VARCHAR(64) NOT NULL,
+  "resourceType" VARCHAR(32) NOT NULL,
+  "resource" VARCHAR(255) NULL,
+  "result" VARCHAR(16) NOT NULL,
+  "detail" TEXT NULL
+);
+CREATE INDEX "idx_audit_createdAt" ON "audit" ("createdAt");
+CREATE INDEX "idx_audit_username" ON "audit" ("username", "createdAt");
@@ -0,0 +1,14 @@
+CREATE TABLE `user` (
+  `id` INTEGER PRIMARY KEY AUTOINCREMENT,
+  `name` VARCHAR(64) NOT NULL,
+  `createdAt` DATETIME NULL,
+  `updatedAt` DATETIME NULL,
+  `status` INTEGER NOT NULL DEFAULT 0,
+  `nickname` VARCHAR(30) NULL,
+  `password` VARCHAR(255) NOT NULL,
+  `email` VARCHAR(256) NULL,
+  `phone` VARCHAR(20) NULL,
+  `isAdmin` NUMERIC NOT NULL DEFAULT 0,
+  `loginedAt` DATETIME NULL
+);
+CREATE UNIQUE INDEX `idx_user_name` ON `user` (`name`);
@@ -0,0 +1,54 @@
+// Package mysql 基于 gorm 实现 store.Factory，支持 pkg/db 的 MySQL、PostgreSQL 和 SQLite 驱动
+package mysql
+
+import (
+	"context"
+
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	pkgdb "github.com/ahang7/go-IAM/pkg/db"
+	"gorm.io/gorm"
+)
+
+type datastore struct {
+	db *gorm.DB
+}
+
+var _ store.Factory = (*datastore)(nil)
+
+// NewFactory 使用已经建立的 gorm 连接创建 store.Factory
+func NewFactory(db *gorm.DB) store.Factory {
+	return &datastore{db: db}
+}
+
+func (ds *datastore) Users() store.UserStore {
+	return newUsers(ds)
+}
+
+func (ds *datastore) Secrets() store.SecretStore {
+	return newSecrets(ds)
+}
+
+func (ds *datastore) Policies() store.PolicyStore {
+	return newPolicies(ds)
+}
+
+func (ds *datastore) Ping(ctx context.Context) error {
+	return pkgdb.Ping(ctx, ds.db)
+}
+
+func (ds *datastore) Close() error {
+	return pkgdb.Close(ds.db)
+}
+
+// paginate 根据分页参数设置 offset 和 limit，未指定 limit 时返回全部记录
+func paginate(db *gorm.DB, opts model.ListOptions) *gorm.DB {
+	if opts.Offset != nil {
+		db = db.Offset(int(*opts.Offset))
+	}
+	if opts.Limit != nil {
+		db = db.Limit(int(*opts.Limit))
+	}
+
+	return db
+}
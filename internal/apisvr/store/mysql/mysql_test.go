@@ -0,0 +1,128 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ahang7/go-IAM/internal/apisvr/store"
+	"github.com/ahang7/go-IAM/internal/apisvr/store/migrations"
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	pkgdb "github.com/ahang7/go-IAM/pkg/db"
+	"github.com/ahang7/go-IAM/pkg/errors"
+)
+
+// newTestFactory 创建使用 SQLite 内存数据库的 store.Factory，通过迁移创建表结构，测试结束后关闭
+func newTestFactory(t *testing.T) store.Factory {
+	t.Helper()
+
+	db, err := pkgdb.NewClient(&pkgdb.Options{Driver: pkgdb.DriverSQLite, Database: ":memory:", LogLevel: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	f := NewFactory(db)
+	t.Cleanup(func() { _ = f.Close() })
+
+	return f
+}
+
+func TestUsers(t *testing.T) {
+	ctx := context.Background()
+	users := newTestFactory(t).Users()
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		if err := users.Create(ctx, &model.User{ObjectMeta: model.ObjectMeta{Name: name}, Password: "hashed"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := users.Create(ctx, &model.User{ObjectMeta: model.ObjectMeta{Name: "alice"}, Password: "hashed"})
+	if !errors.IsCode(err, code.ErrUserAlreadyExist) {
+		t.Errorf("Create() duplicate = %v, want ErrUserAlreadyExist", err)
+	}
+
+	user, err := users.Get(ctx, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Nickname = "Bobby"
+	if err := users.Update(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	if user, err = users.Get(ctx, "bob"); err != nil || user.Nickname != "Bobby" {
+		t.Errorf("Get() after Update = %+v, %v", user, err)
+	}
+
+	offset, limit := int64(1), int64(1)
+	list, err := users.List(ctx, model.ListOptions{Offset: &offset, Limit: &limit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.TotalCount != 3 || len(list.Items) != 1 || list.Items[0].Name != "bob" {
+		t.Errorf("List() = %d %v, want 3 [bob]", list.TotalCount, list.Items)
+	}
+
+	if err := users.Delete(ctx, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.Get(ctx, "bob"); !errors.IsCode(err, code.ErrUserNotFound) {
+		t.Errorf("Get() after Delete = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestSecrets(t *testing.T) {
+	ctx := context.Background()
+	secrets := newTestFactory(t).Secrets()
+
+	for _, s := range []*model.Secret{
+		{ObjectMeta: model.ObjectMeta{Name: "ci"}, Username: "alice", SecretID: "id-1", SecretKey: "key-1"},
+		{ObjectMeta: model.ObjectMeta{Name: "deploy"}, Username: "bob", SecretID: "id-2", SecretKey: "key-2"},
+	} {
+		if err := secrets.Create(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	secret, err := secrets.GetBySecretID(ctx, "id-2")
+	if err != nil || secret.Name != "deploy" {
+		t.Errorf("GetBySecretID() = %+v, %v", secret, err)
+	}
+	if _, err := secrets.Get(ctx, "alice", "deploy"); !errors.IsCode(err, code.ErrSecretNotFound) {
+		t.Errorf("Get() of another user = %v, want ErrSecretNotFound", err)
+	}
+	list, err := secrets.List(ctx, "alice", model.ListOptions{})
+	if err != nil || list.TotalCount != 1 || list.Items[0].SecretID != "id-1" {
+		t.Errorf("List() = %+v, %v", list, err)
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	ctx := context.Background()
+	policies := newTestFactory(t).Policies()
+
+	policy := &model.Policy{ObjectMeta: model.ObjectMeta{Name: "read"}, Username: "alice"}
+	policy.Statement.Effect = "allow"
+	if err := policies.Create(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := policies.Get(ctx, "alice", "read")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Statement.Effect != "allow" {
+		t.Errorf("Statement = %+v, want the stored statement", got.Statement)
+	}
+	if err := policies.Delete(ctx, "alice", "read"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := policies.Get(ctx, "alice", "read"); !errors.IsCode(err, code.ErrPolicyNotFound) {
+		t.Errorf("Get() after Delete = %v, want ErrPolicyNotFound", err)
+	}
+}
@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type policies struct {
+	db *gorm.DB
+}
+
+func newPolicies(ds *datastore) *policies {
+	return &policies{db: ds.db}
+}
+
+func (p *policies) Create(ctx context.Context, policy *model.Policy) error {
+	if err := p.db.WithContext(ctx).Create(policy).Error; err != nil {
+		return errors.WrapC(err, code.ErrDatabase, "create policy %s", policy.Name)
+	}
+
+	return nil
+}
+
+func (p *policies) Update(ctx context.Context, policy *model.Policy) error {
+	if err := p.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return errors.WrapC(err, code.ErrDatabase, "update policy %s", policy.Name)
+	}
+
+	return nil
+}
+
+func (p *policies) Delete(ctx context.Context, username, name string) error {
+	err := p.db.WithContext(ctx).Where("username = ? and name = ?", username, name).Delete(&model.Policy{}).Error
+	if err != nil {
+		return errors.WrapC(err, code.ErrDatabase, "delete policy %s", name)
+	}
+
+	return nil
+}
+
+func (p *policies) Get(ctx context.Context, username, name string) (*model.Policy, error) {
+	policy := &model.Policy{}
+	err := p.db.WithContext(ctx).Where("username = ? and name = ?", username, name).First(policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrPolicyNotFound, "policy %s not found", name)
+		}
+
+		return nil, errors.WrapC(err, code.ErrDatabase, "get policy %s", name)
+	}
+
+	return policy, nil
+}
+
+func (p *policies) List(ctx context.Context, username string, opts model.ListOptions) (*model.PolicyList, error) {
+	ret := &model.PolicyList{}
+	db := p.db.WithContext(ctx).Model(&model.Policy{})
+	if username != "" {
+		db = db.Where("username = ?", username)
+	}
+	if err := db.Count(&ret.TotalCount).Error; err != nil {
+		return nil, errors.WrapC(err, code.ErrDatabase, "count policies")
+	}
+	if err := paginate(db, opts).Order("id desc").Find(&ret.Items).Error; err != nil {
+		return nil, errors.WrapC(err, code.ErrDatabase, "list policies")
+	}
+
+	return ret, nil
+}
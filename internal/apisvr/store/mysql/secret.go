@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type secrets struct {
+	db *gorm.DB
+}
+
+func newSecrets(ds *datastore) *secrets {
+	return &secrets{db: ds.db}
+}
+
+func (s *secrets) Create(ctx context.Context, secret *model.Secret) error {
+	if err := s.db.WithContext(ctx).Create(secret).Error; err != nil {
+		return errors.WrapC(err, code.ErrDatabase, "create secret %s", secret.Name)
+	}
+
+	return nil
+}
+
+func (s *secrets) Update(ctx context.Context, secret *model.Secret) error {
+	if err := s.db.WithContext(ctx).Save(secret).Error; err != nil {
+		return errors.WrapC(err, code.ErrDatabase, "update secret %s", secret.Name)
+	}
+
+	return nil
+}
+
+func (s *secrets) Delete(ctx context.Context, username, name string) error {
+	err := s.db.WithContext(ctx).Where("username = ? and name = ?", username, name).Delete(&model.Secret{}).Error
+	if err != nil {
+		return errors.WrapC(err, code.ErrDatabase, "delete secret %s", name)
+	}
+
+	return nil
+}
+
+func (s *secrets) Get(ctx context.Context, username, name string) (*model.Secret, error) {
+	return s.first(ctx, map[string]any{"username": username, "name": name})
+}
+
+func (s *secrets) GetBySecretID(ctx context.Context, secretID string) (*model.Secret, error) {
+	return s.first(ctx, map[string]any{"secretID": secretID})
+}
+
+// first 按 conds 查询第一个密钥，使用 map 作为条件时 gorm 会为列名加引号，
+// 使 secretID 这样的大小写混合的列名在 PostgreSQL 中也能正确匹配
+func (s *secrets) first(ctx context.Context, conds map[string]any) (*model.Secret, error) {
+	secret := &model.Secret{}
+	err := s.db.WithContext(ctx).Where(conds).First(secret).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrSecretNotFound, "secret not found")
+		}
+
+		return nil, errors.WrapC(err, code.ErrDatabase, "get secret")
+	}
+
+	return secret, nil
+}
+
+func (s *secrets) List(ctx context.Context, username string, opts model.ListOptions) (*model.SecretList, error) {
+	ret := &model.SecretList{}
+	db := s.db.WithContext(ctx).Model(&model.Secret{})
+	if username != "" {
+		db = db.Where("username = ?", username)
+	}
+	if err := db.Count(&ret.TotalCount).Error; err != nil {
+		return nil, errors.WrapC(err, code.ErrDatabase, "count secrets")
+	}
+	if err := paginate(db, opts).Order("id desc").Find(&ret.Items).Error; err != nil {
+		return nil, errors.WrapC(err, code.ErrDatabase, "list secrets")
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,74 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type users struct {
+	db *gorm.DB
+}
+
+func newUsers(ds *datastore) *users {
+	return &users{db: ds.db}
+}
+
+func (u *users) Create(ctx context.Context, user *model.User) error {
+	if err := u.db.WithContext(ctx).Create(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.WithCode(code.ErrUserAlreadyExist, "user %s already exist", user.Name)
+		}
+
+		return errors.WrapC(err, code.ErrDatabase, "create user %s", user.Name)
+	}
+
+	return nil
+}
+
+func (u *users) Update(ctx context.Context, user *model.User) error {
+	if err := u.db.WithContext(ctx).Save(user).Error; err != nil {
+		return errors.WrapC(err, code.ErrDatabase, "update user %s", user.Name)
+	}
+
+	return nil
+}
+
+func (u *users) Delete(ctx context.Context, username string) error {
+	err := u.db.WithContext(ctx).Where("name = ?", username).Delete(&model.User{}).Error
+	if err != nil {
+		return errors.WrapC(err, code.ErrDatabase, "delete user %s", username)
+	}
+
+	return nil
+}
+
+func (u *users) Get(ctx context.Context, username string) (*model.User, error) {
+	user := &model.User{}
+	err := u.db.WithContext(ctx).Where("name = ?", username).First(user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrUserNotFound, "user %s not found", username)
+		}
+
+		return nil, errors.WrapC(err, code.ErrDatabase, "get user %s", username)
+	}
+
+	return user, nil
+}
+
+func (u *users) List(ctx context.Context, opts model.ListOptions) (*model.UserList, error) {
+	ret := &model.UserList{}
+	db := u.db.WithContext(ctx).Model(&model.User{})
+	if err := db.Count(&ret.TotalCount).Error; err != nil {
+		return nil, errors.WrapC(err, code.ErrDatabase, "count users")
+	}
+	if err := paginate(db, opts).Order("id desc").Find(&ret.Items).Error; err != nil {
+		return nil, errors.WrapC(err, code.ErrDatabase, "list users")
+	}
+
+	return ret, nil
+}
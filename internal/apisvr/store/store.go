@@ -0,0 +1,46 @@
+// Package store 定义 apisvr 的数据访问接口，具体实现位于子包中
+package store
+
+import (
+	"context"
+
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+)
+
+// Factory 数据存储工厂，用于获取各资源的存储接口
+type Factory interface {
+	Users() UserStore
+	Secrets() SecretStore
+	Policies() PolicyStore
+	// Ping 检测数据库是否可用，用于就绪检查
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// UserStore 用户存储
+type UserStore interface {
+	Create(ctx context.Context, user *model.User) error
+	Update(ctx context.Context, user *model.User) error
+	Delete(ctx context.Context, username string) error
+	Get(ctx context.Context, username string) (*model.User, error)
+	List(ctx context.Context, opts model.ListOptions) (*model.UserList, error)
+}
+
+// SecretStore 密钥存储
+type SecretStore interface {
+	Create(ctx context.Context, secret *model.Secret) error
+	Update(ctx context.Context, secret *model.Secret) error
+	Delete(ctx context.Context, username, name string) error
+	Get(ctx context.Context, username, name string) (*model.Secret, error)
+	GetBySecretID(ctx context.Context, secretID string) (*model.Secret, error)
+	List(ctx context.Context, username string, opts model.ListOptions) (*model.SecretList, error)
+}
+
+// PolicyStore 策略存储
+type PolicyStore interface {
+	Create(ctx context.Context, policy *model.Policy) error
+	Update(ctx context.Context, policy *model.Policy) error
+	Delete(ctx context.Context, username, name string) error
+	Get(ctx context.Context, username, name string) (*model.Policy, error)
+	List(ctx context.Context, username string, opts model.ListOptions) (*model.PolicyList, error)
+}
@@ -0,0 +1,31 @@
+// Package iamctl 实现 IAM 的命令行客户端 iamctl，凭据保存在类似 kubeconfig 的配置文件中
+package iamctl
+
+import (
+	"github.com/ahang7/go-IAM/pkg/app"
+)
+
+const commandDesc = `iamctl controls the IAM platform.
+
+Log in with "iamctl login", the token is cached in the credentials file
+($HOME/.iam/config by default) and refreshed automatically.`
+
+// NewApp 创建 iamctl 命令
+func NewApp() *app.App {
+	opts := NewOptions()
+
+	return app.NewApp(
+		"IAMCTL",
+		"iamctl",
+		app.WithDescription(commandDesc),
+		app.WithNoConfig(),
+		app.WithDefaultValidArgs(),
+		app.WithCommands(
+			newLoginCommand(opts),
+			newLogoutCommand(opts),
+			newUserCommand(opts),
+			newSecretCommand(opts),
+			newPolicyCommand(opts),
+		),
+	)
+}
@@ -0,0 +1,151 @@
+package iamctl
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ahang7/go-IAM/pkg/client"
+	"github.com/ahang7/go-IAM/pkg/errors"
+)
+
+// session 当前上下文的凭据文件及 SDK 客户端
+type session struct {
+	opts   *Options
+	config *Config
+	user   *User
+	client *client.Client
+}
+
+// newSession 根据凭据文件中的上下文创建会话，--server 覆盖上下文中的服务地址
+func newSession(o *Options, cfg *Config) (*session, error) {
+	cluster, user, err := cfg.Resolve(o.Context)
+	if err != nil {
+		return nil, err
+	}
+	server := cluster.Server
+	if o.Server != "" {
+		server = o.Server
+	}
+
+	s := &session{opts: o, config: cfg, user: user}
+	s.client, err = client.New(server,
+		client.WithHTTPClient(newHTTPClient(cluster.InsecureSkipTLSVerify)),
+		client.WithTimeout(o.Timeout),
+		client.WithUserAgent("iamctl"),
+		client.WithTokenSource(&cachedToken{session: s}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func newHTTPClient(insecure bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// login 使用用户名和密码登录，保存 token
+func (s *session) login(ctx context.Context, username, password string) error {
+	token, err := s.client.Login(ctx, username, password)
+	if err != nil {
+		return err
+	}
+	s.user.Username = username
+
+	return s.saveToken(token)
+}
+
+// logout 通知服务端登出并删除本地保存的 token
+func (s *session) logout(ctx context.Context) error {
+	if s.user.Token != "" {
+		// token 可能已经过期，服务端登出失败不影响删除本地 token
+		_ = s.client.Logout(ctx)
+	}
+
+	return s.saveToken(&client.Token{})
+}
+
+func (s *session) saveToken(token *client.Token) error {
+	s.user.Token = token.AccessToken
+	s.user.Expiry = token.Expiry
+
+	return s.config.Save(s.opts.IAMConfig)
+}
+
+// cachedToken 使用凭据文件中的 token，token 即将过期或被服务端拒绝时刷新并写回凭据文件
+type cachedToken struct {
+	session *session
+
+	mu       sync.Mutex
+	rejected bool
+}
+
+func (t *cachedToken) Token(ctx context.Context) (*client.Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.session
+	if s.user.Token == "" {
+		return nil, fmt.Errorf("not logged in, please run iamctl login first")
+	}
+	token := &client.Token{AccessToken: s.user.Token, Expiry: s.user.Expiry}
+	if !t.rejected && token.Valid() {
+		return token, nil
+	}
+
+	token, err := s.client.RefreshToken(ctx, s.user.Token)
+	if err != nil {
+		return nil, fmt.Errorf("refresh token failed, please run iamctl login again: %w", err)
+	}
+	t.rejected = false
+
+	return token, s.saveToken(token)
+}
+
+func (t *cachedToken) Invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rejected = true
+}
+
+// run 使用当前上下文执行 fn，失败时错误信息中附带 pkg/errors 错误码和参考文档
+func run(o *Options, fn func(ctx context.Context, c *client.Client) error) error {
+	cfg, err := LoadConfig(o.IAMConfig)
+	if err != nil {
+		return err
+	}
+	s, err := newSession(o, cfg)
+	if err != nil {
+		return err
+	}
+
+	return describe(fn(context.Background(), s.client))
+}
+
+// describe 为服务端返回的错误附加错误码和参考文档
+func describe(err error) error {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	msg := err.Error()
+	if apiErr.Code != 0 {
+		msg = fmt.Sprintf("%s (code %d)", msg, apiErr.Code)
+	}
+	if apiErr.Reference != "" {
+		msg += "\nSee " + apiErr.Reference + " for more information."
+	}
+
+	return errors.New(msg)
+}
@@ -0,0 +1,145 @@
+package iamctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/pkg/client"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+)
+
+func newTestServer(t *testing.T, expire time.Duration) (*httptest.Server, *int) {
+	refreshed := 0
+	token := func(w http.ResponseWriter, value string) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": value, "expire": time.Now().Add(expire).Format(time.RFC3339)})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != "admin" || p != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"incorrect Username or Password"}`))
+
+			return
+		}
+		token(w, "t0")
+	})
+	mux.HandleFunc("POST /refresh", func(w http.ResponseWriter, r *http.Request) {
+		refreshed++
+		token(w, "t1")
+	})
+	mux.HandleFunc("GET /v1/users/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("name") != "colin" {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(httpcore.ErrResponse{Code: http.StatusNotFound, ErrorCode: 110001, Msg: "User not found", Reference: "https://example.com/errors"})
+
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"colin","auth":"` + r.Header.Get("Authorization") + `"}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv, &refreshed
+}
+
+func login(t *testing.T, o *Options, server string) *session {
+	cfg := &Config{}
+	cfg.SetContext(defaultContext, Cluster{Server: server}, User{Username: "admin"})
+	s, err := newSession(o, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.login(context.Background(), "admin", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestClientRefresh(t *testing.T) {
+	srv, refreshed := newTestServer(t, 30*time.Second)
+	o := NewOptions()
+	o.IAMConfig = filepath.Join(t.TempDir(), "config")
+
+	login(t, o, srv.URL)
+	cfg, err := LoadConfig(o.IAMConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, u, err := cfg.Resolve(""); err != nil || u.Token != "t0" {
+		t.Fatalf("token not saved: %+v %v", u, err)
+	}
+
+	// token 即将过期，请求前自动刷新
+	var user *v1.User
+	err = run(o, func(ctx context.Context, c *client.Client) (err error) {
+		user, err = c.Users().Get(ctx, "colin")
+
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *refreshed != 1 || user.GetName() != "colin" {
+		t.Errorf("expected one refresh, got %d, user %v", *refreshed, user)
+	}
+	if cfg, _ = LoadConfig(o.IAMConfig); cfg.Users[0].Token != "t1" {
+		t.Errorf("refreshed token not saved: %+v", cfg.Users[0])
+	}
+}
+
+func TestClientError(t *testing.T) {
+	srv, _ := newTestServer(t, time.Hour)
+	o := NewOptions()
+	o.IAMConfig = filepath.Join(t.TempDir(), "config")
+
+	s := login(t, o, srv.URL)
+	err := run(o, func(ctx context.Context, c *client.Client) error {
+		_, err := c.Users().Get(ctx, "missing")
+
+		return err
+	})
+	if err == nil || !strings.Contains(err.Error(), "code 110001") || !strings.Contains(err.Error(), "https://example.com/errors") {
+		t.Errorf("error message misses code or reference: %v", err)
+	}
+
+	if err := s.login(context.Background(), "admin", "wrong"); err == nil || !strings.Contains(err.Error(), "incorrect Username") {
+		t.Errorf("unexpected login error %v", err)
+	}
+}
+
+func TestPrintResult(t *testing.T) {
+	var buf bytes.Buffer
+	out = &buf
+	defer func() { out = os.Stdout }()
+
+	user := &v1.User{Name: "colin", IsAdmin: true, Status: 1}
+	o := &Options{Output: outputYAML}
+	if err := printResult(o, user, func() table { return userTable(user) }); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "name: colin\n") || !strings.Contains(buf.String(), "is_admin: true\n") {
+		t.Errorf("unexpected yaml:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	o.Output = outputTable
+	if err := printResult(o, user, func() table { return userTable(user) }); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "NAME") || !strings.Contains(lines[1], "enabled") {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+}
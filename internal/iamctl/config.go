@@ -0,0 +1,159 @@
+package iamctl
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultContext 登录时没有指定 --context 使用的上下文名称
+const defaultContext = "default"
+
+// Config iamctl 的凭据文件，结构与 kubeconfig 类似：上下文将 IAM API 地址和用户凭据关联起来
+type Config struct {
+	CurrentContext string    `yaml:"current-context"`
+	Clusters       []Cluster `yaml:"clusters"`
+	Users          []User    `yaml:"users"`
+	Contexts       []Context `yaml:"contexts"`
+}
+
+// Cluster IAM API 服务
+type Cluster struct {
+	Name   string `yaml:"name"`
+	Server string `yaml:"server"`
+	// InsecureSkipTLSVerify 不校验 HTTPS 证书，仅用于测试环境
+	InsecureSkipTLSVerify bool `yaml:"insecure-skip-tls-verify,omitempty"`
+}
+
+// User 用户凭据，Token 为登录获得的 JWT
+type User struct {
+	Name     string    `yaml:"name"`
+	Username string    `yaml:"username"`
+	Token    string    `yaml:"token,omitempty"`
+	Expiry   time.Time `yaml:"expiry,omitempty"`
+}
+
+// Context 上下文
+type Context struct {
+	Name    string `yaml:"name"`
+	Cluster string `yaml:"cluster"`
+	User    string `yaml:"user"`
+}
+
+// LoadConfig 读取凭据文件，文件不存在时返回空配置
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read credentials file: %w", err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse credentials file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Save 保存凭据文件，文件中包含 token，只有当前用户可以读写
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create credentials directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write credentials file: %w", err)
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Resolve 返回上下文对应的服务和用户，name 为空时使用 current-context
+func (c *Config) Resolve(name string) (*Cluster, *User, error) {
+	if name == "" {
+		name = c.CurrentContext
+	}
+	if name == "" {
+		return nil, nil, fmt.Errorf("no current context, please run iamctl login first")
+	}
+
+	ctx := c.context(name)
+	if ctx == nil {
+		return nil, nil, fmt.Errorf("context %q not found in credentials file", name)
+	}
+	cluster := c.cluster(ctx.Cluster)
+	if cluster == nil {
+		return nil, nil, fmt.Errorf("cluster %q of context %q not found in credentials file", ctx.Cluster, name)
+	}
+	user := c.user(ctx.User)
+	if user == nil {
+		return nil, nil, fmt.Errorf("user %q of context %q not found in credentials file", ctx.User, name)
+	}
+
+	return cluster, user, nil
+}
+
+// SetContext 添加或更新名为 name 的上下文及其服务和用户，并设为当前上下文
+func (c *Config) SetContext(name string, cluster Cluster, user User) {
+	cluster.Name, user.Name = name, name
+
+	if p := c.cluster(name); p != nil {
+		*p = cluster
+	} else {
+		c.Clusters = append(c.Clusters, cluster)
+	}
+	if p := c.user(name); p != nil {
+		*p = user
+	} else {
+		c.Users = append(c.Users, user)
+	}
+	if p := c.context(name); p != nil {
+		*p = Context{Name: name, Cluster: name, User: name}
+	} else {
+		c.Contexts = append(c.Contexts, Context{Name: name, Cluster: name, User: name})
+	}
+	c.CurrentContext = name
+}
+
+func (c *Config) cluster(name string) *Cluster {
+	for i := range c.Clusters {
+		if c.Clusters[i].Name == name {
+			return &c.Clusters[i]
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) user(name string) *User {
+	for i := range c.Users {
+		if c.Users[i].Name == name {
+			return &c.Users[i]
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) context(name string) *Context {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			return &c.Contexts[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package iamctl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ahang7/go-IAM/pkg/app"
+)
+
+func newLoginCommand(o *Options) *app.Command {
+	var (
+		username string
+		password string
+		insecure bool
+	)
+
+	return app.NewCommand("login", "Log in to the IAM API server and cache the token",
+		app.WithCommandLong(`Log in to the IAM API server with username and password. The token is saved to the
+credentials file under the context given by --context ("default" if empty), which becomes
+the current context. The password is read from stdin when --password is not given.`),
+		app.WithCommandArgs(cobra.NoArgs),
+		o.withFlags("login", func(fs *pflag.FlagSet) {
+			fs.StringVarP(&username, "username", "u", username, "Username to log in with.")
+			fs.StringVarP(&password, "password", "p", password, "Password to log in with, read from stdin if empty.")
+			fs.BoolVar(&insecure, "insecure-skip-tls-verify", insecure, "Do not verify the certificate of the server, insecure.")
+		}),
+		app.WithCommandRunFunc(func([]string) error {
+			cfg, err := LoadConfig(o.IAMConfig)
+			if err != nil {
+				return err
+			}
+
+			name := o.Context
+			if name == "" {
+				name = defaultContext
+			}
+			cluster := Cluster{Server: o.Server, InsecureSkipTLSVerify: insecure}
+			user := User{Username: username}
+			// 重新登录时沿用上下文中的服务地址和用户名
+			if c, u, err := cfg.Resolve(name); err == nil {
+				if cluster.Server == "" {
+					cluster.Server = c.Server
+				}
+				if user.Username == "" {
+					user.Username = u.Username
+				}
+			}
+			if cluster.Server == "" {
+				return fmt.Errorf("--server is required for the first login")
+			}
+			if user.Username == "" {
+				return fmt.Errorf("--username is required for the first login")
+			}
+			if password == "" {
+				if password, err = readPassword(); err != nil {
+					return err
+				}
+			}
+
+			cfg.SetContext(name, cluster, user)
+			o.Context, o.Server = name, ""
+			s, err := newSession(o, cfg)
+			if err != nil {
+				return err
+			}
+			if err := s.login(context.Background(), user.Username, password); err != nil {
+				return describe(err)
+			}
+
+			fmt.Printf("Logged in to %s as %s, context %q\n", cluster.Server, user.Username, name)
+
+			return nil
+		}),
+	)
+}
+
+func newLogoutCommand(o *Options) *app.Command {
+	return app.NewCommand("logout", "Log out and remove the cached token",
+		app.WithCommandArgs(cobra.NoArgs),
+		o.withFlags("logout", nil),
+		app.WithCommandRunFunc(func([]string) error {
+			cfg, err := LoadConfig(o.IAMConfig)
+			if err != nil {
+				return err
+			}
+			s, err := newSession(o, cfg)
+			if err != nil {
+				return err
+			}
+
+			return s.logout(context.Background())
+		}),
+	)
+}
+
+// readPassword 从标准输入读取一行作为密码
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,100 @@
+package iamctl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ahang7/go-IAM/pkg/app"
+)
+
+// 输出格式
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// Options iamctl 的全局命令行参数，所有子命令共用
+type Options struct {
+	// IAMConfig 凭据文件路径
+	IAMConfig string
+	// Context 使用的上下文，为空时使用凭据文件中的 current-context
+	Context string
+	// Server 覆盖上下文中的 IAM API 地址
+	Server  string
+	Output  string
+	Timeout time.Duration
+}
+
+// NewOptions 创建默认的全局参数，凭据文件默认为 $IAMCONFIG 或 $HOME/.iam/config
+func NewOptions() *Options {
+	config := os.Getenv("IAMCONFIG")
+	if config == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			config = filepath.Join(home, ".iam", "config")
+		}
+	}
+
+	return &Options{
+		IAMConfig: config,
+		Output:    outputTable,
+		Timeout:   30 * time.Second,
+	}
+}
+
+// AddFlags 添加全局参数
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.IAMConfig, "iamconfig", o.IAMConfig, "Path to the iamctl credentials file.")
+	fs.StringVar(&o.Context, "context", o.Context, "The name of the context to use, the current context by default.")
+	fs.StringVarP(&o.Server, "server", "s", o.Server, "The address of the IAM API server, overrides the context.")
+	fs.StringVarP(&o.Output, "output", "o", o.Output, "Output format, one of: table, json, yaml.")
+	fs.DurationVar(&o.Timeout, "request-timeout", o.Timeout, "The timeout of a single request, 0 means no timeout.")
+}
+
+// Validate 校验全局参数
+func (o *Options) Validate() []error {
+	var errs []error
+
+	switch o.Output {
+	case outputTable, outputJSON, outputYAML:
+	default:
+		errs = append(errs, fmt.Errorf("--output %q must be one of [table, json, yaml]", o.Output))
+	}
+	if o.IAMConfig == "" {
+		errs = append(errs, fmt.Errorf("--iamconfig cannot be empty"))
+	}
+	if o.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("--request-timeout %s must not be negative", o.Timeout))
+	}
+
+	return errs
+}
+
+// commandFlags 子命令的命令行参数，section 分组下为子命令自己的参数，global 分组下为全局参数
+type commandFlags struct {
+	opts    *Options
+	section string
+	add     func(fs *pflag.FlagSet)
+}
+
+func (f *commandFlags) Flags() (fs app.FlagSet) {
+	if f.add != nil {
+		f.add(fs.Flags(f.section))
+	}
+	f.opts.AddFlags(fs.Flags("global"))
+
+	return
+}
+
+func (f *commandFlags) Validate() []error {
+	return f.opts.Validate()
+}
+
+// withFlags 返回带有全局参数及 add 添加的参数的子命令选项
+func (o *Options) withFlags(section string, add func(fs *pflag.FlagSet)) app.CommandOption {
+	return app.WithCommandFlags(&commandFlags{opts: o, section: section, add: add})
+}
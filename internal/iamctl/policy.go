@@ -0,0 +1,187 @@
+package iamctl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/pkg/app"
+	"github.com/ahang7/go-IAM/pkg/client"
+)
+
+func newPolicyCommand(o *Options) *app.Command {
+	cmd := app.NewCommand("policy", "Manage authorization policies")
+	cmd.AddCommands(
+		newPolicyCreateCommand(o),
+		newPolicyGetCommand(o),
+		newPolicyListCommand(o),
+		newPolicyUpdateCommand(o),
+		newPolicyDeleteCommand(o),
+	)
+
+	return cmd
+}
+
+// statementFlags 添加策略内容的命令行参数
+func statementFlags(fs *pflag.FlagSet, s *v1.PolicyStatement) {
+	fs.StringVar(&s.Description, "description", s.Description, "Description of the policy.")
+	fs.StringVar(&s.Effect, "effect", s.Effect, "Effect of the policy, allow or deny.")
+	fs.StringSliceVar(&s.Subjects, "subjects", s.Subjects, "Subjects the policy applies to, comma separated.")
+	fs.StringSliceVar(&s.Resources, "resources", s.Resources, "Resources the policy applies to, comma separated.")
+	fs.StringSliceVar(&s.Actions, "actions", s.Actions, "Actions the policy applies to, comma separated.")
+}
+
+func newPolicyCreateCommand(o *Options) *app.Command {
+	req := &v1.CreatePolicyRequest{Statement: &v1.PolicyStatement{Effect: "allow"}}
+
+	return app.NewCommand("create NAME", "Create a policy",
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("policy", func(fs *pflag.FlagSet) {
+			ownerFlag(fs, &req.Username)
+			statementFlags(fs, req.Statement)
+		}),
+		app.WithCommandRunFunc(func(args []string) error {
+			req.Name = args[0]
+
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				policy, err := c.Policies().Create(ctx, req)
+				if err != nil {
+					return err
+				}
+
+				return printResult(o, policy, func() table { return policyTable(policy) })
+			})
+		}),
+	)
+}
+
+func newPolicyGetCommand(o *Options) *app.Command {
+	var username string
+
+	return app.NewCommand("get NAME", "Show a policy",
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("policy", func(fs *pflag.FlagSet) { ownerFlag(fs, &username) }),
+		app.WithCommandRunFunc(func(args []string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				policy, err := c.Policies().Get(ctx, &v1.GetPolicyRequest{Username: username, Name: args[0]})
+				if err != nil {
+					return err
+				}
+
+				return printResult(o, policy, func() table { return policyTable(policy) })
+			})
+		}),
+	)
+}
+
+func newPolicyListCommand(o *Options) *app.Command {
+	var page pageOptions
+
+	return app.NewCommand("list", "List policies",
+		app.WithCommandArgs(cobra.NoArgs),
+		o.withFlags("policy", func(fs *pflag.FlagSet) {
+			ownerFlag(fs, &page.username)
+			page.addFlags(fs)
+		}),
+		app.WithCommandRunFunc(func([]string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				resp, err := c.Policies().List(ctx, &v1.ListPoliciesRequest{Username: page.username, Offset: page.offset, Limit: page.limit})
+				if err != nil {
+					return err
+				}
+
+				return printResult(o, resp, func() table { return policyTable(resp.GetItems()...) })
+			})
+		}),
+	)
+}
+
+func newPolicyUpdateCommand(o *Options) *app.Command {
+	var username string
+	changes := &v1.PolicyStatement{}
+
+	return app.NewCommand("update NAME", "Update the statement of a policy",
+		app.WithCommandLong("Update the statement of a policy, only the given fields are changed."),
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("policy", func(fs *pflag.FlagSet) {
+			ownerFlag(fs, &username)
+			statementFlags(fs, changes)
+		}),
+		app.WithCommandRunFunc(func(args []string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				policy, err := c.Policies().Get(ctx, &v1.GetPolicyRequest{Username: username, Name: args[0]})
+				if err != nil {
+					return err
+				}
+				s := policy.GetStatement()
+				if s == nil {
+					s = &v1.PolicyStatement{}
+					policy.Statement = s
+				}
+				if changes.Description != "" {
+					s.Description = changes.Description
+				}
+				if changes.Effect != "" {
+					s.Effect = changes.Effect
+				}
+				if len(changes.Subjects) != 0 {
+					s.Subjects = changes.Subjects
+				}
+				if len(changes.Resources) != 0 {
+					s.Resources = changes.Resources
+				}
+				if len(changes.Actions) != 0 {
+					s.Actions = changes.Actions
+				}
+
+				req := &v1.UpdatePolicyRequest{Username: username, Name: args[0], Policy: policy}
+				if policy, err = c.Policies().Update(ctx, req); err != nil {
+					return err
+				}
+
+				return printResult(o, policy, func() table { return policyTable(policy) })
+			})
+		}),
+	)
+}
+
+func newPolicyDeleteCommand(o *Options) *app.Command {
+	var username string
+
+	return app.NewCommand("delete NAME", "Delete a policy",
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("policy", func(fs *pflag.FlagSet) { ownerFlag(fs, &username) }),
+		app.WithCommandRunFunc(func(args []string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				if err := c.Policies().Delete(ctx, &v1.DeletePolicyRequest{Username: username, Name: args[0]}); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "policy/%s deleted\n", args[0])
+
+				return nil
+			})
+		}),
+	)
+}
+
+func policyTable(policies ...*v1.Policy) table {
+	t := table{header: []string{"NAME", "USERNAME", "EFFECT", "SUBJECTS", "RESOURCES", "ACTIONS", "CREATED"}}
+	for _, p := range policies {
+		s := p.GetStatement()
+		t.rows = append(t.rows, []string{
+			p.GetName(),
+			p.GetUsername(),
+			s.GetEffect(),
+			orNone(strings.Join(s.GetSubjects(), ",")),
+			orNone(strings.Join(s.GetResources(), ",")),
+			orNone(strings.Join(s.GetActions(), ",")),
+			formatTime(p.GetCreatedAt()),
+		})
+	}
+
+	return t
+}
@@ -0,0 +1,106 @@
+package iamctl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+)
+
+// out 命令的输出
+var out io.Writer = os.Stdout
+
+// table 表格形式的输出
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// printResult 按照 --output 输出结果，json 和 yaml 输出完整的消息，table 输出 toTable 返回的表格
+func printResult(o *Options, msg proto.Message, toTable func() table) error {
+	switch o.Output {
+	case outputJSON, outputYAML:
+		data, err := protojson.MarshalOptions{
+			Multiline:       true,
+			Indent:          "  ",
+			UseProtoNames:   true,
+			EmitUnpopulated: true,
+		}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		if o.Output == outputJSON {
+			_, err = fmt.Fprintf(out, "%s\n", data)
+
+			return err
+		}
+
+		return printYAML(data)
+	default:
+		t := toTable()
+		w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+		fmt.Fprintln(w, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+
+		return w.Flush()
+	}
+}
+
+// printYAML 将 JSON 转换为 YAML 输出，保持字段顺序
+func printYAML(data []byte) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// blockStyle 清除 JSON 的 flow 风格和字符串引号
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		blockStyle(n)
+	}
+}
+
+func formatTime(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return "<none>"
+	}
+
+	return ts.AsTime().Local().Format(time.DateTime)
+}
+
+// formatUnix 格式化 unix 时间戳，0 表示永不
+func formatUnix(sec int64) string {
+	if sec == 0 {
+		return "never"
+	}
+
+	return time.Unix(sec, 0).Local().Format(time.DateTime)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+
+	return s
+}
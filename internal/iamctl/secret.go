@@ -0,0 +1,191 @@
+package iamctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/pkg/app"
+	"github.com/ahang7/go-IAM/pkg/client"
+)
+
+func newSecretCommand(o *Options) *app.Command {
+	cmd := app.NewCommand("secret", "Manage API secrets")
+	cmd.AddCommands(
+		newSecretCreateCommand(o),
+		newSecretGetCommand(o),
+		newSecretListCommand(o),
+		newSecretUpdateCommand(o),
+		newSecretDeleteCommand(o),
+	)
+
+	return cmd
+}
+
+// ownerFlag 管理员操作其他用户的资源时指定资源所属的用户
+func ownerFlag(fs *pflag.FlagSet, username *string) {
+	fs.StringVar(username, "username", *username, "Owner of the resource, administrators only. The logged in user by default.")
+}
+
+// expiresAt 将有效期转换为过期时间的 unix 时间戳，0 表示永不过期
+func expiresAt(ttl time.Duration) int64 {
+	if ttl == 0 {
+		return 0
+	}
+
+	return time.Now().Add(ttl).Unix()
+}
+
+func newSecretCreateCommand(o *Options) *app.Command {
+	req := &v1.CreateSecretRequest{}
+	var ttl time.Duration
+
+	return app.NewCommand("create NAME", "Create a secret",
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("secret", func(fs *pflag.FlagSet) {
+			ownerFlag(fs, &req.Username)
+			fs.DurationVar(&ttl, "expires-in", ttl, "Lifetime of the secret, 0 means never expires.")
+			fs.StringVar(&req.Description, "description", req.Description, "Description of the secret.")
+		}),
+		app.WithCommandRunFunc(func(args []string) error {
+			req.Name = args[0]
+			req.Expires = expiresAt(ttl)
+
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				secret, err := c.Secrets().Create(ctx, req)
+				if err != nil {
+					return err
+				}
+
+				// 创建时输出 secret key，表格中同样显示
+				return printResult(o, secret, func() table {
+					t := secretTable(secret)
+					t.header = append(t.header, "SECRET-KEY")
+					t.rows[0] = append(t.rows[0], secret.GetSecretKey())
+
+					return t
+				})
+			})
+		}),
+	)
+}
+
+func newSecretGetCommand(o *Options) *app.Command {
+	var username string
+
+	return app.NewCommand("get NAME", "Show a secret",
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("secret", func(fs *pflag.FlagSet) { ownerFlag(fs, &username) }),
+		app.WithCommandRunFunc(func(args []string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				secret, err := c.Secrets().Get(ctx, &v1.GetSecretRequest{Username: username, Name: args[0]})
+				if err != nil {
+					return err
+				}
+
+				return printResult(o, secret, func() table { return secretTable(secret) })
+			})
+		}),
+	)
+}
+
+func newSecretListCommand(o *Options) *app.Command {
+	var page pageOptions
+
+	return app.NewCommand("list", "List secrets",
+		app.WithCommandArgs(cobra.NoArgs),
+		o.withFlags("secret", func(fs *pflag.FlagSet) {
+			ownerFlag(fs, &page.username)
+			page.addFlags(fs)
+		}),
+		app.WithCommandRunFunc(func([]string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				resp, err := c.Secrets().List(ctx, &v1.ListSecretsRequest{Username: page.username, Offset: page.offset, Limit: page.limit})
+				if err != nil {
+					return err
+				}
+
+				return printResult(o, resp, func() table { return secretTable(resp.GetItems()...) })
+			})
+		}),
+	)
+}
+
+func newSecretUpdateCommand(o *Options) *app.Command {
+	var (
+		username    string
+		description string
+		ttl         time.Duration
+	)
+
+	return app.NewCommand("update NAME", "Update the description or lifetime of a secret",
+		app.WithCommandLong("Update the description or lifetime of a secret, only the given fields are changed."),
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("secret", func(fs *pflag.FlagSet) {
+			ownerFlag(fs, &username)
+			fs.DurationVar(&ttl, "expires-in", ttl, "New lifetime of the secret from now.")
+			fs.StringVar(&description, "description", description, "New description of the secret.")
+		}),
+		app.WithCommandRunFunc(func(args []string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				secret, err := c.Secrets().Get(ctx, &v1.GetSecretRequest{Username: username, Name: args[0]})
+				if err != nil {
+					return err
+				}
+				if description != "" {
+					secret.Description = description
+				}
+				if ttl != 0 {
+					secret.Expires = expiresAt(ttl)
+				}
+
+				req := &v1.UpdateSecretRequest{Username: username, Name: args[0], Secret: secret}
+				if secret, err = c.Secrets().Update(ctx, req); err != nil {
+					return err
+				}
+
+				return printResult(o, secret, func() table { return secretTable(secret) })
+			})
+		}),
+	)
+}
+
+func newSecretDeleteCommand(o *Options) *app.Command {
+	var username string
+
+	return app.NewCommand("delete NAME", "Delete a secret",
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("secret", func(fs *pflag.FlagSet) { ownerFlag(fs, &username) }),
+		app.WithCommandRunFunc(func(args []string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				if err := c.Secrets().Delete(ctx, &v1.DeleteSecretRequest{Username: username, Name: args[0]}); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "secret/%s deleted\n", args[0])
+
+				return nil
+			})
+		}),
+	)
+}
+
+// secretTable 表格中不显示 secret key
+func secretTable(secrets ...*v1.Secret) table {
+	t := table{header: []string{"NAME", "USERNAME", "SECRET-ID", "EXPIRES", "DESCRIPTION", "CREATED"}}
+	for _, s := range secrets {
+		t.rows = append(t.rows, []string{
+			s.GetName(),
+			s.GetUsername(),
+			s.GetSecretId(),
+			formatUnix(s.GetExpires()),
+			orNone(s.GetDescription()),
+			formatTime(s.GetCreatedAt()),
+		})
+	}
+
+	return t
+}
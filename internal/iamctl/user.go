@@ -0,0 +1,191 @@
+package iamctl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/pkg/app"
+	"github.com/ahang7/go-IAM/pkg/client"
+)
+
+func newUserCommand(o *Options) *app.Command {
+	cmd := app.NewCommand("user", "Manage users")
+	cmd.AddCommands(
+		newUserCreateCommand(o),
+		newUserGetCommand(o),
+		newUserListCommand(o),
+		newUserUpdateCommand(o),
+		newUserDeleteCommand(o),
+	)
+
+	return cmd
+}
+
+func newUserCreateCommand(o *Options) *app.Command {
+	req := &v1.CreateUserRequest{}
+
+	return app.NewCommand("create NAME", "Create a user",
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("user", func(fs *pflag.FlagSet) {
+			fs.StringVarP(&req.Password, "password", "p", req.Password, "Password of the user, read from stdin if empty.")
+			fs.StringVar(&req.Nickname, "nickname", req.Nickname, "Nickname of the user.")
+			fs.StringVar(&req.Email, "email", req.Email, "Email of the user.")
+			fs.StringVar(&req.Phone, "phone", req.Phone, "Phone number of the user.")
+			fs.BoolVar(&req.IsAdmin, "admin", req.IsAdmin, "Grant administrator to the user.")
+		}),
+		app.WithCommandRunFunc(func(args []string) error {
+			req.Name = args[0]
+			if req.Password == "" {
+				var err error
+				if req.Password, err = readPassword(); err != nil {
+					return err
+				}
+			}
+
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				user, err := c.Users().Create(ctx, req)
+				if err != nil {
+					return err
+				}
+
+				return printResult(o, user, func() table { return userTable(user) })
+			})
+		}),
+	)
+}
+
+func newUserGetCommand(o *Options) *app.Command {
+	return app.NewCommand("get NAME", "Show a user",
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("user", nil),
+		app.WithCommandRunFunc(func(args []string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				user, err := c.Users().Get(ctx, args[0])
+				if err != nil {
+					return err
+				}
+
+				return printResult(o, user, func() table { return userTable(user) })
+			})
+		}),
+	)
+}
+
+func newUserListCommand(o *Options) *app.Command {
+	var page pageOptions
+
+	return app.NewCommand("list", "List users",
+		app.WithCommandArgs(cobra.NoArgs),
+		o.withFlags("user", page.addFlags),
+		app.WithCommandRunFunc(func([]string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				resp, err := c.Users().List(ctx, &v1.ListUsersRequest{Offset: page.offset, Limit: page.limit})
+				if err != nil {
+					return err
+				}
+
+				return printResult(o, resp, func() table { return userTable(resp.GetItems()...) })
+			})
+		}),
+	)
+}
+
+func newUserUpdateCommand(o *Options) *app.Command {
+	var nickname, email, phone, admin string
+
+	return app.NewCommand("update NAME", "Update the profile of a user",
+		app.WithCommandLong("Update the profile of a user, only the given fields are changed."),
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("user", func(fs *pflag.FlagSet) {
+			fs.StringVar(&nickname, "nickname", nickname, "New nickname of the user.")
+			fs.StringVar(&email, "email", email, "New email of the user.")
+			fs.StringVar(&phone, "phone", phone, "New phone number of the user.")
+			fs.StringVar(&admin, "admin", admin, "Grant (true) or revoke (false) administrator, administrators only.")
+		}),
+		app.WithCommandRunFunc(func(args []string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				// 更新接口会覆盖所有可更新的字段，先获取当前值
+				user, err := c.Users().Get(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				if nickname != "" {
+					user.Nickname = nickname
+				}
+				if email != "" {
+					user.Email = email
+				}
+				if phone != "" {
+					user.Phone = phone
+				}
+				if admin != "" {
+					if user.IsAdmin, err = strconv.ParseBool(admin); err != nil {
+						return fmt.Errorf("--admin %q must be true or false", admin)
+					}
+				}
+
+				if user, err = c.Users().Update(ctx, &v1.UpdateUserRequest{Name: args[0], User: user}); err != nil {
+					return err
+				}
+
+				return printResult(o, user, func() table { return userTable(user) })
+			})
+		}),
+	)
+}
+
+func newUserDeleteCommand(o *Options) *app.Command {
+	return app.NewCommand("delete NAME", "Delete a user",
+		app.WithCommandArgs(cobra.ExactArgs(1)),
+		o.withFlags("user", nil),
+		app.WithCommandRunFunc(func(args []string) error {
+			return run(o, func(ctx context.Context, c *client.Client) error {
+				if err := c.Users().Delete(ctx, args[0]); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "user/%s deleted\n", args[0])
+
+				return nil
+			})
+		}),
+	)
+}
+
+func userTable(users ...*v1.User) table {
+	t := table{header: []string{"NAME", "NICKNAME", "EMAIL", "PHONE", "ADMIN", "STATUS", "CREATED"}}
+	for _, u := range users {
+		status := "enabled"
+		if u.GetStatus() != 1 {
+			status = "disabled"
+		}
+		t.rows = append(t.rows, []string{
+			u.GetName(),
+			orNone(u.GetNickname()),
+			orNone(u.GetEmail()),
+			orNone(u.GetPhone()),
+			strconv.FormatBool(u.GetIsAdmin()),
+			status,
+			formatTime(u.GetCreatedAt()),
+		})
+	}
+
+	return t
+}
+
+// pageOptions 列表命令的分页参数
+type pageOptions struct {
+	offset int64
+	limit  int64
+	// username 管理员查看其他用户的资源
+	username string
+}
+
+func (p *pageOptions) addFlags(fs *pflag.FlagSet) {
+	fs.Int64Var(&p.offset, "offset", p.offset, "Number of records to skip.")
+	fs.Int64Var(&p.limit, "limit", p.limit, "Maximum number of records to return, 0 means all.")
+}
@@ -0,0 +1,161 @@
+// Package authz 实现基于策略的授权判定。
+//
+// 一个请求由 subject、resource、action 组成，与用户的策略逐条匹配：
+// 任意一条 deny 策略匹配时拒绝，否则任意一条 allow 策略匹配时允许，都不匹配时默认拒绝。
+package authz
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/pkg/metrics"
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+	"github.com/ahang7/go-IAM/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Request 授权请求
+type Request struct {
+	Subject  string
+	Resource string
+	Action   string
+}
+
+// Decision 授权结果
+type Decision struct {
+	Allowed bool
+	// Effect 决定结果的策略效果，没有策略匹配时为 deny
+	Effect string
+	// Policy 决定结果的策略名称，没有策略匹配时为空
+	Policy string
+	Reason string
+}
+
+// PolicyGetter 获取用户的策略
+type PolicyGetter interface {
+	GetPolicies(ctx context.Context, username string) ([]*model.Policy, error)
+}
+
+// Authorizer 授权器
+type Authorizer struct {
+	getter PolicyGetter
+}
+
+// NewAuthorizer 创建授权器
+func NewAuthorizer(getter PolicyGetter) *Authorizer {
+	return &Authorizer{getter: getter}
+}
+
+// Authorize 使用 username 的策略对请求进行授权判定
+func (a *Authorizer) Authorize(ctx context.Context, username string, req *Request) (_ *Decision, err error) {
+	ctx, span := tracing.Start(ctx, "authz.Authorize", trace.WithAttributes(
+		attribute.String("authz.username", username),
+		attribute.String("authz.subject", req.Subject),
+		attribute.String("authz.resource", req.Resource),
+		attribute.String("authz.action", req.Action),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	start := time.Now()
+	policies, err := a.getter.GetPolicies(ctx, username)
+	if err != nil {
+		metrics.ObserveAuthz("error", time.Since(start))
+
+		return nil, err
+	}
+
+	d := Evaluate(policies, req)
+	metrics.ObserveAuthz(d.Effect, time.Since(start))
+	span.SetAttributes(
+		attribute.Int("authz.policies", len(policies)),
+		attribute.Bool("authz.allowed", d.Allowed),
+		attribute.String("authz.effect", d.Effect),
+		attribute.String("authz.policy", d.Policy),
+	)
+
+	return d, nil
+}
+
+// Evaluate 使用给定的策略对请求进行授权判定
+func Evaluate(policies []*model.Policy, req *Request) *Decision {
+	var allowedBy *model.Policy
+	for _, p := range policies {
+		if !matches(&p.Statement, req) {
+			continue
+		}
+		if strings.EqualFold(p.Statement.Effect, model.DenyAccess) {
+			return &Decision{
+				Allowed: false,
+				Effect:  model.DenyAccess,
+				Policy:  p.Name,
+				Reason:  "request was forbidden by policy " + p.Name,
+			}
+		}
+		if allowedBy == nil && strings.EqualFold(p.Statement.Effect, model.AllowAccess) {
+			allowedBy = p
+		}
+	}
+
+	if allowedBy != nil {
+		return &Decision{
+			Allowed: true,
+			Effect:  model.AllowAccess,
+			Policy:  allowedBy.Name,
+		}
+	}
+
+	return &Decision{
+		Allowed: false,
+		Effect:  model.DenyAccess,
+		Reason:  "request was denied by default",
+	}
+}
+
+func matches(s *model.PolicyStatement, req *Request) bool {
+	return matchAny(s.Subjects, req.Subject) &&
+		matchAny(s.Resources, req.Resource) &&
+		matchAny(s.Actions, req.Action)
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if Match(p, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match 判断 s 是否匹配模式 pattern，pattern 中的 * 匹配任意长度（包括 0）的任意字符
+func Match(pattern, s string) bool {
+	for len(pattern) > 0 {
+		star := strings.IndexByte(pattern, '*')
+		if star < 0 {
+			return pattern == s
+		}
+
+		prefix := pattern[:star]
+		if !strings.HasPrefix(s, prefix) {
+			return false
+		}
+		s = s[len(prefix):]
+		pattern = pattern[star+1:]
+		if pattern == "" {
+			return true
+		}
+
+		// 尝试让 * 匹配 s 的每一个后缀
+		for i := 0; i <= len(s); i++ {
+			if Match(pattern, s[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return s == ""
+}
@@ -0,0 +1,63 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "", true},
+		{"*", "anything", true},
+		{"users:*", "users:colin", true},
+		{"users:*", "secrets:colin", false},
+		{"a*c*e", "abcde", true},
+		{"a*c*e", "abcd", false},
+		{"exact", "exact", true},
+		{"exact", "exactly", false},
+	}
+	for _, c := range cases {
+		if got := Match(c.pattern, c.s); got != c.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", c.pattern, c.s, got, c.want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	policy := func(name, effect string, resources ...string) *model.Policy {
+		return &model.Policy{
+			ObjectMeta: model.ObjectMeta{Name: name},
+			Statement: model.PolicyStatement{
+				Effect:    effect,
+				Subjects:  []string{"users:colin"},
+				Resources: resources,
+				Actions:   []string{"get", "list"},
+			},
+		}
+	}
+	policies := []*model.Policy{
+		policy("allow-articles", model.AllowAccess, "articles:*"),
+		policy("deny-secret-article", model.DenyAccess, "articles:secret"),
+	}
+
+	cases := []struct {
+		req        Request
+		allowed    bool
+		decisiveBy string
+	}{
+		{Request{"users:colin", "articles:ladon", "get"}, true, "allow-articles"},
+		{Request{"users:colin", "articles:secret", "get"}, false, "deny-secret-article"},
+		{Request{"users:colin", "articles:ladon", "delete"}, false, ""},
+		{Request{"users:tom", "articles:ladon", "get"}, false, ""},
+	}
+	for _, c := range cases {
+		d := Evaluate(policies, &c.req)
+		if d.Allowed != c.allowed || d.Policy != c.decisiveBy {
+			t.Errorf("Evaluate(%+v) = %+v, want allowed=%v policy=%q", c.req, d, c.allowed, c.decisiveBy)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package authz
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+)
+
+// Cache 缓存用户策略的 PolicyGetter。缓存在 ttl 之后过期，本进程内修改策略时需要调用 Invalidate，
+// 其他副本修改的策略最多在 ttl 之后生效
+type Cache struct {
+	getter PolicyGetter
+	ttl    time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]cacheEntry
+	// pruned 上次清理过期缓存的时间
+	pruned time.Time
+	// generation 每次 Invalidate 时递增，加载期间发生过 Invalidate 的结果不写入缓存
+	generation uint64
+}
+
+type cacheEntry struct {
+	policies []*model.Policy
+	loadedAt time.Time
+}
+
+var _ PolicyGetter = (*Cache)(nil)
+
+// NewCache 创建策略缓存，未命中时从 getter 加载
+func NewCache(getter PolicyGetter, ttl time.Duration) *Cache {
+	return &Cache{
+		getter:  getter,
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+		pruned:  time.Now(),
+	}
+}
+
+// GetPolicies 实现 PolicyGetter，返回的策略不能修改
+func (c *Cache) GetPolicies(ctx context.Context, username string) ([]*model.Policy, error) {
+	c.mu.RLock()
+	e, ok := c.entries[username]
+	generation := c.generation
+	c.mu.RUnlock()
+	if ok && time.Since(e.loadedAt) < c.ttl {
+		return e.policies, nil
+	}
+
+	policies, err := c.getter.GetPolicies(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return policies, nil
+	}
+	c.entries[username] = cacheEntry{policies: policies, loadedAt: now}
+	// 不再访问的用户的缓存在过期后清理
+	if now.Sub(c.pruned) >= c.ttl {
+		for name, e := range c.entries {
+			if now.Sub(e.loadedAt) >= c.ttl {
+				delete(c.entries, name)
+			}
+		}
+		c.pruned = now
+	}
+
+	return policies, nil
+}
+
+// Invalidate 删除 username 的策略缓存
+func (c *Cache) Invalidate(username string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, username)
+	c.generation++
+}
+
+// Stats 返回缓存的用户数以及最早加载的缓存的加载时间，没有缓存时 oldest 为零值
+func (c *Cache) Stats() (entries int, oldest time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, e := range c.entries {
+		if oldest.IsZero() || e.loadedAt.Before(oldest) {
+			oldest = e.loadedAt
+		}
+	}
+
+	return len(c.entries), oldest
+}
@@ -0,0 +1,48 @@
+package authz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/pkg/model"
+)
+
+type countingGetter struct {
+	calls int
+}
+
+func (g *countingGetter) GetPolicies(context.Context, string) ([]*model.Policy, error) {
+	g.calls++
+
+	return []*model.Policy{{ObjectMeta: model.ObjectMeta{Name: "p"}}}, nil
+}
+
+func TestCache(t *testing.T) {
+	getter := &countingGetter{}
+	c := NewCache(getter, time.Hour)
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := c.GetPolicies(ctx, "colin"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if getter.calls != 1 {
+		t.Errorf("getter called %d times, want 1", getter.calls)
+	}
+	if entries, oldest := c.Stats(); entries != 1 || oldest.IsZero() {
+		t.Errorf("Stats() = %d, %v, want 1 entry", entries, oldest)
+	}
+
+	c.Invalidate("colin")
+	if entries, _ := c.Stats(); entries != 0 {
+		t.Errorf("Stats() = %d entries after Invalidate, want 0", entries)
+	}
+	if _, err := c.GetPolicies(ctx, "colin"); err != nil {
+		t.Fatal(err)
+	}
+	if getter.calls != 2 {
+		t.Errorf("getter called %d times after Invalidate, want 2", getter.calls)
+	}
+}
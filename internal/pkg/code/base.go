@@ -23,6 +23,15 @@ const (
 
 	// ErrPageNotFound - 404: Page not found.
 	ErrPageNotFound
+
+	// ErrRequestTooLarge - 413: Request body too large.
+	ErrRequestTooLarge
+
+	// ErrRequestTimeout - 504: Request timeout.
+	ErrRequestTimeout
+
+	// ErrTooManyRequests - 429: Too many requests.
+	ErrTooManyRequests
 )
 
 // common: database errors.
@@ -42,9 +42,9 @@ func (c *ErrCode) String() string {
 var _ errors.Coder = (*ErrCode)(nil)
 
 func register(code int, httpStatus int, message string, refs ...string) {
-	found, _ := gubrak.Includes([]int{200, 400, 401, 403, 404, 500}, httpStatus)
+	found, _ := gubrak.Includes([]int{200, 400, 401, 403, 404, 413, 429, 500, 504}, httpStatus)
 	if !found {
-		panic("http status code must be 200, 400, 401, 404, 500")
+		panic("http status code must be 200, 400, 401, 403, 404, 413, 429, 500, 504")
 	}
 
 	var reference string
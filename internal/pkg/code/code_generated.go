@@ -15,6 +15,9 @@ func init() {
 	register(ErrValidation, 400, "Validation failed")
 	register(ErrTokenInvalid, 401, "Invalid token")
 	register(ErrPageNotFound, 404, "Page not found")
+	register(ErrRequestTooLarge, 413, "Request body too large")
+	register(ErrRequestTimeout, 504, "Request timeout")
+	register(ErrTooManyRequests, 429, "Too many requests")
 	register(ErrDatabase, 500, "Database error")
 	register(ErrEncrypt, 401, "Error occurred while encrypting the user password")
 	register(ErrSignatureInvalid, 401, "Signature is invalid")
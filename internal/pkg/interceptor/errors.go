@@ -0,0 +1,97 @@
+package interceptor
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/ahang7/go-IAM/internal/pkg/metrics"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain gRPC 错误详情 ErrorInfo 中的 domain
+const ErrorDomain = "iam.ch.com"
+
+// httpToGRPC HTTP 状态码与 gRPC 状态码的对应关系
+var httpToGRPC = map[int]codes.Code{
+	http.StatusOK:                    codes.OK,
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusInternalServerError:   codes.Internal,
+	http.StatusNotImplemented:        codes.Unimplemented,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// GRPCCode 返回 HTTP 状态码对应的 gRPC 状态码，未知的状态码返回 codes.Unknown
+func GRPCCode(httpStatus int) codes.Code {
+	if c, ok := httpToGRPC[httpStatus]; ok {
+		return c
+	}
+
+	return codes.Unknown
+}
+
+// ToStatus 将 pkg/errors 错误转换为 gRPC status，错误码、HTTP 状态码和参考文档放在 ErrorInfo 详情中
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	// 已经是 gRPC status 的错误直接返回
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	coder := errors.ParseCoder(err)
+	st := status.New(GRPCCode(coder.HTTPStatus()), coder.String())
+	detailed, derr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: strconv.Itoa(coder.Code()),
+		Domain: ErrorDomain,
+		Metadata: map[string]string{
+			"code":       strconv.Itoa(coder.Code()),
+			"httpStatus": strconv.Itoa(coder.HTTPStatus()),
+			"reference":  coder.Reference(),
+		},
+	})
+	if derr != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// UnaryErrors 将 handler 返回的 pkg/errors 错误转换为带详情的 gRPC status
+func UnaryErrors() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			metrics.ObserveError("grpc", errors.ParseCoder(err).Code())
+
+			return resp, ToStatus(err).Err()
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamErrors 将 handler 返回的 pkg/errors 错误转换为带详情的 gRPC status
+func StreamErrors() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			metrics.ObserveError("grpc", errors.ParseCoder(err).Code())
+
+			return ToStatus(err).Err()
+		}
+
+		return nil
+	}
+}
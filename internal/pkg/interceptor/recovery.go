@@ -0,0 +1,43 @@
+package interceptor
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"google.golang.org/grpc"
+)
+
+// UnaryRecovery 捕获 handler 中的 panic，记录日志并返回 code.ErrUnknown
+func UnaryRecovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecovery 捕获 handler 中的 panic，记录日志并返回 code.ErrUnknown
+func StreamRecovery() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func recovered(method string, r any) error {
+	log.Errorw("panic recovered", "method", method, "error", r, "stack", string(debug.Stack()))
+
+	return ToStatus(errors.WithCode(code.ErrUnknown, "%v", r)).Err()
+}
@@ -0,0 +1,153 @@
+// Package metrics 定义 IAM 的业务指标：认证、token 签发、授权判定、策略缓存以及按错误码统计的错误数。
+// 指标注册到 prometheus 默认的 Registry，由 GenericServer 的 /metrics 接口暴露
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "iam"
+
+// 认证结果
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// 签发 token 的方式
+const (
+	TokenLogin   = "login"
+	TokenRefresh = "refresh"
+)
+
+var (
+	loginAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_attempts_total",
+		Help:      "Authentication attempts by strategy (basic, jwt, secret, password) and outcome.",
+	}, []string{"strategy", "outcome"})
+
+	tokensIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tokens_issued_total",
+		Help:      "JWT tokens issued by login or refresh.",
+	}, []string{"type"})
+
+	authzDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "authz_decisions_total",
+		Help:      "Authorization decisions by effect, error when the policies could not be loaded.",
+	}, []string{"effect"})
+
+	authzDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "authz_duration_seconds",
+		Help:      "Latency of authorization decisions including loading the policies.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 12),
+	})
+
+	errorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "errors_total",
+		Help:      "Errors returned to clients by pkg/errors code and transport (http, grpc).",
+	}, []string{"code", "transport"})
+)
+
+func init() {
+	prometheus.MustRegister(loginAttempts, tokensIssued, authzDecisions, authzDuration, errorsTotal,
+		logsDropped("sampled", func(d log.Drops) uint64 { return d.Sampled }),
+		logsDropped("queue_full", func(d log.Drops) uint64 { return d.QueueFull }),
+	)
+}
+
+// logsDropped 返回 pkg/log 丢弃日志条数的指标
+func logsDropped(reason string, count func(log.Drops) uint64) prometheus.Collector {
+	return prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "logs_dropped_total",
+		Help:        "Log entries dropped by sampling or because the async write queue was full.",
+		ConstLabels: prometheus.Labels{"reason": reason},
+	}, func() float64 {
+		return float64(count(log.DroppedLogs()))
+	})
+}
+
+// ObserveLogin 记录一次认证，err 为 nil 时认证成功
+func ObserveLogin(strategy string, err error) {
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeFailure
+	}
+	loginAttempts.WithLabelValues(strategy, outcome).Inc()
+}
+
+// ObserveTokenIssued 记录一次 token 签发，kind 为 TokenLogin 或 TokenRefresh
+func ObserveTokenIssued(kind string) {
+	tokensIssued.WithLabelValues(kind).Inc()
+}
+
+// ObserveAuthz 记录一次授权判定，effect 为判定结果的策略效果，判定失败时为 error
+func ObserveAuthz(effect string, latency time.Duration) {
+	authzDecisions.WithLabelValues(effect).Inc()
+	authzDuration.Observe(latency.Seconds())
+}
+
+// ObserveError 记录一次返回给客户端的错误
+func ObserveError(transport string, code int) {
+	errorsTotal.WithLabelValues(strconv.Itoa(code), transport).Inc()
+}
+
+// CountErrors 返回统计 REST 接口错误的中间件，错误由 httpcore.WriteResponse 通过 c.Error 记录
+func CountErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		for _, e := range c.Errors {
+			ObserveError("http", errors.ParseCoder(e.Err).Code())
+		}
+	}
+}
+
+// RegisterPolicyCache 注册策略缓存的指标，stats 返回缓存的用户数以及最早加载的缓存的加载时间
+func RegisterPolicyCache(stats func() (entries int, oldest time.Time)) {
+	Register(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "policy_cache_entries",
+			Help:      "Number of users whose policies are cached.",
+		}, func() float64 {
+			entries, _ := stats()
+
+			return float64(entries)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "policy_cache_oldest_entry_age_seconds",
+			Help:      "Age of the oldest cached policies, 0 when the cache is empty.",
+		}, func() float64 {
+			if _, oldest := stats(); !oldest.IsZero() {
+				return time.Since(oldest).Seconds()
+			}
+
+			return 0
+		}),
+	)
+}
+
+// Register 注册指标，重复注册时忽略
+func Register(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := prometheus.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if !errors.As(err, &are) {
+				log.Errorf("register metric failed: %s", err.Error())
+			}
+		}
+	}
+}
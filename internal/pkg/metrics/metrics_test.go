@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCountErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Use(CountErrors())
+	e.GET("/users", func(c *gin.Context) {
+		_ = c.Error(errors.WithCode(code.ErrUserNotFound, "user not found"))
+		c.Status(http.StatusNotFound)
+	})
+	e.GET("/healthz", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	notFound := errorsTotal.WithLabelValues(strconv.Itoa(code.ErrUserNotFound), "http")
+	before := testutil.ToFloat64(notFound)
+	total := testutil.CollectAndCount(errorsTotal)
+
+	for _, target := range []string{"/users", "/users", "/healthz"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	if got := testutil.ToFloat64(notFound) - before; got != 2 {
+		t.Errorf("errors_total{code=%d} increased by %v, want 2", code.ErrUserNotFound, got)
+	}
+	if got := testutil.CollectAndCount(errorsTotal); got != total {
+		t.Errorf("errors_total has %d series, want %d", got, total)
+	}
+}
+
+func TestObserveLogin(t *testing.T) {
+	success := loginAttempts.WithLabelValues("basic", OutcomeSuccess)
+	failure := loginAttempts.WithLabelValues("basic", OutcomeFailure)
+	successBefore, failureBefore := testutil.ToFloat64(success), testutil.ToFloat64(failure)
+
+	ObserveLogin("basic", nil)
+	ObserveLogin("basic", errors.WithCode(code.ErrPasswordIncorrect, "password incorrect"))
+	ObserveLogin("basic", errors.WithCode(code.ErrPasswordIncorrect, "password incorrect"))
+
+	if got := testutil.ToFloat64(success) - successBefore; got != 1 {
+		t.Errorf("successful logins increased by %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(failure) - failureBefore; got != 2 {
+		t.Errorf("failed logins increased by %v, want 2", got)
+	}
+}
+
+func TestRegisterPolicyCache(t *testing.T) {
+	var buf bytes.Buffer
+	std := log.Default()
+	log.ReplaceDefault(log.New(&buf, log.InfoLevel))
+	defer log.ReplaceDefault(std)
+
+	oldest := time.Now().Add(-time.Minute)
+	RegisterPolicyCache(func() (int, time.Time) { return 3, oldest })
+	// 重复注册时保留已注册的指标
+	RegisterPolicyCache(func() (int, time.Time) { return 0, time.Time{} })
+
+	expected := `
+# HELP iam_policy_cache_entries Number of users whose policies are cached.
+# TYPE iam_policy_cache_entries gauge
+iam_policy_cache_entries 3
+`
+	if err := testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected),
+		"iam_policy_cache_entries"); err != nil {
+		t.Error(err)
+	}
+
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var age float64
+	for _, mf := range mfs {
+		if mf.GetName() == "iam_policy_cache_oldest_entry_age_seconds" {
+			age = mf.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	if age < time.Minute.Seconds() {
+		t.Errorf("iam_policy_cache_oldest_entry_age_seconds = %v, want at least 60", age)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("RegisterPolicyCache logged %q, want nothing", buf.String())
+	}
+}
+
+func TestRegister_AlreadyRegistered(t *testing.T) {
+	var buf bytes.Buffer
+	std := log.Default()
+	log.ReplaceDefault(log.New(&buf, log.InfoLevel))
+	defer log.ReplaceDefault(std)
+
+	newCounter := func() prometheus.Counter {
+		return prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "test_register_total",
+			Help:      "Counter registered by TestRegister_AlreadyRegistered.",
+		})
+	}
+	c := newCounter()
+	defer prometheus.Unregister(c)
+
+	Register(c)
+	Register(c, newCounter())
+
+	if got, err := testutil.GatherAndCount(prometheus.DefaultGatherer, "iam_test_register_total"); err != nil || got != 1 {
+		t.Errorf("iam_test_register_total has %d series (err %v), want 1", got, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Register logged %q, want nothing", buf.String())
+	}
+
+	// 注册失败的其它错误仍然记录日志
+	Register(prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "test_register_total",
+		Help:      "Counter with a different help text.",
+	}))
+	if !strings.Contains(buf.String(), "register metric failed") {
+		t.Errorf("Register logged %q, want the registration error", buf.String())
+	}
+}
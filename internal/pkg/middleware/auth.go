@@ -1,11 +1,21 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
 
 type AuthStrategy interface {
 	AuthExecute() gin.HandlerFunc
 }
 
+// CredentialVerifier 校验与传输协议无关的认证凭据，供 gRPC 拦截器等非 gin 场景复用认证策略
+type CredentialVerifier interface {
+	// Verify 校验 Authorization 中认证类型之后的凭据，返回认证通过的用户名
+	Verify(ctx context.Context, credential string) (string, error)
+}
+
 type AuthOperator struct {
 	strategy AuthStrategy
 }
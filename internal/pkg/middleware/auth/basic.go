@@ -5,9 +5,11 @@ import (
 	"strings"
 
 	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/metrics"
 	"github.com/ahang7/go-IAM/internal/pkg/middleware"
 	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
 	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/ahang7/go-IAM/pkg/tracing"
 	"github.com/gin-gonic/gin"
 )
 
@@ -34,9 +36,13 @@ func (b BasicStrategy) AuthExecute() gin.HandlerFunc {
 			return
 		}
 
+		_, span := tracing.Start(c.Request.Context(), "auth.basic")
 		payload, _ := base64.StdEncoding.DecodeString(auth[1])
 		pair := strings.SplitN(string(payload), ":", 2)
 		if len(pair) != 2 || !b.compare(pair[0], pair[1]) {
+			err := errors.New("invalid username or password")
+			tracing.End(span, err)
+			metrics.ObserveLogin("basic", err)
 			httpcore.WriteResponse(c,
 				errors.WithCode(code.ErrSignatureInvalid, "Authorization header format is wrong."),
 				nil)
@@ -44,6 +50,8 @@ func (b BasicStrategy) AuthExecute() gin.HandlerFunc {
 			return
 		}
 
+		span.End()
+		metrics.ObserveLogin("basic", nil)
 		c.Set(middleware.UserNameKey, pair[0])
 		c.Next()
 	}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v4"
+)
+
+// BearerStrategy 根据 token 头部选择 Bearer 认证策略：
+// 带有 kid 的 token 使用用户密钥签发，由 CacheStrategy 校验，其余为登录获得的 token，由 JWTStrategy 校验
+type BearerStrategy struct {
+	jwt   middleware.AuthStrategy
+	cache middleware.AuthStrategy
+}
+
+var _ middleware.AuthStrategy = BearerStrategy{}
+
+// NewBearerStrategy create bearer strategy
+func NewBearerStrategy(jwt JWTStrategy, cache CacheStrategy) BearerStrategy {
+	return BearerStrategy{jwt: jwt, cache: cache}
+}
+
+func (b BearerStrategy) AuthExecute() gin.HandlerFunc {
+	jwt, cache := b.jwt.AuthExecute(), b.cache.AuthExecute()
+
+	return func(c *gin.Context) {
+		if hasKeyID(c.Request.Header.Get("Authorization")) {
+			cache(c)
+
+			return
+		}
+		jwt(c)
+	}
+}
+
+// hasKeyID 判断 Authorization 中的 Bearer token 头部是否带有 kid，不校验签名
+func hasKeyID(header string) bool {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return false
+	}
+	t, _, err := gojwt.NewParser().ParseUnverified(token, gojwt.MapClaims{})
+	if err != nil {
+		return false
+	}
+	_, ok = t.Header["kid"].(string)
+
+	return ok
+}
@@ -1 +1,120 @@
 package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/metrics"
+	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/ahang7/go-IAM/pkg/tracing"
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v4"
+)
+
+// Secret 校验 token 所需的密钥信息
+type Secret struct {
+	Username string
+	ID       string
+	Key      string
+	// Expires 过期时间的 unix 时间戳，0 表示永不过期
+	Expires int64
+}
+
+// CacheStrategy 使用用户密钥进行 HMAC 签名校验的 Bearer 认证策略。
+// token 头部的 kid 为 SecretID，签名密钥为对应的 SecretKey，audience 必须为 AuthzAudience
+type CacheStrategy struct {
+	get func(ctx context.Context, kid string) (Secret, error)
+}
+
+var (
+	_ middleware.AuthStrategy       = &CacheStrategy{}
+	_ middleware.CredentialVerifier = &CacheStrategy{}
+)
+
+// NewCacheStrategy create cache strategy with function which can list and cache secret.
+func NewCacheStrategy(get func(ctx context.Context, kid string) (Secret, error)) CacheStrategy {
+	return CacheStrategy{get: get}
+}
+
+func (cache CacheStrategy) AuthExecute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Request.Header.Get("Authorization")
+		if len(header) == 0 {
+			httpcore.WriteResponse(c, errors.WithCode(code.ErrMissingHeader, "Authorization header cannot be empty."), nil)
+			c.Abort()
+
+			return
+		}
+
+		parts := strings.SplitN(header, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			httpcore.WriteResponse(c, errors.WithCode(code.ErrInvalidAuthHeader, "Authorization header format is wrong."), nil)
+			c.Abort()
+
+			return
+		}
+
+		username, err := cache.Verify(c.Request.Context(), parts[1])
+		if err != nil {
+			httpcore.WriteResponse(c, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		c.Set(middleware.UserNameKey, username)
+		c.Next()
+	}
+}
+
+// Verify 校验使用用户密钥签名的 token，返回密钥所属的用户名
+func (cache CacheStrategy) Verify(ctx context.Context, rawJWT string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "auth.secret")
+	defer func() {
+		tracing.End(span, err)
+		metrics.ObserveLogin("secret", err)
+	}()
+
+	var secret Secret
+	claims := gojwt.MapClaims{}
+	_, err = gojwt.ParseWithClaims(rawJWT, claims, func(token *gojwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*gojwt.SigningMethodHMAC); !ok {
+			return nil, errors.WithCode(code.ErrSignatureInvalid, "unexpected signing method: %v", token.Header["alg"])
+		}
+
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, errors.WithCode(code.ErrTokenInvalid, "missing kid in token header")
+		}
+
+		var err error
+		secret, err = cache.get(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		return []byte(secret.Key), nil
+	})
+	if err != nil {
+		// keyFunc 返回的错误已经带有错误码
+		var ve *gojwt.ValidationError
+		if errors.As(err, &ve) && ve.Errors&gojwt.ValidationErrorUnverifiable != 0 && ve.Inner != nil {
+			return "", ve.Inner
+		}
+
+		return "", tokenError(err)
+	}
+
+	if !claims.VerifyAudience(AuthzAudience, true) {
+		return "", errors.WithCode(code.ErrTokenInvalid, "invalid token audience")
+	}
+	if secret.Expires != 0 && time.Now().Unix() > secret.Expires {
+		return "", errors.WithCode(code.ErrExpired, "secret %s expired", secret.ID)
+	}
+
+	return secret.Username, nil
+}
@@ -1,9 +1,16 @@
 package auth
 
 import (
+	"context"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/metrics"
 	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/ahang7/go-IAM/pkg/tracing"
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v4"
 )
 
 // AuthzAudience defines the audience of the token
@@ -13,7 +20,10 @@ type JWTStrategy struct {
 	jwt.GinJWTMiddleware
 }
 
-var _ middleware.AuthStrategy = &JWTStrategy{}
+var (
+	_ middleware.AuthStrategy       = &JWTStrategy{}
+	_ middleware.CredentialVerifier = &JWTStrategy{}
+)
 
 // NewJWTStrategy creates a new JWT strategy
 func NewJWTStrategy(gjwt jwt.GinJWTMiddleware) JWTStrategy {
@@ -21,5 +31,59 @@ func NewJWTStrategy(gjwt jwt.GinJWTMiddleware) JWTStrategy {
 }
 
 func (j JWTStrategy) AuthExecute() gin.HandlerFunc {
-	return j.MiddlewareFunc()
+	// gin-jwt 校验通过后在 Authorizator 之后直接调用 c.Next，span 在 Authorizator 或 Unauthorized 回调中结束
+	mw := j.GinJWTMiddleware
+	authorizator, unauthorized := mw.Authorizator, mw.Unauthorized
+	mw.Authorizator = func(data any, c *gin.Context) bool {
+		ok := authorizator(data, c)
+		if ok {
+			endSpan(c, nil)
+			metrics.ObserveLogin("jwt", nil)
+		}
+
+		return ok
+	}
+	mw.Unauthorized = func(c *gin.Context, code int, message string) {
+		err := errors.New(message)
+		endSpan(c, err)
+		metrics.ObserveLogin("jwt", err)
+		unauthorized(c, code, message)
+	}
+	handler := mw.MiddlewareFunc()
+
+	return func(c *gin.Context) {
+		startSpan(c, "auth.jwt")
+		handler(c)
+	}
+}
+
+// Verify 使用与 gin 中间件相同的密钥和签名算法校验 token，返回 token 中的用户名
+func (j JWTStrategy) Verify(ctx context.Context, token string) (_ string, err error) {
+	_, span := tracing.Start(ctx, "auth.jwt")
+	defer func() {
+		tracing.End(span, err)
+		metrics.ObserveLogin("jwt", err)
+	}()
+
+	t, err := j.ParseTokenString(token)
+	if err != nil {
+		return "", tokenError(err)
+	}
+
+	username, _ := jwt.ExtractClaimsFromToken(t)[jwt.IdentityKey].(string)
+	if username == "" {
+		return "", errors.WithCode(code.ErrTokenInvalid, "token has no identity")
+	}
+
+	return username, nil
+}
+
+// tokenError 将 jwt 解析错误转换为错误码
+func tokenError(err error) error {
+	var ve *gojwt.ValidationError
+	if errors.As(err, &ve) && ve.Errors&gojwt.ValidationErrorExpired != 0 {
+		return errors.WrapC(err, code.ErrExpired, "token expired")
+	}
+
+	return errors.WrapC(err, code.ErrTokenInvalid, "invalid token")
 }
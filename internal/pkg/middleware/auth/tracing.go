@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"github.com/ahang7/go-IAM/pkg/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanKey 保存认证 span 的 gin.Context key
+const spanKey = "auth.span"
+
+// startSpan 为认证过程创建 span。认证策略校验通过后会直接调用 c.Next 处理请求，
+// 因此 span 不在 handler 返回时结束，而是在校验结束时由 endSpan 结束
+func startSpan(c *gin.Context, name string) {
+	_, span := tracing.Start(c.Request.Context(), name)
+	c.Set(spanKey, span)
+}
+
+// endSpan 结束 startSpan 创建的 span，重复调用时忽略
+func endSpan(c *gin.Context, err error) {
+	if span, ok := c.Value(spanKey).(trace.Span); ok && span.IsRecording() {
+		tracing.End(span, err)
+	}
+}
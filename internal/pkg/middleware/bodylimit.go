@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimitConfig 请求体大小限制配置
+type BodyLimitConfig struct {
+	// MaxBytes 请求体最大字节数，小于等于0时不限制
+	MaxBytes int64 `json:"max-bytes" mapstructure:"max-bytes"`
+}
+
+// NewBodyLimitConfig 返回默认的请求体大小限制配置
+func NewBodyLimitConfig() BodyLimitConfig {
+	return BodyLimitConfig{
+		MaxBytes: 4 << 20, // 4MiB
+	}
+}
+
+// BodyLimit 限制请求体大小，Content-Length 超过限制时直接返回 code.ErrRequestTooLarge，
+// 否则通过 http.MaxBytesReader 在读取时限制
+func BodyLimit(cfg BodyLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.MaxBytes <= 0 || c.Request.Body == nil {
+			c.Next()
+
+			return
+		}
+
+		if c.Request.ContentLength > cfg.MaxBytes {
+			httpcore.WriteResponse(c,
+				errors.WithCode(code.ErrRequestTooLarge, "request body exceeds %d bytes", cfg.MaxBytes),
+				nil,
+			)
+			c.Abort()
+
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, cfg.MaxBytes)
+		c.Next()
+	}
+}
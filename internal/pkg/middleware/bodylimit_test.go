@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Use(BodyLimit(BodyLimitConfig{MaxBytes: 8}))
+	var readErr error
+	e.POST("/", func(c *gin.Context) {
+		_, readErr = io.ReadAll(c.Request.Body)
+		c.Status(http.StatusOK)
+	})
+
+	// Content-Length 超过限制时不调用 handler
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789")))
+	var resp httpcore.ErrResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusRequestEntityTooLarge || resp.ErrorCode != code.ErrRequestTooLarge {
+		t.Errorf("oversized body = %d %+v, want 413 with ErrRequestTooLarge", w.Code, resp)
+	}
+
+	// 未知长度的请求体在读取时限制
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader("0123456789")))
+	req.ContentLength = -1
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	var maxErr *http.MaxBytesError
+	if !errors.As(readErr, &maxErr) {
+		t.Errorf("reading an oversized chunked body = %v, want *http.MaxBytesError", readErr)
+	}
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("01234567")))
+	if w.Code != http.StatusOK || readErr != nil {
+		t.Errorf("body within the limit = %d, %v", w.Code, readErr)
+	}
+}
@@ -1,6 +1,10 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
 
 const (
 	UserNameKey = "username"
@@ -13,3 +17,16 @@ func Context() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// WithUsername 返回携带认证用户名的 context，用于 gin 之外的场景（如 gRPC）
+func WithUsername(ctx context.Context, username string) context.Context {
+	//nolint:staticcheck // 与 gin.Context 及 log.L 使用相同的 key
+	return context.WithValue(ctx, UserNameKey, username)
+}
+
+// UsernameFrom 从 context 中获取认证用户名，同时支持 gin.Context
+func UsernameFrom(ctx context.Context) string {
+	username, _ := ctx.Value(UserNameKey).(string)
+
+	return username
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CorsConfig 跨域资源共享配置
+type CorsConfig struct {
+	AllowOrigins     []string      `json:"allow-origins" mapstructure:"allow-origins"`
+	AllowMethods     []string      `json:"allow-methods" mapstructure:"allow-methods"`
+	AllowHeaders     []string      `json:"allow-headers" mapstructure:"allow-headers"`
+	ExposeHeaders    []string      `json:"expose-headers" mapstructure:"expose-headers"`
+	AllowCredentials bool          `json:"allow-credentials" mapstructure:"allow-credentials"`
+	MaxAge           time.Duration `json:"max-age" mapstructure:"max-age"`
+}
+
+// NewCorsConfig 返回默认的跨域配置，默认允许所有来源
+func NewCorsConfig() CorsConfig {
+	return CorsConfig{
+		AllowOrigins:     []string{"*"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", XRequestIDKey},
+		ExposeHeaders:    []string{"Content-Length", XRequestIDKey},
+		AllowCredentials: false,
+		MaxAge:           12 * time.Hour,
+	}
+}
+
+// Validate 校验允许的来源，gin-contrib/cors 遇到不带协议或包含多个 * 的来源时会 panic
+func (cfg CorsConfig) Validate() error {
+	if allowAllOrigins(cfg.AllowOrigins) {
+		return nil
+	}
+	for _, origin := range cfg.AllowOrigins {
+		switch n := strings.Count(origin, "*"); {
+		case n > 1:
+			return fmt.Errorf("origin %q must contain at most one '*'", origin)
+		case n == 0 && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://"):
+			return fmt.Errorf("origin %q must start with http:// or https://", origin)
+		}
+	}
+
+	return cfg.config().Validate()
+}
+
+func (cfg CorsConfig) config() cors.Config {
+	c := cors.Config{
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
+	if allowAllOrigins(cfg.AllowOrigins) {
+		c.AllowAllOrigins = true
+	} else {
+		c.AllowOrigins = cfg.AllowOrigins
+		c.AllowWildcard = true
+	}
+
+	return c
+}
+
+func allowAllOrigins(origins []string) bool {
+	return len(origins) == 0 || (len(origins) == 1 && origins[0] == "*")
+}
+
+// Cors 返回跨域中间件，配置错误时返回错误而不是 panic
+func Cors(cfg CorsConfig) (mw gin.HandlerFunc, err error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			mw, err = nil, fmt.Errorf("invalid cors config: %v", r)
+		}
+	}()
+
+	return cors.New(cfg.config()), nil
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCors(t *testing.T) {
+	cfg := NewCorsConfig()
+	cfg.AllowOrigins = []string{"https://*.example.com"}
+
+	mw, err := Cors(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Use(mw)
+	e.PUT("/v1/users/:name", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/v1/users/colin", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+		req.Header.Set("Access-Control-Request-Headers", "Authorization")
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := preflight("https://console.example.com")
+	h := w.Header()
+	if w.Code != http.StatusNoContent || h.Get("Access-Control-Allow-Origin") != "https://console.example.com" {
+		t.Errorf("preflight from an allowed origin = %d %v", w.Code, h)
+	}
+	if h.Get("Access-Control-Allow-Methods") == "" || h.Get("Access-Control-Max-Age") != "43200" {
+		t.Errorf("preflight headers = %v", h)
+	}
+
+	if w := preflight("https://evil.com"); w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("preflight from a disallowed origin = %d %v", w.Code, w.Header())
+	}
+}
+
+func TestCors_InvalidOrigins(t *testing.T) {
+	for _, origins := range [][]string{
+		{"example.com"},
+		{"https://*.*.example.com"},
+		{"https://example.com", "console.example.com"},
+	} {
+		cfg := NewCorsConfig()
+		cfg.AllowOrigins = origins
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate(%q) should fail", origins)
+		}
+		if mw, err := Cors(cfg); err == nil || mw != nil {
+			t.Errorf("Cors(%q) = %v, want an error", origins, err)
+		}
+	}
+}
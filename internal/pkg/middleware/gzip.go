@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"compress/gzip"
+
+	ginzip "github.com/gin-contrib/gzip"
+	"github.com/gin-gonic/gin"
+)
+
+// GzipConfig 响应压缩配置
+type GzipConfig struct {
+	// Level 压缩级别，-1 为默认级别，1~9 数值越大压缩率越高
+	Level              int      `json:"level" mapstructure:"level"`
+	ExcludedExtensions []string `json:"excluded-extensions" mapstructure:"excluded-extensions"`
+	ExcludedPaths      []string `json:"excluded-paths" mapstructure:"excluded-paths"`
+}
+
+// NewGzipConfig 返回默认的响应压缩配置
+func NewGzipConfig() GzipConfig {
+	return GzipConfig{
+		Level:              gzip.DefaultCompression,
+		ExcludedExtensions: []string{".png", ".gif", ".jpeg", ".jpg"},
+		ExcludedPaths:      []string{"/metrics", "/debug/pprof"},
+	}
+}
+
+// Gzip 返回响应压缩中间件
+func Gzip(cfg GzipConfig) gin.HandlerFunc {
+	return ginzip.Gzip(cfg.Level,
+		ginzip.WithExcludedExtensions(cfg.ExcludedExtensions),
+		ginzip.WithExcludedPaths(cfg.ExcludedPaths),
+	)
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGzip(t *testing.T) {
+	body := strings.Repeat("iam ", 256)
+
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Use(Gzip(NewGzipConfig()))
+	for _, path := range []string{"/v1/users", "/metrics"} {
+		e.GET(path, func(c *gin.Context) {
+			c.String(http.StatusOK, body)
+		})
+	}
+
+	get := func(path, encoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if encoding != "" {
+			req.Header.Set("Accept-Encoding", encoding)
+		}
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := get("/v1/users", "gzip, deflate")
+	if w.Header().Get("Content-Encoding") != "gzip" || w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("response headers = %v, want gzip encoding", w.Header())
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := io.ReadAll(zr); err != nil || string(data) != body {
+		t.Errorf("decompressed body = %d bytes, %v", len(data), err)
+	}
+
+	for _, tt := range []struct{ path, encoding string }{
+		{"/v1/users", ""},
+		{"/v1/users", "br"},
+		{"/metrics", "gzip"},
+	} {
+		w := get(tt.path, tt.encoding)
+		if w.Header().Get("Content-Encoding") != "" || w.Body.String() != body {
+			t.Errorf("GET %s with Accept-Encoding %q = %v, want an uncompressed body", tt.path, tt.encoding, w.Header())
+		}
+	}
+}
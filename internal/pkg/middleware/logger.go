@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"math/rand/v2"
+	"path"
+	"time"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+)
+
+// LoggerConfig 访问日志配置
+type LoggerConfig struct {
+	// SkipPaths 不记录访问日志的路径，与 gin 注册的路由（如 /v1/users/:name）或请求路径进行 path.Match 匹配
+	SkipPaths []string `json:"skip-paths" mapstructure:"skip-paths"`
+	// SampleRate 成功请求的采样比例，取值 0 到 1。失败请求和慢请求总是记录
+	SampleRate float64 `json:"sample-rate" mapstructure:"sample-rate"`
+	// SlowThreshold 耗时超过该值的请求以 warn 级别记录并标记为 slow，0 表示不检测慢请求
+	SlowThreshold time.Duration `json:"slow-threshold" mapstructure:"slow-threshold"`
+}
+
+// NewLoggerConfig 返回默认的访问日志配置
+func NewLoggerConfig() LoggerConfig {
+	return LoggerConfig{
+		SkipPaths:     []string{"/healthz", "/readyz"},
+		SampleRate:    1,
+		SlowThreshold: time.Second,
+	}
+}
+
+// Logger 返回结构化访问日志中间件，每个请求输出一条日志。
+// 同时将携带 requestID 的日志记录器保存到请求的 context 中，handler 通过 log.L(c) 获取，
+// 认证通过后 log.L 会追加 username
+func Logger(cfg LoggerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		rid := c.Writer.Header().Get(XRequestIDKey)
+		c.Request = c.Request.WithContext(log.WithValues("requestID", rid).WithContext(c.Request.Context()))
+
+		c.Next()
+
+		route := c.FullPath()
+		if skipPath(cfg.SkipPaths, route, c.Request.URL.Path) {
+			return
+		}
+
+		status, latency := c.Writer.Status(), time.Since(start)
+		slow := cfg.SlowThreshold > 0 && latency >= cfg.SlowThreshold
+		if status < 400 && !slow && cfg.SampleRate < 1 && rand.Float64() >= cfg.SampleRate {
+			return
+		}
+
+		fields := []any{
+			"method", c.Request.Method,
+			"route", route,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency", latency,
+			"bytes", max(c.Writer.Size(), 0),
+			"clientIP", c.ClientIP(),
+		}
+		if slow {
+			fields = append(fields, "slow", true)
+		}
+		if errs := c.Errors.ByType(gin.ErrorTypePrivate).String(); errs != "" {
+			fields = append(fields, "errors", errs)
+		}
+
+		lg := log.L(c)
+		switch {
+		case status >= 500:
+			lg.Errorw("access", fields...)
+		case status >= 400 || slow:
+			lg.Warnw("access", fields...)
+		default:
+			lg.Infow("access", fields...)
+		}
+	}
+}
+
+// skipPath 判断路由或请求路径是否匹配 patterns 中的任意一个
+func skipPath(patterns []string, route, reqPath string) bool {
+	for _, p := range patterns {
+		if matched, _ := path.Match(p, route); matched && route != "" {
+			return true
+		}
+		if matched, _ := path.Match(p, reqPath); matched {
+			return true
+		}
+	}
+
+	return false
+}
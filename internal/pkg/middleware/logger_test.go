@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	std := log.Default()
+	log.ReplaceDefault(log.New(&buf, log.InfoLevel))
+	defer log.ReplaceDefault(std)
+
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.ContextWithFallback = true
+	e.Use(RequestID(), Context(), Logger(LoggerConfig{SkipPaths: []string{"/healthz"}, SampleRate: 0, SlowThreshold: 50 * time.Millisecond}))
+	e.GET("/v1/users/:name", func(c *gin.Context) {
+		c.Set(UserNameKey, "admin")
+		log.L(c).Info("get user")
+		c.String(http.StatusOK, "ok")
+	})
+	e.GET("/slow", func(c *gin.Context) {
+		time.Sleep(60 * time.Millisecond)
+	})
+	e.GET("/healthz", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+
+	get := func(path string) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(XRequestIDKey, "rid-1")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	get("/v1/users/colin")
+	get("/slow")
+	get("/healthz")
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		r := map[string]any{}
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+		records = append(records, r)
+	}
+
+	// handler 日志携带 requestID 和 username，成功请求的访问日志被采样掉，慢请求总是记录，/healthz 被跳过
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d:\n%s", len(records), buf.String())
+	}
+	if r := records[0]; r["msg"] != "get user" || r["requestID"] != "rid-1" || r["username"] != "admin" {
+		t.Errorf("unexpected handler record %v", r)
+	}
+	if r := records[1]; r["msg"] != "access" || r["level"] != "warn" || r["slow"] != true ||
+		r["route"] != "/slow" || r["status"] != float64(http.StatusOK) || r["requestID"] != "rid-1" {
+		t.Errorf("unexpected access record %v", r)
+	}
+}
@@ -1,9 +1,106 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"fmt"
+	"sort"
 
+	"github.com/gin-gonic/gin"
+)
+
+// Middlewares 以名称注册的中间件，GenericServer 根据配置中的 server.middlewares 按名称启用
 var Middlewares = defaultMiddlewares()
 
+// builder 根据配置构建内置中间件，配置错误时返回错误
+type builder func(cfg *Config) (gin.HandlerFunc, error)
+
+// builtins 内置中间件的构建函数
+var builtins = map[string]builder{
+	"recovery":  func(*Config) (gin.HandlerFunc, error) { return Recovery(), nil },
+	"cors":      func(cfg *Config) (gin.HandlerFunc, error) { return Cors(cfg.Cors) },
+	"secure":    func(cfg *Config) (gin.HandlerFunc, error) { return Secure(cfg.Secure), nil },
+	"timeout":   func(cfg *Config) (gin.HandlerFunc, error) { return Timeout(cfg.Timeout), nil },
+	"bodylimit": func(cfg *Config) (gin.HandlerFunc, error) { return BodyLimit(cfg.BodyLimit), nil },
+	"gzip":      func(cfg *Config) (gin.HandlerFunc, error) { return Gzip(cfg.Gzip), nil },
+	"logger":    func(cfg *Config) (gin.HandlerFunc, error) { return Logger(cfg.Logger), nil },
+}
+
+// defaultMiddlewares 使用默认配置构建内置中间件，默认配置一定有效
 func defaultMiddlewares() map[string]gin.HandlerFunc {
-	return map[string]gin.HandlerFunc{}
+	cfg := NewConfig()
+	mws := make(map[string]gin.HandlerFunc, len(builtins))
+	for name, build := range builtins {
+		mw, err := build(cfg)
+		if err != nil {
+			panic(fmt.Sprintf("build default middleware %s: %s", name, err.Error()))
+		}
+		mws[name] = mw
+	}
+
+	return mws
+}
+
+// Register 注册一个自定义中间件，同名时覆盖
+func Register(name string, mw gin.HandlerFunc) {
+	Middlewares[name] = mw
+}
+
+// Build 返回 names 中已注册的中间件，内置中间件按 cfg 重新构建，自定义中间件保持不变，
+// 只构建启用的中间件，内置中间件配置错误时返回错误。未注册的名称不在返回值中，由调用方处理。
+// ratelimit 中间件持有存储，不在集合中，由调用方通过 NewRateLimiter 创建
+func Build(cfg *Config, names []string) (map[string]gin.HandlerFunc, error) {
+	mws := make(map[string]gin.HandlerFunc, len(names))
+	for _, name := range names {
+		if build, ok := builtins[name]; ok && cfg != nil {
+			mw, err := build(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("middleware %s: %w", name, err)
+			}
+			mws[name] = mw
+
+			continue
+		}
+		if mw, ok := Middlewares[name]; ok {
+			mws[name] = mw
+		}
+	}
+
+	return mws, nil
+}
+
+// Names 返回已注册的中间件名称
+func Names() []string {
+	names := make([]string, 0, len(Middlewares)+1)
+	for name := range Middlewares {
+		names = append(names, name)
+	}
+	if _, ok := Middlewares[RateLimitName]; !ok {
+		names = append(names, RateLimitName)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Config 内置中间件的配置，每个中间件对应一个配置段
+type Config struct {
+	Cors      CorsConfig      `json:"cors" mapstructure:"cors"`
+	Secure    SecureConfig    `json:"secure" mapstructure:"secure"`
+	Timeout   TimeoutConfig   `json:"timeout" mapstructure:"timeout"`
+	BodyLimit BodyLimitConfig `json:"body-limit" mapstructure:"body-limit"`
+	Gzip      GzipConfig      `json:"gzip" mapstructure:"gzip"`
+	Logger    LoggerConfig    `json:"logger" mapstructure:"logger"`
+	RateLimit RateLimitConfig `json:"ratelimit" mapstructure:"ratelimit"`
+}
+
+// NewConfig 返回内置中间件的默认配置
+func NewConfig() *Config {
+	return &Config{
+		Cors:      NewCorsConfig(),
+		Secure:    NewSecureConfig(),
+		Timeout:   NewTimeoutConfig(),
+		BodyLimit: NewBodyLimitConfig(),
+		Gzip:      NewGzipConfig(),
+		Logger:    NewLoggerConfig(),
+		RateLimit: NewRateLimitConfig(),
+	}
 }
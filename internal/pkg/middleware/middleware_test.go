@@ -0,0 +1,21 @@
+package middleware
+
+import "testing"
+
+func TestBuild(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Cors.AllowOrigins = []string{"example.com"}
+
+	// 没有启用的中间件不会被构建，错误的跨域配置不影响其他中间件
+	mws, err := Build(cfg, []string{"recovery", "secure", "unknown"})
+	if err != nil {
+		t.Fatalf("Build() without cors = %v", err)
+	}
+	if len(mws) != 2 || mws["recovery"] == nil || mws["secure"] == nil {
+		t.Errorf("Build() = %v, want recovery and secure only", mws)
+	}
+
+	if _, err := Build(cfg, []string{"recovery", "cors"}); err == nil {
+		t.Error("Build() with an invalid cors origin should fail")
+	}
+}
@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/ahang7/go-IAM/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitName 限流中间件的名称，限流器持有存储，由 GenericServer 创建并在热加载、关闭时释放
+const RateLimitName = "ratelimit"
+
+// 限流 key 的来源
+const (
+	RateLimitKeyByIP    = "ip"
+	RateLimitKeyByUser  = "user"
+	RateLimitKeyByRoute = "route"
+)
+
+// 限流存储后端
+const (
+	RateLimitBackendMemory = "memory"
+	RateLimitBackendRedis  = "redis"
+)
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	// Backend 令牌桶存储后端，memory 只适用于单副本部署，多副本部署使用 redis
+	Backend string               `json:"backend" mapstructure:"backend"`
+	Redis   RateLimitRedisConfig `json:"redis" mapstructure:"redis"`
+	// Rules 按路由匹配的限流规则，按顺序匹配第一条
+	Rules []RateLimitRule `json:"rules" mapstructure:"rules"`
+}
+
+// RateLimitRedisConfig 限流使用的 Redis 配置
+type RateLimitRedisConfig struct {
+	Addrs     []string `json:"addrs" mapstructure:"addrs"`
+	Password  string   `json:"password" mapstructure:"password" log:"redact"`
+	DB        int      `json:"db" mapstructure:"db"`
+	KeyPrefix string   `json:"key-prefix" mapstructure:"key-prefix"`
+}
+
+// RateLimitRule 一条限流规则
+type RateLimitRule struct {
+	// Path 路由模式，与 gin 注册的路由（如 /v1/users/:name）或请求路径进行 path.Match 匹配
+	Path string `json:"path" mapstructure:"path"`
+	// Method 请求方法，为空时匹配所有方法
+	Method string `json:"method" mapstructure:"method"`
+	// KeyBy 限流 key 的来源：ip、user、route。
+	// user 规则只由安装在认证之后的 RateLimitByUser 处理，按认证中间件设置的 UserNameKey 限流
+	KeyBy string `json:"key-by" mapstructure:"key-by"`
+	// Rate 每秒补充的令牌数
+	Rate float64 `json:"rate" mapstructure:"rate"`
+	// Burst 允许的最大突发请求数
+	Burst int `json:"burst" mapstructure:"burst"`
+}
+
+// NewRateLimitConfig 返回默认的限流配置，默认对登录和刷新 token 按客户端 IP 限流
+func NewRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Backend: RateLimitBackendMemory,
+		Redis: RateLimitRedisConfig{
+			Addrs:     []string{"127.0.0.1:6379"},
+			KeyPrefix: "iam:ratelimit:",
+		},
+		Rules: []RateLimitRule{
+			{Path: "/login", Method: "POST", KeyBy: RateLimitKeyByIP, Rate: 1, Burst: 5},
+			{Path: "/refresh", Method: "POST", KeyBy: RateLimitKeyByIP, Rate: 1, Burst: 5},
+		},
+	}
+}
+
+// RateLimiter 限流中间件使用的令牌桶存储，Redis 后端持有的客户端在 Close 时关闭
+type RateLimiter struct {
+	backend string
+	redis   RateLimitRedisConfig
+	store   ratelimit.Store
+	client  redis.UniversalClient
+}
+
+// NewRateLimiter 根据配置的存储后端创建限流器，不再使用时需要调用 Close
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	l := &RateLimiter{backend: cfg.Backend, redis: cfg.Redis}
+	if cfg.Backend == RateLimitBackendRedis {
+		l.client = redis.NewUniversalClient(&redis.UniversalOptions{
+			Addrs:    cfg.Redis.Addrs,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		l.store = ratelimit.NewRedisStore(l.client, cfg.Redis.KeyPrefix)
+
+		return l
+	}
+	l.store = ratelimit.NewMemoryStore()
+
+	return l
+}
+
+// Reusable 判断 cfg 的存储配置与 l 是否相同，相同时热加载可以继续使用 l，只更新限流规则
+func (l *RateLimiter) Reusable(cfg RateLimitConfig) bool {
+	if l.backend != cfg.Backend {
+		return false
+	}
+	if l.backend != RateLimitBackendRedis {
+		return true
+	}
+
+	return slices.Equal(l.redis.Addrs, cfg.Redis.Addrs) && l.redis.Password == cfg.Redis.Password &&
+		l.redis.DB == cfg.Redis.DB && l.redis.KeyPrefix == cfg.Redis.KeyPrefix
+}
+
+// Handler 返回全局限流中间件，请求被拒绝时返回 429 及 Retry-After、X-RateLimit-* 响应头。
+// 全局中间件安装在认证之前，只处理按 ip、route 限流的规则；按 user 限流的规则由 RateLimitByUser 在认证之后处理
+func (l *RateLimiter) Handler(rules []RateLimitRule) gin.HandlerFunc {
+	var global, byUser []RateLimitRule
+	for _, r := range rules {
+		if r.KeyBy == RateLimitKeyByUser {
+			byUser = append(byUser, r)
+		} else {
+			global = append(global, r)
+		}
+	}
+	globalLimit := RateLimitWithStore(global, l.store)
+	userLimit := RateLimitWithStore(byUser, l.store)
+
+	return func(c *gin.Context) {
+		c.Set(userRateLimitKey, userLimit)
+		globalLimit(c)
+	}
+}
+
+// Close 关闭 Redis 客户端，memory 后端不需要关闭
+func (l *RateLimiter) Close() error {
+	if l.client == nil {
+		return nil
+	}
+
+	return l.client.Close()
+}
+
+// userRateLimitKey 全局限流中间件在 gin.Context 中保存按用户限流的中间件
+const userRateLimitKey = "iam.ratelimit.user"
+
+// RateLimitByUser 返回按认证用户限流的中间件，需要安装在认证中间件之后的路由组上。
+// 使用全局 ratelimit 中间件配置中 key-by 为 user 的规则，未启用 ratelimit 中间件时不限流
+func RateLimitByUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit, ok := c.Get(userRateLimitKey); ok {
+			limit.(gin.HandlerFunc)(c)
+
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimitWithStore 使用指定的存储创建限流中间件，按顺序匹配第一条规则，存储后端出错时放行请求并记录日志
+func RateLimitWithStore(rules []RateLimitRule, store ratelimit.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+
+		rule, ok := matchRateLimitRule(rules, c.Request.Method, route)
+		if !ok {
+			c.Next()
+
+			return
+		}
+
+		limit := ratelimit.Limit{Rate: rule.Rate, Burst: rule.Burst}
+		res, err := store.Allow(c.Request.Context(), rateLimitKey(c, rule, route), limit)
+		if err != nil {
+			log.Errorw("rate limit store failed, request allowed", "route", route, "error", err)
+			c.Next()
+
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+
+		if !res.Allowed {
+			h.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			httpcore.WriteResponse(c, errors.WithCode(code.ErrTooManyRequests, "rate limit exceeded on %s", route), nil)
+			c.Abort()
+
+			return
+		}
+		c.Next()
+	}
+}
+
+func matchRateLimitRule(rules []RateLimitRule, method, route string) (RateLimitRule, bool) {
+	for _, r := range rules {
+		if r.Method != "" && !strings.EqualFold(r.Method, method) {
+			continue
+		}
+		if matched, _ := path.Match(r.Path, route); matched {
+			return r, true
+		}
+	}
+
+	return RateLimitRule{}, false
+}
+
+func rateLimitKey(c *gin.Context, rule RateLimitRule, route string) string {
+	key := rule.Method + " " + rule.Path
+	switch rule.KeyBy {
+	case RateLimitKeyByRoute:
+		return key + "|route:" + route
+	case RateLimitKeyByUser:
+		if username := c.GetString(UserNameKey); username != "" {
+			return key + "|user:" + username
+		}
+	}
+
+	return key + "|ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
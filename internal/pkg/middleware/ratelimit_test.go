@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func newRateLimitEngine(l *RateLimiter, rules []RateLimitRule) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	if l != nil {
+		e.Use(l.Handler(rules))
+	}
+	e.POST("/login", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	// 模拟认证中间件
+	authed := e.Group("", func(c *gin.Context) {
+		c.Set(UserNameKey, c.GetHeader("X-User"))
+	}, RateLimitByUser())
+	authed.GET("/v1/users/:name", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	return e
+}
+
+func TestRateLimit(t *testing.T) {
+	rules := []RateLimitRule{
+		{Path: "/login", Method: http.MethodPost, KeyBy: RateLimitKeyByIP, Rate: 1, Burst: 1},
+		{Path: "/v1/users/*", KeyBy: RateLimitKeyByUser, Rate: 1, Burst: 1},
+	}
+	l := NewRateLimiter(RateLimitConfig{Backend: RateLimitBackendMemory})
+	e := newRateLimitEngine(l, rules)
+
+	do := func(method, path, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := do(http.MethodPost, "/login", "")
+	if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "1" || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("first login = %d %v", w.Code, w.Header())
+	}
+	w = do(http.MethodPost, "/login", "")
+	var resp httpcore.ErrResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusTooManyRequests || resp.ErrorCode != code.ErrTooManyRequests {
+		t.Fatalf("second login = %d %+v, want 429 with ErrTooManyRequests", w.Code, resp)
+	}
+	if w.Header().Get("Retry-After") != "1" || w.Header().Get("X-RateLimit-Reset") != "1" {
+		t.Errorf("rate limited headers = %v", w.Header())
+	}
+
+	// 按用户限流的规则在认证之后匹配，不同用户互不影响
+	if w := do(http.MethodGet, "/v1/users/colin", "alice"); w.Code != http.StatusOK {
+		t.Fatalf("first request of alice = %d", w.Code)
+	}
+	if w := do(http.MethodGet, "/v1/users/colin", "alice"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("second request of alice = %d, want 429", w.Code)
+	}
+	if w := do(http.MethodGet, "/v1/users/colin", "bob"); w.Code != http.StatusOK {
+		t.Errorf("request of bob = %d, want 200", w.Code)
+	}
+
+	// 未启用 ratelimit 中间件时 RateLimitByUser 不限流
+	e = newRateLimitEngine(nil, nil)
+	for i := 0; i < 3; i++ {
+		if w := do(http.MethodGet, "/v1/users/colin", "alice"); w.Code != http.StatusOK {
+			t.Fatalf("request %d without ratelimit = %d", i, w.Code)
+		}
+	}
+}
+
+func TestRateLimiter_Redis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cfg := NewRateLimitConfig()
+	cfg.Backend = RateLimitBackendRedis
+	cfg.Redis.Addrs = []string{mr.Addr()}
+
+	l := NewRateLimiter(cfg)
+	e := newRateLimitEngine(l, cfg.Rules)
+	for i := 0; i < 6; i++ {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+		if want := i < 5; (w.Code == http.StatusOK) != want {
+			t.Fatalf("login %d = %d", i, w.Code)
+		}
+	}
+
+	if !l.Reusable(cfg) {
+		t.Error("Reusable() with the same config = false")
+	}
+	cfg.Redis.DB = 1
+	if l.Reusable(cfg) || l.Reusable(NewRateLimitConfig()) {
+		t.Error("Reusable() with a different store config = true")
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	// Redis 客户端关闭后请求被放行
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("login after Close = %d, want allowed", w.Code)
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery 捕获 handler 中的 panic，记录日志并返回 code.ErrUnknown
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				// 客户端断开连接时无法再写入响应
+				if isBrokenPipe(err) {
+					log.Warnw("connection broken while handling request",
+						"path", c.Request.URL.Path, "error", err)
+					c.Abort()
+
+					return
+				}
+
+				log.L(c).Errorw("panic recovered",
+					"method", c.Request.Method,
+					"path", c.Request.URL.Path,
+					"error", err,
+					"stack", string(debug.Stack()),
+				)
+				httpcore.WriteResponse(c, errors.WithCode(code.ErrUnknown, "%v", err), nil)
+				c.Abort()
+			}
+		}()
+		c.Next()
+	}
+}
+
+func isBrokenPipe(err any) bool {
+	if errors.Is(toError(err), http.ErrAbortHandler) {
+		return true
+	}
+	var ne *net.OpError
+	if !errors.As(toError(err), &ne) {
+		return false
+	}
+	var se *os.SyscallError
+	if errors.As(ne, &se) {
+		msg := strings.ToLower(se.Error())
+		return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+	}
+
+	return false
+}
+
+func toError(v any) error {
+	if err, ok := v.(error); ok {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	std := log.Default()
+	log.ReplaceDefault(log.New(&buf, log.InfoLevel))
+	defer log.ReplaceDefault(std)
+
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Use(Recovery())
+	e.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	var resp httpcore.ErrResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response %q: %v", w.Body.String(), err)
+	}
+	if w.Code != http.StatusInternalServerError || resp.Code != http.StatusInternalServerError ||
+		resp.ErrorCode != code.ErrUnknown || resp.Msg != "Internal server error" {
+		t.Errorf("panic response = %d %+v, want 500 with ErrUnknown", w.Code, resp)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("panic recovered")) || !bytes.Contains(buf.Bytes(), []byte("boom")) {
+		t.Errorf("panic is not logged:\n%s", buf.String())
+	}
+}
@@ -16,8 +16,8 @@ func RequestID() gin.HandlerFunc {
 		if rid == "" {
 			rid = uuid.Must(uuid.NewV7()).String()
 			ctx.Request.Header.Set(XRequestIDKey, rid)
-			ctx.Set(XRequestIDKey, rid)
 		}
+		ctx.Set(XRequestIDKey, rid)
 		ctx.Writer.Header().Set(XRequestIDKey, rid)
 		ctx.Next()
 	}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutConfig 请求超时配置
+type TimeoutConfig struct {
+	Timeout time.Duration `json:"timeout" mapstructure:"timeout"`
+}
+
+// NewTimeoutConfig 返回默认的请求超时配置
+func NewTimeoutConfig() TimeoutConfig {
+	return TimeoutConfig{
+		Timeout: 30 * time.Second,
+	}
+}
+
+// Timeout 为请求上下文设置截止时间，handler 通过 c.Request.Context() 感知超时。
+// 超时不会中断 handler，中间件等待 handler 返回，不检查 ctx 的 handler 会一直执行到结束；
+// handler 返回时已经超时且尚未写入响应时返回 code.ErrRequestTimeout
+func Timeout(cfg TimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.Timeout <= 0 {
+			c.Next()
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), cfg.Timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			httpcore.WriteResponse(c, errors.WithCode(code.ErrRequestTimeout, "request timeout after %s", cfg.Timeout), nil)
+			c.Abort()
+		}
+	}
+}
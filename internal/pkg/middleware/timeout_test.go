@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Use(Timeout(TimeoutConfig{Timeout: 20 * time.Millisecond}))
+	e.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+	e.GET("/fast", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	var resp httpcore.ErrResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusGatewayTimeout || resp.ErrorCode != code.ErrRequestTimeout {
+		t.Errorf("slow request = %d %+v, want 504 with ErrRequestTimeout", w.Code, resp)
+	}
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Errorf("fast request = %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	oldProvider, oldPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(oldProvider)
+		otel.SetTextMapPropagator(oldPropagator)
+	}()
+
+	var buf bytes.Buffer
+	std := log.Default()
+	log.ReplaceDefault(log.New(&buf, log.InfoLevel))
+	defer log.ReplaceDefault(std)
+
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.ContextWithFallback = true
+	e.Use(Tracing(), RequestID())
+	e.GET("/v1/users/:name", func(c *gin.Context) {
+		log.L(c).Info("get user")
+		c.Status(http.StatusInternalServerError)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/colin", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "GET /v1/users/:name" || span.SpanContext.TraceID().String() != traceID ||
+		span.Parent.SpanID().String() != "00f067aa0ba902b7" || span.Status.Code.String() != "Error" {
+		t.Errorf("unexpected span %s trace %s parent %s status %s",
+			span.Name, span.SpanContext.TraceID(), span.Parent.SpanID(), span.Status.Code)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid record %q: %v", buf.String(), err)
+	}
+	if record["trace_id"] != traceID || record["span_id"] != span.SpanContext.SpanID().String() {
+		t.Errorf("expected log record to carry the span, got %v", record)
+	}
+}
@@ -0,0 +1,23 @@
+// Package model 定义 IAM 的数据模型，同时作为 gorm 的表结构
+package model
+
+import "time"
+
+// ObjectMeta 所有资源共有的元数据
+type ObjectMeta struct {
+	ID        uint64    `json:"id,omitempty" gorm:"primaryKey;autoIncrement;column:id"`
+	Name      string    `json:"name" gorm:"column:name;type:varchar(64);uniqueIndex;not null"`
+	CreatedAt time.Time `json:"createdAt,omitempty" gorm:"column:createdAt"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty" gorm:"column:updatedAt"`
+}
+
+// ListMeta 列表资源的元数据
+type ListMeta struct {
+	TotalCount int64 `json:"totalCount,omitempty"`
+}
+
+// ListOptions 分页查询参数
+type ListOptions struct {
+	Offset *int64 `json:"offset,omitempty" form:"offset"`
+	Limit  *int64 `json:"limit,omitempty" form:"limit"`
+}
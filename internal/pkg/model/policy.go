@@ -0,0 +1,69 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// 授权策略的效果
+const (
+	AllowAccess = "allow"
+	DenyAccess  = "deny"
+)
+
+// Policy 授权策略
+type Policy struct {
+	ObjectMeta `json:",inline"`
+
+	Username  string          `json:"username" gorm:"column:username;type:varchar(64);index"`
+	Statement PolicyStatement `json:"statement" gorm:"column:statement;type:text"`
+}
+
+// TableName 指定 gorm 表名
+func (p *Policy) TableName() string {
+	return "policy"
+}
+
+// PolicyStatement 策略内容，Subjects、Resources、Actions 支持 * 通配符
+type PolicyStatement struct {
+	Description string   `json:"description,omitempty"`
+	Effect      string   `json:"effect"`
+	Subjects    []string `json:"subjects"`
+	Resources   []string `json:"resources"`
+	Actions     []string `json:"actions"`
+}
+
+// Value 实现 driver.Valuer，以 JSON 格式存储
+func (s PolicyStatement) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Scan 实现 sql.Scanner
+func (s *PolicyStatement) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %T for PolicyStatement", value)
+	}
+
+	return json.Unmarshal(data, s)
+}
+
+// PolicyList 策略列表
+type PolicyList struct {
+	ListMeta `json:",inline"`
+
+	Items []*Policy `json:"items"`
+}
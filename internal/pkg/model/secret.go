@@ -0,0 +1,24 @@
+package model
+
+// Secret 用户的密钥对，SecretKey 用于签发 HMAC 签名的 JWT，SecretID 作为 JWT 头部的 kid
+type Secret struct {
+	ObjectMeta `json:",inline"`
+
+	Username    string `json:"username" gorm:"column:username;type:varchar(64);index"`
+	SecretID    string `json:"secretID" gorm:"column:secretID;type:varchar(36);uniqueIndex"`
+	SecretKey   string `json:"secretKey" gorm:"column:secretKey;type:varchar(255)" log:"redact"`
+	Expires     int64  `json:"expires" gorm:"column:expires"`
+	Description string `json:"description" gorm:"column:description;type:varchar(255)"`
+}
+
+// TableName 指定 gorm 表名
+func (s *Secret) TableName() string {
+	return "secret"
+}
+
+// SecretList 密钥列表
+type SecretList struct {
+	ListMeta `json:",inline"`
+
+	Items []*Secret `json:"items"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// User 用户
+type User struct {
+	ObjectMeta `json:",inline"`
+
+	Status    int        `json:"status" gorm:"column:status"`
+	Nickname  string     `json:"nickname" gorm:"column:nickname;type:varchar(30)"`
+	Password  string     `json:"password,omitempty" gorm:"column:password;type:varchar(255);not null" log:"redact"`
+	Email     string     `json:"email" gorm:"column:email;type:varchar(256)"`
+	Phone     string     `json:"phone" gorm:"column:phone;type:varchar(20)"`
+	IsAdmin   bool       `json:"isAdmin" gorm:"column:isAdmin"`
+	LoginedAt *time.Time `json:"loginedAt,omitempty" gorm:"column:loginedAt"`
+}
+
+// TableName 指定 gorm 表名
+func (u *User) TableName() string {
+	return "user"
+}
+
+// UserList 用户列表
+type UserList struct {
+	ListMeta `json:",inline"`
+
+	Items []*User `json:"items"`
+}
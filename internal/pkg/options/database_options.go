@@ -0,0 +1,226 @@
+package options
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ahang7/go-IAM/pkg/db"
+	"github.com/spf13/pflag"
+	"gorm.io/gorm"
+)
+
+// DatabaseOptions 数据库配置，支持 MySQL、PostgreSQL 和 SQLite。
+// 设置 dsn 时忽略 host、username、password、name；SQLite 的 name 为数据库文件路径
+type DatabaseOptions struct {
+	Driver                string             `json:"driver" mapstructure:"driver"`
+	DSN                   string             `json:"dsn,omitempty" mapstructure:"dsn" log:"redact"`
+	Host                  string             `json:"host" mapstructure:"host"`
+	Username              string             `json:"username" mapstructure:"username"`
+	Password              string             `json:"password" mapstructure:"password" log:"redact"`
+	Database              string             `json:"name" mapstructure:"name"`
+	TLS                   DatabaseTLSOptions `json:"tls" mapstructure:"tls"`
+	MaxIdleConnections    int                `json:"max-idle-connections,omitempty" mapstructure:"max-idle-connections"`
+	MaxOpenConnections    int                `json:"max-open-connections,omitempty" mapstructure:"max-open-connections"`
+	MaxConnectionLifeTime time.Duration      `json:"max-connection-life-time,omitempty" mapstructure:"max-connection-life-time"`
+	LogLevel              int                `json:"log-level,omitempty" mapstructure:"log-level"`
+	SlowThreshold         time.Duration      `json:"slow-threshold,omitempty" mapstructure:"slow-threshold"`
+	MigrateOnStartup      bool               `json:"migrate-on-startup" mapstructure:"migrate-on-startup"`
+}
+
+// DatabaseTLSOptions 连接 MySQL、PostgreSQL 的 TLS 配置
+type DatabaseTLSOptions struct {
+	Mode       string `json:"mode" mapstructure:"mode"`
+	CAFile     string `json:"ca-file,omitempty" mapstructure:"ca-file"`
+	CertFile   string `json:"cert-file,omitempty" mapstructure:"cert-file"`
+	KeyFile    string `json:"private-key-file,omitempty" mapstructure:"private-key-file"`
+	ServerName string `json:"server-name,omitempty" mapstructure:"server-name"`
+}
+
+// NewDatabaseOptions 创建默认的数据库配置，默认使用 MySQL
+func NewDatabaseOptions() *DatabaseOptions {
+	return &DatabaseOptions{
+		Driver:                db.DriverMySQL,
+		Host:                  "127.0.0.1:3306",
+		Username:              "",
+		Password:              "",
+		Database:              "",
+		TLS:                   DatabaseTLSOptions{Mode: db.TLSDisable},
+		MaxIdleConnections:    100,
+		MaxOpenConnections:    100,
+		MaxConnectionLifeTime: time.Duration(10) * time.Second,
+		LogLevel:              1, // Silent
+		SlowThreshold:         db.DefaultSlowThreshold,
+	}
+}
+
+// Validate 校验数据库配置，MySQL、PostgreSQL 的 dsn 和 host 都为空时不校验连接相关的配置
+func (o *DatabaseOptions) Validate() []error {
+	var errs []error
+
+	if !slices.Contains(db.Drivers(), o.Driver) {
+		return append(errs, fmt.Errorf("--database.driver %q must be one of %v", o.Driver, db.Drivers()))
+	}
+	if o.Driver == db.DriverSQLite {
+		if o.DSN == "" && o.Database == "" {
+			errs = append(errs, fmt.Errorf("--database.name (the database file) or --database.dsn is required for sqlite"))
+		}
+		if o.TLS.Mode != "" && o.TLS.Mode != db.TLSDisable {
+			errs = append(errs, fmt.Errorf("--database.tls.mode %q is not supported by sqlite", o.TLS.Mode))
+		}
+	} else {
+		if o.DSN == "" && o.Host == "" {
+			return errs
+		}
+		errs = append(errs, o.validateConnection()...)
+		errs = append(errs, o.TLS.Validate()...)
+	}
+
+	if o.MaxOpenConnections <= 0 {
+		errs = append(errs, fmt.Errorf("--database.max-open-connections %d must be greater than 0", o.MaxOpenConnections))
+	}
+	if o.MaxIdleConnections <= 0 {
+		errs = append(errs, fmt.Errorf("--database.max-idle-connections %d must be greater than 0", o.MaxIdleConnections))
+	} else if o.MaxOpenConnections > 0 && o.MaxIdleConnections > o.MaxOpenConnections {
+		errs = append(errs, fmt.Errorf("--database.max-idle-connections %d must not be greater than --database.max-open-connections %d",
+			o.MaxIdleConnections, o.MaxOpenConnections))
+	}
+	if o.MaxConnectionLifeTime < 0 {
+		errs = append(errs, fmt.Errorf("--database.max-connection-life-time %s must not be negative", o.MaxConnectionLifeTime))
+	}
+	if o.LogLevel < 1 || o.LogLevel > 4 {
+		errs = append(errs, fmt.Errorf("--database.log-level %d must be between 1 (silent) and 4 (info), inclusive", o.LogLevel))
+	}
+	if o.SlowThreshold < 0 {
+		errs = append(errs, fmt.Errorf("--database.slow-threshold %s must not be negative", o.SlowThreshold))
+	}
+
+	return errs
+}
+
+// validateConnection 校验 MySQL、PostgreSQL 的连接配置，设置 dsn 时由驱动解析
+func (o *DatabaseOptions) validateConnection() []error {
+	var errs []error
+
+	if o.DSN != "" {
+		return errs
+	}
+	if err := validateHostPort("database.host", o.Host); err != nil {
+		errs = append(errs, err)
+	}
+	if o.Username == "" {
+		errs = append(errs, fmt.Errorf("--database.username cannot be empty"))
+	}
+	if o.Database == "" {
+		errs = append(errs, fmt.Errorf("--database.name cannot be empty"))
+	}
+
+	return errs
+}
+
+// Validate 校验 TLS 模式以及证书文件
+func (o *DatabaseTLSOptions) Validate() []error {
+	var errs []error
+
+	if o.Mode != "" && !slices.Contains(db.TLSModes(), o.Mode) {
+		errs = append(errs, fmt.Errorf("--database.tls.mode %q must be one of %v", o.Mode, db.TLSModes()))
+	}
+	if o.CAFile != "" {
+		if err := validateReadableFile("database.tls.ca-file", o.CAFile); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		errs = append(errs, fmt.Errorf("--database.tls.cert-file and --database.tls.private-key-file must be set together"))
+	} else if o.CertFile != "" {
+		if err := validateReadableFile("database.tls.cert-file", o.CertFile); err != nil {
+			errs = append(errs, err)
+		}
+		if err := validateReadableFile("database.tls.private-key-file", o.KeyFile); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// AddFlags adds flags to the given pflag.flagSet.
+func (o *DatabaseOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Driver, "database.driver", o.Driver, ""+
+		"Database driver, one of mysql, postgres, sqlite.")
+
+	fs.StringVar(&o.DSN, "database.dsn", o.DSN, ""+
+		"Driver specific data source name. If set, --database.host, --database.username, "+
+		"--database.password and --database.name are ignored.")
+
+	fs.StringVar(&o.Host, "database.host", o.Host, ""+
+		"Database service host address. If left blank for mysql or postgres, the following related database options will be ignored.")
+
+	fs.StringVar(&o.Username, "database.username", o.Username, ""+
+		"Username for access to database service.")
+
+	fs.StringVar(&o.Password, "database.password", o.Password, ""+
+		"Password for access to database, should be used pair with username.")
+
+	fs.StringVar(&o.Database, "database.name", o.Database, ""+
+		"Database name for the logicServer to use, or the database file for sqlite (\":memory:\" for an in-memory database).")
+
+	fs.StringVar(&o.TLS.Mode, "database.tls.mode", o.TLS.Mode, ""+
+		"TLS mode for mysql and postgres, one of disable, require, verify-ca, verify-full.")
+
+	fs.StringVar(&o.TLS.CAFile, "database.tls.ca-file", o.TLS.CAFile, ""+
+		"CA certificate used to verify the database server. System CAs are used if left blank.")
+
+	fs.StringVar(&o.TLS.CertFile, "database.tls.cert-file", o.TLS.CertFile, ""+
+		"Client certificate for databases requiring client authentication.")
+
+	fs.StringVar(&o.TLS.KeyFile, "database.tls.private-key-file", o.TLS.KeyFile, ""+
+		"Private key matching --database.tls.cert-file.")
+
+	fs.StringVar(&o.TLS.ServerName, "database.tls.server-name", o.TLS.ServerName, ""+
+		"Server name used to verify the database certificate in verify-full mode, defaults to the host.")
+
+	fs.IntVar(&o.MaxIdleConnections, "database.max-idle-connections", o.MaxIdleConnections, ""+
+		"Maximum idle connections allowed to connect to database.")
+
+	fs.IntVar(&o.MaxOpenConnections, "database.max-open-connections", o.MaxOpenConnections, ""+
+		"Maximum open connections allowed to connect to database.")
+
+	fs.DurationVar(&o.MaxConnectionLifeTime, "database.max-connection-life-time", o.MaxConnectionLifeTime, ""+
+		"Maximum connection life time allowed to connect to database.")
+
+	fs.IntVar(&o.LogLevel, "database.log-level", o.LogLevel, ""+
+		"Specify gorm log level, 1: silent, 2: error, 3: warn, 4: info.")
+
+	fs.DurationVar(&o.SlowThreshold, "database.slow-threshold", o.SlowThreshold, ""+
+		"Queries slower than this are logged as slow queries at warn level, 0 disables the detection.")
+
+	fs.BoolVar(&o.MigrateOnStartup, "database.migrate-on-startup", o.MigrateOnStartup, ""+
+		"Apply pending schema migrations before serving. Replicas starting at the same time take turns through an advisory lock.")
+}
+
+// NewClient new database client with options.
+func (o *DatabaseOptions) NewClient() (*gorm.DB, error) {
+	opts := db.Options{
+		Driver:   o.Driver,
+		DSN:      o.DSN,
+		Host:     o.Host,
+		UserName: o.Username,
+		Password: o.Password,
+		Database: o.Database,
+		TLS: db.TLSOptions{
+			Mode:       o.TLS.Mode,
+			CAFile:     o.TLS.CAFile,
+			CertFile:   o.TLS.CertFile,
+			KeyFile:    o.TLS.KeyFile,
+			ServerName: o.TLS.ServerName,
+		},
+		MaxIdleConnections:    o.MaxIdleConnections,
+		MaxOpenConnections:    o.MaxOpenConnections,
+		MaxConnectionLifeTime: o.MaxConnectionLifeTime,
+		MaxConnectionIdleTime: time.Minute * 24,
+		LogLevel:              o.LogLevel,
+		SlowThreshold:         o.SlowThreshold,
+	}
+	return db.NewClient(&opts)
+}
@@ -0,0 +1,63 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+	"github.com/spf13/pflag"
+)
+
+// GRPCOptions gRPC 服务配置，TLS 证书复用 secure.tls 配置
+type GRPCOptions struct {
+	BindAddress string `json:"bind-address" mapstructure:"bind-address"`
+	BindPort    int    `json:"bind-port" mapstructure:"bind-port"`
+	MaxMsgSize  int    `json:"max-msg-size" mapstructure:"max-msg-size"`
+}
+
+// NewGRPCOptions 创建默认的 gRPC 服务配置
+func NewGRPCOptions() *GRPCOptions {
+	return &GRPCOptions{
+		BindAddress: "0.0.0.0",
+		BindPort:    8081,
+		MaxMsgSize:  4 * 1024 * 1024,
+	}
+}
+
+// ApplyTo 将配置应用到 server.Config
+func (s *GRPCOptions) ApplyTo(c *server.Config) error {
+	c.GRPCServing = &server.GRPCServingInfo{
+		BindAddress: s.BindAddress,
+		BindPort:    s.BindPort,
+		MaxMsgSize:  s.MaxMsgSize,
+	}
+
+	return nil
+}
+
+// Validate 校验 gRPC 服务配置
+func (s *GRPCOptions) Validate() []error {
+	var errs []error
+
+	if s.BindPort < 0 || s.BindPort > 65535 {
+		errs = append(errs, fmt.Errorf("--grpc.bind-port %v must be between 0 and 65535, inclusive. 0 for turning off gRPC server", s.BindPort))
+	}
+	if err := validateBindAddress("grpc.bind-address", s.BindAddress); err != nil {
+		errs = append(errs, err)
+	}
+	if s.MaxMsgSize < 0 {
+		errs = append(errs, fmt.Errorf("--grpc.max-msg-size %v must not be negative", s.MaxMsgSize))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags to the given pflag.flagSet.
+func (s *GRPCOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.BindAddress, "grpc.bind-address", s.BindAddress, ""+
+		"The IP address on which to serve the --grpc.bind-port(set to 0.0.0.0 for all IPv4 interfaces and :: for all IPv6 interfaces).")
+
+	fs.IntVar(&s.BindPort, "grpc.bind-port", s.BindPort, ""+
+		"The port on which to serve gRPC, with the same TLS certificate as HTTPS if configured. Set to 0 to disable.")
+
+	fs.IntVar(&s.MaxMsgSize, "grpc.max-msg-size", s.MaxMsgSize, "gRPC max message size.")
+}
@@ -0,0 +1,56 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+	"github.com/spf13/pflag"
+)
+
+// InsecureServingOptions 不加密的 HTTP 服务配置
+type InsecureServingOptions struct {
+	BindAddress string `json:"bind-address" mapstructure:"bind-address"`
+	BindPort    int    `json:"bind-port" mapstructure:"bind-port"`
+}
+
+// NewInsecureServingOptions 创建默认的 HTTP 服务配置
+func NewInsecureServingOptions() *InsecureServingOptions {
+	return &InsecureServingOptions{
+		BindAddress: "127.0.0.1",
+		BindPort:    8080,
+	}
+}
+
+// ApplyTo 将配置应用到 server.Config
+func (s *InsecureServingOptions) ApplyTo(c *server.Config) error {
+	c.InsecureServing = &server.InsecureServingInfo{
+		BindAddress: s.BindAddress,
+		BindPort:    s.BindPort,
+	}
+
+	return nil
+}
+
+// Validate 校验 HTTP 服务配置
+func (s *InsecureServingOptions) Validate() []error {
+	var errs []error
+
+	if s.BindPort < 0 || s.BindPort > 65535 {
+		errs = append(errs, fmt.Errorf("--insecure.bind-port %v must be between 0 and 65535, inclusive. 0 for turning off insecure (HTTP) port", s.BindPort))
+	}
+	if err := validateBindAddress("insecure.bind-address", s.BindAddress); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// AddFlags adds flags to the given pflag.flagSet.
+func (s *InsecureServingOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.BindAddress, "insecure.bind-address", s.BindAddress, ""+
+		"The IP address on which to serve the --insecure.bind-port "+
+		"(set to 0.0.0.0 for all IPv4 interfaces and :: for all IPv6 interfaces).")
+
+	fs.IntVar(&s.BindPort, "insecure.bind-port", s.BindPort, ""+
+		"The port on which to serve unsecured, unauthenticated access. Set to 0 to disable.")
+}
@@ -0,0 +1,74 @@
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+	"github.com/spf13/pflag"
+)
+
+// minJWTKeyLength HS256 签名密钥的最小长度
+const minJWTKeyLength = 32
+
+// JWTOptions JWT 认证配置
+type JWTOptions struct {
+	Realm      string        `json:"realm" mapstructure:"realm"`
+	Key        string        `json:"key" mapstructure:"key" log:"redact"`
+	Timeout    time.Duration `json:"timeout" mapstructure:"timeout"`
+	MaxRefresh time.Duration `json:"max-refresh" mapstructure:"max-refresh"`
+}
+
+// NewJWTOptions 创建默认的 JWT 配置
+func NewJWTOptions() *JWTOptions {
+	defaults := server.NewNilConfig()
+
+	return &JWTOptions{
+		Realm:      defaults.JWT.Realm,
+		Key:        defaults.JWT.Key,
+		Timeout:    defaults.JWT.Timeout,
+		MaxRefresh: defaults.JWT.MaxRefresh,
+	}
+}
+
+// ApplyTo 将配置应用到 server.Config
+func (s *JWTOptions) ApplyTo(c *server.Config) error {
+	c.JWT = &server.JWTInfo{
+		Realm:      s.Realm,
+		Key:        s.Key,
+		Timeout:    s.Timeout,
+		MaxRefresh: s.MaxRefresh,
+	}
+
+	return nil
+}
+
+// Validate 校验 JWT 配置
+func (s *JWTOptions) Validate() []error {
+	var errs []error
+
+	if s.Realm == "" {
+		errs = append(errs, fmt.Errorf("--jwt.realm cannot be empty"))
+	}
+	if len(s.Key) < minJWTKeyLength {
+		errs = append(errs, fmt.Errorf("--jwt.key must be at least %d bytes long, got %d", minJWTKeyLength, len(s.Key)))
+	}
+	if s.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("--jwt.timeout %s must be greater than 0", s.Timeout))
+	}
+	if s.MaxRefresh < 0 {
+		errs = append(errs, fmt.Errorf("--jwt.max-refresh %s must not be negative", s.MaxRefresh))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags to the given pflag.flagSet.
+func (s *JWTOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.Realm, "jwt.realm", s.Realm, "Realm name to display to the user.")
+	fs.StringVar(&s.Key, "jwt.key", s.Key, "Private key used to sign jwt token.")
+	fs.DurationVar(&s.Timeout, "jwt.timeout", s.Timeout, "JWT token timeout.")
+
+	fs.DurationVar(&s.MaxRefresh, "jwt.max-refresh", s.MaxRefresh, ""+
+		"This field allows clients to refresh their token until MaxRefresh has passed.")
+}
@@ -0,0 +1,142 @@
+package options
+
+import (
+	"compress/gzip"
+	"fmt"
+	"path"
+
+	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+	"github.com/spf13/pflag"
+)
+
+// MiddlewareOptions 内置中间件配置，每个中间件对应 middleware 下的一个配置段
+type MiddlewareOptions struct {
+	middleware.Config `mapstructure:",squash"`
+}
+
+// NewMiddlewareOptions 创建默认的中间件配置
+func NewMiddlewareOptions() *MiddlewareOptions {
+	return &MiddlewareOptions{
+		Config: *middleware.NewConfig(),
+	}
+}
+
+// ApplyTo 将配置应用到 server.Config
+func (o *MiddlewareOptions) ApplyTo(c *server.Config) error {
+	cfg := o.Config
+	c.MiddlewareConfig = &cfg
+
+	return nil
+}
+
+// Validate 校验中间件配置
+func (o *MiddlewareOptions) Validate() []error {
+	var errs []error
+
+	if o.Timeout.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("--middleware.timeout.timeout %s must not be negative", o.Timeout.Timeout))
+	}
+	if o.Gzip.Level < gzip.HuffmanOnly || o.Gzip.Level > gzip.BestCompression {
+		errs = append(errs, fmt.Errorf("--middleware.gzip.level %d must be between %d and %d",
+			o.Gzip.Level, gzip.HuffmanOnly, gzip.BestCompression))
+	}
+	if o.Cors.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("--middleware.cors.max-age %s must not be negative", o.Cors.MaxAge))
+	}
+	if err := o.Cors.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("--middleware.cors.allow-origins: %w", err))
+	}
+
+	errs = append(errs, validateLogger(o.Logger)...)
+	errs = append(errs, validateRateLimit(o.RateLimit)...)
+
+	return errs
+}
+
+func validateLogger(cfg middleware.LoggerConfig) []error {
+	var errs []error
+
+	for i, p := range cfg.SkipPaths {
+		if _, err := path.Match(p, ""); err != nil {
+			errs = append(errs, fmt.Errorf("middleware.logger.skip-paths[%d] %q: %w", i, p, err))
+		}
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("--middleware.logger.sample-rate %v must be between 0 and 1", cfg.SampleRate))
+	}
+	if cfg.SlowThreshold < 0 {
+		errs = append(errs, fmt.Errorf("--middleware.logger.slow-threshold %s must not be negative", cfg.SlowThreshold))
+	}
+
+	return errs
+}
+
+func validateRateLimit(cfg middleware.RateLimitConfig) []error {
+	var errs []error
+
+	switch cfg.Backend {
+	case middleware.RateLimitBackendMemory:
+	case middleware.RateLimitBackendRedis:
+		if len(cfg.Redis.Addrs) == 0 {
+			errs = append(errs, fmt.Errorf("--middleware.ratelimit.redis.addrs must not be empty when backend is redis"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("--middleware.ratelimit.backend %q must be one of [memory, redis]", cfg.Backend))
+	}
+
+	for i, r := range cfg.Rules {
+		if _, err := path.Match(r.Path, ""); err != nil {
+			errs = append(errs, fmt.Errorf("middleware.ratelimit.rules[%d].path %q: %w", i, r.Path, err))
+		}
+		switch r.KeyBy {
+		case middleware.RateLimitKeyByIP, middleware.RateLimitKeyByUser, middleware.RateLimitKeyByRoute:
+		default:
+			errs = append(errs, fmt.Errorf("middleware.ratelimit.rules[%d].key-by %q must be one of [ip, user, route]", i, r.KeyBy))
+		}
+		if r.Rate <= 0 {
+			errs = append(errs, fmt.Errorf("middleware.ratelimit.rules[%d].rate must be greater than 0", i))
+		}
+		if r.Burst < 1 {
+			errs = append(errs, fmt.Errorf("middleware.ratelimit.rules[%d].burst must be at least 1", i))
+		}
+	}
+
+	return errs
+}
+
+// AddFlags adds flags to the given pflag.flagSet.
+func (o *MiddlewareOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&o.Cors.AllowOrigins, "middleware.cors.allow-origins", o.Cors.AllowOrigins, ""+
+		"List of origins a cross-domain request can be executed from, '*' allows all origins.")
+
+	fs.BoolVar(&o.Cors.AllowCredentials, "middleware.cors.allow-credentials", o.Cors.AllowCredentials, ""+
+		"Whether the request can include user credentials like cookies or HTTP authentication.")
+
+	fs.StringVar(&o.Secure.ContentSecurityPolicy, "middleware.secure.content-security-policy",
+		o.Secure.ContentSecurityPolicy, "Value of the Content-Security-Policy header, empty to disable.")
+
+	fs.DurationVar(&o.Timeout.Timeout, "middleware.timeout.timeout", o.Timeout.Timeout, ""+
+		"Deadline of a single request, 0 to disable.")
+
+	fs.Int64Var(&o.BodyLimit.MaxBytes, "middleware.body-limit.max-bytes", o.BodyLimit.MaxBytes, ""+
+		"Maximum size in bytes of a request body, 0 to disable.")
+
+	fs.IntVar(&o.Gzip.Level, "middleware.gzip.level", o.Gzip.Level, ""+
+		"Gzip compression level, -1 for default compression, 1 to 9 for best speed to best compression.")
+
+	fs.StringVar(&o.RateLimit.Backend, "middleware.ratelimit.backend", o.RateLimit.Backend, ""+
+		"Token bucket storage of the rate limiter, memory for a single replica or redis for multi-replica deployments.")
+
+	fs.StringSliceVar(&o.RateLimit.Redis.Addrs, "middleware.ratelimit.redis.addrs", o.RateLimit.Redis.Addrs, ""+
+		"Redis addresses used by the redis rate limit backend.")
+
+	fs.StringSliceVar(&o.Logger.SkipPaths, "middleware.logger.skip-paths", o.Logger.SkipPaths, ""+
+		"Routes or request paths excluded from the access log, glob patterns are supported.")
+
+	fs.Float64Var(&o.Logger.SampleRate, "middleware.logger.sample-rate", o.Logger.SampleRate, ""+
+		"Fraction of successful requests written to the access log, failed and slow requests are always logged.")
+
+	fs.DurationVar(&o.Logger.SlowThreshold, "middleware.logger.slow-threshold", o.Logger.SlowThreshold, ""+
+		"Requests taking longer than this are logged at warn level as slow, 0 to disable.")
+}
@@ -0,0 +1,19 @@
+package options
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareOptions_ValidateCors(t *testing.T) {
+	o := NewMiddlewareOptions()
+	if errs := o.Validate(); len(errs) != 0 {
+		t.Fatalf("Validate() default = %v", errs)
+	}
+
+	o.Cors.AllowOrigins = []string{"https://example.com", "example.com"}
+	errs := o.Validate()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "example.com") {
+		t.Errorf("Validate() = %v, want an allow-origins error", errs)
+	}
+}
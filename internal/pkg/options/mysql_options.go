@@ -1,83 +1,104 @@
 package options
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/ahang7/go-IAM/pkg/db"
 	"github.com/spf13/pflag"
-	"gorm.io/gorm"
 )
 
+// MySQLOptions 旧版的 mysql 配置段及 --mysql.* 参数，作为 DatabaseOptions 的别名保留，
+// 在配置文件中设置或通过命令行参数设置的配置项覆盖 database 中对应的配置项，mysql.database 对应 database.name。
+// IAM_MYSQL_* 环境变量不作为别名生效。
+//
+// Deprecated: 使用 DatabaseOptions
 type MySQLOptions struct {
-	Host                  string        `json:"host" yaml:"host" mapstructure:"host"`
-	Username              string        `json:"username" mapstructure:"username"`
-	Password              string        `json:"password" mapstructure:"password"`
-	Database              string        `json:"database" mapstructure:"database"`
-	MaxIdleConnections    int           `json:"max-idle-connections,omitempty" mapstructure:"max_idle_connections"`
-	MaxOpenConnections    int           `json:"max-open-connections,omitempty" mapstructure:"max_open_connections"`
-	MaxConnectionLifeTime time.Duration `json:"max-connection-life-time,omitempty" mapstructure:"max_connection_life_time"`
-	LogLevel              int           `json:"log-level,omitempty" mapstructure:"log_level"`
-}
+	Host                  string        `json:"host,omitempty" mapstructure:"host"`
+	Username              string        `json:"username,omitempty" mapstructure:"username"`
+	Password              string        `json:"password,omitempty" mapstructure:"password" log:"redact"`
+	Database              string        `json:"database,omitempty" mapstructure:"database"`
+	MaxIdleConnections    int           `json:"max-idle-connections,omitempty" mapstructure:"max-idle-connections"`
+	MaxOpenConnections    int           `json:"max-open-connections,omitempty" mapstructure:"max-open-connections"`
+	MaxConnectionLifeTime time.Duration `json:"max-connection-life-time,omitempty" mapstructure:"max-connection-life-time"`
+	LogLevel              int           `json:"log-level,omitempty" mapstructure:"log-level"`
+	SlowThreshold         time.Duration `json:"slow-threshold,omitempty" mapstructure:"slow-threshold"`
 
-// NewMySQLOptionsNil create a "" MySQLOptions
-func NewMySQLOptionsNil() *MySQLOptions {
-	return &MySQLOptions{
-		Host:                  "127.0.0.1:3306",
-		Username:              "",
-		Password:              "",
-		Database:              "",
-		MaxIdleConnections:    100,
-		MaxOpenConnections:    100,
-		MaxConnectionLifeTime: time.Duration(10) * time.Second,
-		LogLevel:              1, // Silent
-	}
+	// fs AddFlags 添加参数的 FlagSet，用于判断 --mysql.* 参数是否被设置
+	fs *pflag.FlagSet
 }
 
-func (o *MySQLOptions) Validate() []error {
-	var errs []error
-	return errs
+// NewMySQLOptions 创建空的 mysql 配置，零值表示没有设置，不覆盖 database 中的配置项
+func NewMySQLOptions() *MySQLOptions {
+	return &MySQLOptions{}
 }
 
-// AddFlags adds flags to the given pflag.flagSet.
-func (o *MySQLOptions) AddFlags(fs *pflag.FlagSet) {
-	fs.StringVar(&o.Host, "mysql.host", o.Host, ""+
-		"MySQL service host address. If left blank, the following related mysql options will be ignored.")
+// mysqlAlias 旧配置项与 database 配置项的对应关系
+type mysqlAlias struct {
+	name   string
+	target string
+	set    bool
+	apply  func()
+}
 
-	fs.StringVar(&o.Username, "mysql.username", o.Username, ""+
-		"Username for access to mysql service.")
+func (m *MySQLOptions) aliases(o *DatabaseOptions) []mysqlAlias {
+	return []mysqlAlias{
+		{"host", "host", m.Host != "", func() { o.Host = m.Host }},
+		{"username", "username", m.Username != "", func() { o.Username = m.Username }},
+		{"password", "password", m.Password != "", func() { o.Password = m.Password }},
+		{"database", "name", m.Database != "", func() { o.Database = m.Database }},
+		{"max-idle-connections", "max-idle-connections", m.MaxIdleConnections != 0, func() {
+			o.MaxIdleConnections = m.MaxIdleConnections
+		}},
+		{"max-open-connections", "max-open-connections", m.MaxOpenConnections != 0, func() {
+			o.MaxOpenConnections = m.MaxOpenConnections
+		}},
+		{"max-connection-life-time", "max-connection-life-time", m.MaxConnectionLifeTime != 0, func() {
+			o.MaxConnectionLifeTime = m.MaxConnectionLifeTime
+		}},
+		{"log-level", "log-level", m.LogLevel != 0, func() { o.LogLevel = m.LogLevel }},
+		{"slow-threshold", "slow-threshold", m.SlowThreshold != 0, func() { o.SlowThreshold = m.SlowThreshold }},
+	}
+}
 
-	fs.StringVar(&o.Password, "mysql.password", o.Password, ""+
-		"Password for access to mysql, should be used pair with password.")
+// ApplyTo 将显式设置的旧配置项写入 o，返回每个旧配置项的弃用提示。inConfig 判断配置键是否出现在配置文件中，
+// 配置项只有出现在配置文件中或者对应的命令行参数被设置时才生效，避免环境变量被当作旧配置项。
+// 旧配置项只能用于 MySQL，database.driver 不是 mysql 时返回错误
+func (m *MySQLOptions) ApplyTo(o *DatabaseOptions, inConfig func(key string) bool) ([]string, error) {
+	var warnings []string
+	for _, a := range m.aliases(o) {
+		key := "mysql." + a.name
+		if !a.set || !(inConfig(key) || m.fs != nil && m.fs.Changed(key)) {
+			continue
+		}
+		a.apply()
+		warnings = append(warnings, fmt.Sprintf("%s is deprecated, use database.%s instead", key, a.target))
+	}
+	if len(warnings) > 0 && o.Driver != db.DriverMySQL {
+		return warnings, fmt.Errorf("the deprecated mysql config requires --database.driver %s, got %q", db.DriverMySQL, o.Driver)
+	}
 
-	fs.StringVar(&o.Database, "mysql.database", o.Database, ""+
-		"Database name for the logicServer to use.")
+	return warnings, nil
+}
 
-	fs.IntVar(&o.MaxIdleConnections, "mysql.max-idle-connections", o.MaxOpenConnections, ""+
+// AddFlags 添加已弃用的 --mysql.* 参数，参数不在帮助信息中显示，使用时提示对应的 --database.* 参数
+func (m *MySQLOptions) AddFlags(fs *pflag.FlagSet) {
+	m.fs = fs
+	fs.StringVar(&m.Host, "mysql.host", m.Host, "MySQL service host address.")
+	fs.StringVar(&m.Username, "mysql.username", m.Username, "Username for access to mysql service.")
+	fs.StringVar(&m.Password, "mysql.password", m.Password, "Password for access to mysql.")
+	fs.StringVar(&m.Database, "mysql.database", m.Database, "Database name for the logicServer to use.")
+	fs.IntVar(&m.MaxIdleConnections, "mysql.max-idle-connections", m.MaxIdleConnections, ""+
 		"Maximum idle connections allowed to connect to mysql.")
-
-	fs.IntVar(&o.MaxOpenConnections, "mysql.max-open-connections", o.MaxOpenConnections, ""+
+	fs.IntVar(&m.MaxOpenConnections, "mysql.max-open-connections", m.MaxOpenConnections, ""+
 		"Maximum open connections allowed to connect to mysql.")
-
-	fs.DurationVar(&o.MaxConnectionLifeTime, "mysql.max-connection-life-time", o.MaxConnectionLifeTime, ""+
+	fs.DurationVar(&m.MaxConnectionLifeTime, "mysql.max-connection-life-time", m.MaxConnectionLifeTime, ""+
 		"Maximum connection life time allowed to connect to mysql.")
+	fs.IntVar(&m.LogLevel, "mysql.log-level", m.LogLevel, "Specify gorm log level, 1: silent, 2: error, 3: warn, 4: info.")
+	fs.DurationVar(&m.SlowThreshold, "mysql.slow-threshold", m.SlowThreshold, ""+
+		"Queries slower than this are logged as slow queries at warn level.")
 
-	fs.IntVar(&o.LogLevel, "mysql.log-mode", o.LogLevel, ""+
-		"Specify gorm log level.")
-}
-
-// NewClient new mysql client with options.
-func (o *MySQLOptions) NewClient() (*gorm.DB, error) {
-	opts := db.MySQLOptions{
-		Host:                  o.Host,
-		UserName:              o.Username,
-		Password:              o.Password,
-		Database:              o.Database,
-		MaxIdleConnections:    o.MaxIdleConnections,
-		MaxOpenConnections:    o.MaxOpenConnections,
-		MaxConnectionLifeTime: o.MaxConnectionLifeTime,
-		MaxConnectionIdleTime: time.Minute * 24,
-		LogLevel:              o.LogLevel,
-		//Logger:                nil,
+	for _, a := range m.aliases(&DatabaseOptions{}) {
+		_ = fs.MarkDeprecated("mysql."+a.name, fmt.Sprintf("use --database.%s instead", a.target))
 	}
-	return db.NewMySQLClient(&opts)
 }
@@ -0,0 +1,69 @@
+package options
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ahang7/go-IAM/pkg/db"
+	"github.com/spf13/pflag"
+)
+
+func TestMySQLOptions_ApplyTo(t *testing.T) {
+	o := NewDatabaseOptions()
+	m := NewMySQLOptions()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	m.AddFlags(fs)
+	if err := fs.Parse([]string{"--mysql.host=db:3306", "--mysql.database=iam", "--mysql.slow-threshold=1s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	notInConfig := func(string) bool { return false }
+	warnings, err := m.ApplyTo(o, notInConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Host != "db:3306" || o.Database != "iam" || o.SlowThreshold != time.Second {
+		t.Errorf("ApplyTo() database = %+v", o)
+	}
+	if o.MaxOpenConnections != 100 || o.LogLevel != 1 {
+		t.Errorf("ApplyTo() overrides unset options: %+v", o)
+	}
+	want := []string{
+		"mysql.host is deprecated, use database.host instead",
+		"mysql.database is deprecated, use database.name instead",
+		"mysql.slow-threshold is deprecated, use database.slow-threshold instead",
+	}
+	if len(warnings) != len(want) {
+		t.Fatalf("ApplyTo() warnings = %q, want %q", warnings, want)
+	}
+	for i := range want {
+		if warnings[i] != want[i] {
+			t.Errorf("ApplyTo() warnings[%d] = %q, want %q", i, warnings[i], want[i])
+		}
+	}
+	if f := fs.Lookup("mysql.host"); f == nil || f.Deprecated == "" {
+		t.Error("--mysql.host should be marked deprecated")
+	}
+
+	o = NewDatabaseOptions()
+	o.Driver = db.DriverPostgres
+	if _, err := m.ApplyTo(o, notInConfig); err == nil {
+		t.Error("ApplyTo() with the postgres driver should fail")
+	}
+	if warnings, err := NewMySQLOptions().ApplyTo(o, notInConfig); err != nil || len(warnings) != 0 {
+		t.Errorf("ApplyTo() without mysql options = %q, %v", warnings, err)
+	}
+
+	// 环境变量等非显式设置的值不作为旧配置项生效，配置文件中的配置项生效
+	o = NewDatabaseOptions()
+	m = NewMySQLOptions()
+	m.Host, m.Password = "db:3306", "secret"
+	if warnings, err := m.ApplyTo(o, notInConfig); err != nil || len(warnings) != 0 || o.Host == "db:3306" {
+		t.Errorf("ApplyTo() with values from env = %q, %v, host %q", warnings, err, o.Host)
+	}
+	o = NewDatabaseOptions()
+	inConfig := func(key string) bool { return key == "mysql.password" }
+	if warnings, err := m.ApplyTo(o, inConfig); err != nil || len(warnings) != 1 || o.Password != "secret" || o.Host == "db:3306" {
+		t.Errorf("ApplyTo() with mysql.password in config = %q, %v, %+v", warnings, err, o)
+	}
+}
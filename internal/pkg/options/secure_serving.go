@@ -0,0 +1,88 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+	"github.com/spf13/pflag"
+)
+
+// SecureServingOptions HTTPS 服务配置
+type SecureServingOptions struct {
+	BindAddress string `json:"bind-address" mapstructure:"bind-address"`
+	// BindPort 为0时不启动 HTTPS 服务
+	BindPort   int         `json:"bind-port" mapstructure:"bind-port"`
+	ServerCert CertKeyOpts `json:"tls" mapstructure:"tls"`
+}
+
+// CertKeyOpts 证书和密钥文件配置
+type CertKeyOpts struct {
+	CertFile string `json:"cert-file" mapstructure:"cert-file"`
+	KeyFile  string `json:"private-key-file" mapstructure:"private-key-file"`
+}
+
+// NewSecureServingOptions 创建默认的 HTTPS 服务配置
+func NewSecureServingOptions() *SecureServingOptions {
+	return &SecureServingOptions{
+		BindAddress: "0.0.0.0",
+		BindPort:    0,
+	}
+}
+
+// ApplyTo 将配置应用到 server.Config
+func (s *SecureServingOptions) ApplyTo(c *server.Config) error {
+	c.SecureServing = &server.SecureServingInfo{
+		BindAddress: s.BindAddress,
+		BindPort:    s.BindPort,
+		CertKey: server.CertKey{
+			CertFile: s.ServerCert.CertFile,
+			KeyFile:  s.ServerCert.KeyFile,
+		},
+	}
+
+	return nil
+}
+
+// Validate 校验 HTTPS 服务配置
+func (s *SecureServingOptions) Validate() []error {
+	var errs []error
+
+	if s.BindPort < 0 || s.BindPort > 65535 {
+		errs = append(errs, fmt.Errorf("--secure.bind-port %v must be between 0 and 65535, inclusive. 0 for turning off secure port", s.BindPort))
+	}
+	if err := validateBindAddress("secure.bind-address", s.BindAddress); err != nil {
+		errs = append(errs, err)
+	}
+
+	// 启用 HTTPS 时必须配置证书，证书同时用于 gRPC 服务，配置了证书时同样需要校验
+	cert, key := s.ServerCert.CertFile, s.ServerCert.KeyFile
+	if s.BindPort != 0 || cert != "" || key != "" {
+		if cert == "" {
+			errs = append(errs, fmt.Errorf("--secure.tls.cert-file is required when HTTPS or TLS is enabled"))
+		} else if err := validateReadableFile("secure.tls.cert-file", cert); err != nil {
+			errs = append(errs, err)
+		}
+		if key == "" {
+			errs = append(errs, fmt.Errorf("--secure.tls.private-key-file is required when HTTPS or TLS is enabled"))
+		} else if err := validateReadableFile("secure.tls.private-key-file", key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// AddFlags adds flags to the given pflag.flagSet.
+func (s *SecureServingOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.BindAddress, "secure.bind-address", s.BindAddress, ""+
+		"The IP address on which to listen for the --secure.bind-port port.")
+
+	fs.IntVar(&s.BindPort, "secure.bind-port", s.BindPort, ""+
+		"The port on which to serve HTTPS with authentication and authorization. Set to 0 to disable.")
+
+	fs.StringVar(&s.ServerCert.CertFile, "secure.tls.cert-file", s.ServerCert.CertFile, ""+
+		"File containing the default x509 Certificate for HTTPS.")
+
+	fs.StringVar(&s.ServerCert.KeyFile, "secure.tls.private-key-file", s.ServerCert.KeyFile, ""+
+		"File containing the default x509 private key matching --secure.tls.cert-file.")
+}
@@ -1,7 +1,91 @@
 package options
 
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	"github.com/ahang7/go-IAM/internal/pkg/server"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/pflag"
+)
+
+// ServerRunOptions 通用服务器运行配置
 type ServerRunOptions struct {
 	Mode        string   `json:"mode" mapstructure:"mode"`
 	Healthz     bool     `json:"healthz" mapstructure:"healthz"`
 	Middlewares []string `json:"middlewares" mapstructure:"middlewares"`
+	// EnableMetrics 是否暴露 Prometheus 指标
+	EnableMetrics bool `json:"enable-metrics" mapstructure:"enable-metrics"`
+	// MetricsAddress 单独暴露 /metrics 的监听地址，为空时在业务端口上暴露
+	MetricsAddress string `json:"metrics-address" mapstructure:"metrics-address"`
+}
+
+// NewServerRunOptions 创建默认的服务器运行配置
+func NewServerRunOptions() *ServerRunOptions {
+	return &ServerRunOptions{
+		Mode:          server.NewNilConfig().Mode,
+		Healthz:       true,
+		Middlewares:   []string{"logger", "recovery", "secure"},
+		EnableMetrics: true,
+	}
+}
+
+// ApplyTo 将配置应用到 server.Config
+func (s *ServerRunOptions) ApplyTo(c *server.Config) error {
+	c.Mode = s.Mode
+	c.Healthz = s.Healthz
+	c.Middlewares = s.Middlewares
+	c.EnableMetrics = s.EnableMetrics
+	c.MetricsAddress = s.MetricsAddress
+
+	return nil
+}
+
+// Validate 校验服务器运行配置
+func (s *ServerRunOptions) Validate() []error {
+	var errs []error
+
+	switch s.Mode {
+	case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+	default:
+		errs = append(errs, fmt.Errorf("--server.mode %q must be one of [%s, %s, %s]",
+			s.Mode, gin.DebugMode, gin.ReleaseMode, gin.TestMode))
+	}
+
+	names := middleware.Names()
+	for _, m := range s.Middlewares {
+		if !slices.Contains(names, m) {
+			errs = append(errs, fmt.Errorf("--server.middlewares: unknown middleware %q, must be one of [%s]",
+				m, strings.Join(names, ", ")))
+		}
+	}
+
+	if s.MetricsAddress != "" {
+		if _, _, err := net.SplitHostPort(s.MetricsAddress); err != nil {
+			errs = append(errs, fmt.Errorf("--server.metrics-address %q must be host:port: %w", s.MetricsAddress, err))
+		}
+	}
+
+	return errs
+}
+
+// AddFlags adds flags to the given pflag.flagSet.
+func (s *ServerRunOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.Mode, "server.mode", s.Mode, ""+
+		"Start the server in a specified server mode. Supported server mode: debug, test, release.")
+
+	fs.BoolVar(&s.Healthz, "server.healthz", s.Healthz, ""+
+		"Add self readiness check and install /healthz and /readyz routers.")
+
+	fs.StringSliceVar(&s.Middlewares, "server.middlewares", s.Middlewares, ""+
+		"List of middlewares installed on the server in order, comma separated.")
+
+	fs.BoolVar(&s.EnableMetrics, "server.enable-metrics", s.EnableMetrics, ""+
+		"Expose Prometheus metrics on /metrics.")
+
+	fs.StringVar(&s.MetricsAddress, "server.metrics-address", s.MetricsAddress, ""+
+		"Serve /metrics on a separate listener (host:port) instead of the API ports, e.g. 127.0.0.1:9090.")
 }
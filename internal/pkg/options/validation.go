@@ -0,0 +1,50 @@
+package options
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// validateHostPort 校验 host:port 格式的地址，端口必须在 1-65535 之间
+func validateHostPort(flag, addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("--%s %q must be in host:port format: %w", flag, addr, err)
+	}
+	if host == "" {
+		return fmt.Errorf("--%s %q: host cannot be empty", flag, addr)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("--%s %q: port must be between 1 and 65535, inclusive", flag, addr)
+	}
+
+	return nil
+}
+
+// validateBindAddress 校验监听的 IP 地址
+func validateBindAddress(flag, addr string) error {
+	if net.ParseIP(addr) == nil {
+		return fmt.Errorf("--%s %q is not a valid IP address", flag, addr)
+	}
+
+	return nil
+}
+
+// validateReadableFile 校验文件存在并且可读
+func validateReadableFile(flag, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("--%s %q: %w", flag, path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("--%s %q is a directory", flag, path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("--%s %q is not readable: %w", flag, path, err)
+	}
+
+	return f.Close()
+}
@@ -23,7 +23,9 @@ func (c CompletedConfig) NewServer() (*GenericServer, error) {
 		ShutdownTimeout: 5,
 	}
 
-	initGenericServer(s)
+	if err := initGenericServer(s); err != nil {
+		return nil, err
+	}
 
 	return s, nil
 }
@@ -5,20 +5,36 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ahang7/go-IAM/internal/pkg/middleware"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
 // Config 用于配置genericServer的配置.
 type Config struct {
 	SecureServing   *SecureServingInfo
 	InsecureServing *InsecureServingInfo
+	GRPCServing     *GRPCServingInfo
 	JWT             *JWTInfo
 	Mode            string
 	Middlewares     []string
+	// MiddlewareConfig 内置中间件配置，为 nil 时使用 middleware.Middlewares 中的默认实现
+	MiddlewareConfig *middleware.Config
 
-	Healthz         bool
+	// GRPCUnaryInterceptors 和 GRPCStreamInterceptors 按顺序安装到 gRPC 服务上
+	GRPCUnaryInterceptors  []grpc.UnaryServerInterceptor
+	GRPCStreamInterceptors []grpc.StreamServerInterceptor
+
+	// AdminMiddlewares 管理接口 /admin/* 的认证及鉴权中间件，为空时不安装管理接口
+	AdminMiddlewares []gin.HandlerFunc
+
+	Healthz bool
+	// ReadinessChecks 就绪检查，Healthz 为 true 时由 /readyz 依次执行，任意一个失败时返回 503
+	ReadinessChecks []ReadinessCheck
 	EnableProfiling bool
 	EnableMetrics   bool
+	// MetricsAddress 单独暴露 /metrics 的监听地址，为空时在业务端口上暴露
+	MetricsAddress string
 }
 
 // CertKey 结构体用于存储证书和密钥文件的路径。
@@ -1,6 +1,42 @@
 package server
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout 单次 /readyz 请求执行所有就绪检查的超时时间
+const readinessTimeout = 5 * time.Second
+
+// ReadinessCheck 就绪检查，例如检测数据库是否可用
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// readyz 依次执行就绪检查，全部通过时返回 200，否则返回 503 及失败的检查
+func (s *GenericServer) readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	failed := make(map[string]string)
+	for _, check := range s.ReadinessChecks {
+		if err := check.Check(ctx); err != nil {
+			log.L(c).Warnw("readiness check failed", "check", check.Name, "error", err)
+			failed[check.Name] = err.Error()
+		}
+	}
+	if len(failed) > 0 {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": failed})
+
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
 
 // 启动一个http服务器，提供api检查泵的健康状态
 func serverHealthCheck(healthAddress string, healthPath string) {
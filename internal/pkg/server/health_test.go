@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadyz(t *testing.T) {
+	std := log.Default()
+	log.ReplaceDefault(log.New(io.Discard, log.InfoLevel))
+	defer log.ReplaceDefault(std)
+
+	var dbErr error
+	cfg := NewNilConfig()
+	cfg.Healthz = true
+	cfg.ReadinessChecks = []ReadinessCheck{
+		{Name: "database", Check: func(ctx context.Context) error { return dbErr }},
+	}
+	gin.SetMode(gin.TestMode)
+	s := &GenericServer{Config: cfg}
+	e := gin.New()
+	s.installAPIs(e)
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		return w
+	}
+
+	if w := get(); w.Code != http.StatusOK {
+		t.Errorf("GET /readyz = %d %s, want 200", w.Code, w.Body.String())
+	}
+
+	dbErr = errors.New("connection refused")
+	w := get()
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), `"database":"connection refused"`) {
+		t.Errorf("GET /readyz = %d %s, want 503 with the failed check", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"time"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// defaultLogLevelTTL 修改日志级别时没有指定 ttl 的默认值
+	defaultLogLevelTTL = 10 * time.Minute
+	// maxLogLevelTTL 临时日志级别的最长有效期，需要永久修改时使用配置文件
+	maxLogLevelTTL = 24 * time.Hour
+)
+
+// setLogLevelRequest 临时修改日志级别的请求，Name 为空时修改全局级别
+type setLogLevelRequest struct {
+	Name  string `json:"name"`
+	Level string `json:"level" binding:"required"`
+	// TTL 有效期，例如 30m，到期后恢复为修改之前的级别
+	TTL string `json:"ttl"`
+}
+
+// installLogLevelAPIs 安装查看及临时修改日志级别的管理接口：
+//
+//	GET    /admin/loglevel        查看全局级别及按名称覆盖的级别
+//	PUT    /admin/loglevel        临时修改级别
+//	DELETE /admin/loglevel?name=  立即恢复临时修改的级别
+func installLogLevelAPIs(g *gin.RouterGroup) {
+	g.GET("/loglevel", func(c *gin.Context) {
+		httpcore.WriteResponse(c, nil, gin.H{"levels": log.Levels()})
+	})
+
+	g.PUT("/loglevel", func(c *gin.Context) {
+		var r setLogLevelRequest
+		if err := c.ShouldBindJSON(&r); err != nil {
+			httpcore.WriteResponse(c, errors.WithCode(code.ErrBind, "%s", err.Error()), nil)
+
+			return
+		}
+		level, err := zapcore.ParseLevel(r.Level)
+		if err != nil {
+			httpcore.WriteResponse(c, errors.WithCode(code.ErrValidation, "invalid level %q", r.Level), nil)
+
+			return
+		}
+		ttl := defaultLogLevelTTL
+		if r.TTL != "" {
+			if ttl, err = time.ParseDuration(r.TTL); err != nil || ttl <= 0 || ttl > maxLogLevelTTL {
+				httpcore.WriteResponse(c,
+					errors.WithCode(code.ErrValidation, "ttl %q must be a duration in (0, %s]", r.TTL, maxLogLevelTTL), nil)
+
+				return
+			}
+		}
+
+		log.SetTemporaryLevel(r.Name, level, ttl)
+		log.L(c).Warnw("log level changed", "name", r.Name, "level", level.String(), "ttl", ttl.String())
+		httpcore.WriteResponse(c, nil, gin.H{"levels": log.Levels()})
+	})
+
+	g.DELETE("/loglevel", func(c *gin.Context) {
+		name := c.Query("name")
+		if !log.RevertLevel(name) {
+			httpcore.WriteResponse(c, errors.WithCode(code.ErrValidation, "no temporary level for %q", name), nil)
+
+			return
+		}
+		log.L(c).Warnw("log level reverted", "name", name)
+		httpcore.WriteResponse(c, nil, gin.H{"levels": log.Levels()})
+	})
+}
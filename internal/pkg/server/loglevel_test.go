@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLogLevelAPIs(t *testing.T) {
+	std := log.Default()
+	log.ReplaceDefault(log.New(io.Discard, log.InfoLevel))
+	defer log.ReplaceDefault(std)
+
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	installLogLevelAPIs(e.Group("/admin"))
+
+	do := func(method, target, body string) (*httptest.ResponseRecorder, httpcore.ErrResponse) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		e.ServeHTTP(w, req)
+
+		var resp httpcore.ErrResponse
+		if w.Code != http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("%s %s: invalid response %q: %v", method, target, w.Body.String(), err)
+			}
+		}
+
+		return w, resp
+	}
+
+	errTests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		code   int
+	}{
+		{"missing level", http.MethodPut, "/admin/loglevel", `{"name":"store"}`, code.ErrBind},
+		{"invalid level", http.MethodPut, "/admin/loglevel", `{"level":"verbose"}`, code.ErrValidation},
+		{"invalid ttl", http.MethodPut, "/admin/loglevel", `{"level":"debug","ttl":"abc"}`, code.ErrValidation},
+		{"negative ttl", http.MethodPut, "/admin/loglevel", `{"level":"debug","ttl":"-1m"}`, code.ErrValidation},
+		{"ttl too long", http.MethodPut, "/admin/loglevel", `{"level":"debug","ttl":"48h"}`, code.ErrValidation},
+		{"no temporary level", http.MethodDelete, "/admin/loglevel?name=store", "", code.ErrValidation},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, resp := do(tt.method, tt.target, tt.body)
+			if w.Code != http.StatusBadRequest || resp.ErrorCode != tt.code {
+				t.Errorf("%s %s = %d %+v, want 400 with error code %d", tt.method, tt.target, w.Code, resp, tt.code)
+			}
+		})
+	}
+
+	levels := func() map[string]log.LevelSetting {
+		settings := make(map[string]log.LevelSetting)
+		for _, s := range log.Levels() {
+			settings[s.Name] = s
+		}
+
+		return settings
+	}
+
+	if w, _ := do(http.MethodPut, "/admin/loglevel", `{"name":"store","level":"debug","ttl":"30m"}`); w.Code != http.StatusOK {
+		t.Fatalf("PUT = %d %s", w.Code, w.Body.String())
+	}
+	if s, ok := levels()["store"]; !ok || s.Level != log.DebugLevel || s.ExpiresAt == nil {
+		t.Errorf("levels after PUT = %+v, want store at debug with an expiry", log.Levels())
+	}
+
+	w, _ := do(http.MethodGet, "/admin/loglevel", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"name":"store"`) {
+		t.Errorf("GET = %d %s, want the store level", w.Code, w.Body.String())
+	}
+
+	if w, _ := do(http.MethodDelete, "/admin/loglevel?name=store", ""); w.Code != http.StatusOK {
+		t.Fatalf("DELETE = %d %s", w.Code, w.Body.String())
+	}
+	if s, ok := levels()["store"]; ok && s.ExpiresAt != nil {
+		t.Errorf("levels after DELETE = %+v, want no temporary level for store", log.Levels())
+	}
+}
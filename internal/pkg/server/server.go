@@ -4,16 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ahang7/go-IAM/internal/pkg/metrics"
 	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/ahang7/go-IAM/pkg/version"
 	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	ginprometheus "github.com/zsais/go-gin-prometheus"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
 )
 
 type GenericServer struct {
@@ -25,91 +32,259 @@ type GenericServer struct {
 
 	insecureServer *http.Server
 	secureServer   *http.Server
+	metricsServer  *http.Server
+	grpcServer     *grpc.Server
+	grpcHealth     *health.Server
+
+	// handler 当前处理请求的 gin.Engine，中间件热加载时会被整体替换
+	handler atomic.Pointer[gin.Engine]
+	// routes 通过 InstallRoutes 安装的路由，重建 gin.Engine 时重新安装
+	routes []func(*gin.Engine) error
+	// rateLimiter 当前 gin.Engine 的 ratelimit 中间件使用的限流器，未启用时为空
+	rateLimiter *middleware.RateLimiter
+	reloadMu    sync.Mutex
+	prometheus  *ginprometheus.Prometheus
 }
 
 func (s *GenericServer) Setup() {
 	gin.DebugPrintRouteFunc = func(httpMethod, absolutePath, handlerName string, nuHandlers int) {
-		// TODO:  更换日志
-		log.Printf("%-6s %-25s --> %s (%d handlers)", httpMethod, absolutePath, handlerName, nuHandlers)
+		log.Infow("route registered", "method", httpMethod, "path", absolutePath, "handler", handlerName, "handlers", nuHandlers)
+	}
+	// gin.Context 取不到的值从 c.Request.Context() 中获取，使 log.L(c) 能取到访问日志中间件保存的日志记录器
+	s.Engine.ContextWithFallback = true
+}
+
+// InstallMiddlewares 安装中间件，配置了未注册的中间件时返回错误
+func (s *GenericServer) InstallMiddlewares() error {
+	limiter, err := installMiddlewares(s.Engine, s.Middlewares, s.MiddlewareConfig, nil)
+	if err != nil {
+		return err
 	}
+	s.rateLimiter = limiter
+
+	return nil
 }
 
-// InstallMiddlewares 安装中间件
-func (s *GenericServer) InstallMiddlewares() {
-	s.Use(middleware.RequestID())
-	s.Use(middleware.Context())
+// installMiddlewares 按顺序安装中间件，返回 ratelimit 中间件使用的限流器，未启用时返回 nil。
+// current 的存储配置未变化时继续使用 current，否则创建新的限流器，出错时关闭新创建的限流器
+func installMiddlewares(e *gin.Engine, names []string, cfg *middleware.Config,
+	current *middleware.RateLimiter) (*middleware.RateLimiter, error) {
+	e.Use(middleware.Tracing())
+	e.Use(middleware.RequestID())
+	e.Use(middleware.Context())
 
 	// install middlewares
-	for _, m := range s.Middlewares {
-		mw, ok := middleware.Middlewares[m]
+	var limiter *middleware.RateLimiter
+	mws, err := middleware.Build(cfg, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range names {
+		mw, ok := mws[m]
+		if !ok && m == middleware.RateLimitName {
+			if limiter == nil {
+				limiter = rateLimiter(cfg, current)
+			}
+			mw, ok = limiter.Handler(rateLimitConfig(cfg).Rules), true
+		}
 		if !ok {
-			// log
-			continue
+			if limiter != nil && limiter != current {
+				_ = limiter.Close()
+			}
+
+			return nil, fmt.Errorf("unknown middleware %q, must be one of [%s]", m, strings.Join(middleware.Names(), ", "))
 		}
-		s.Use(mw)
+		log.Infof("install middleware: %s", m)
+		e.Use(mw)
+	}
+
+	return limiter, nil
+}
+
+func rateLimitConfig(cfg *middleware.Config) middleware.RateLimitConfig {
+	if cfg == nil {
+		return middleware.NewRateLimitConfig()
+	}
+
+	return cfg.RateLimit
+}
+
+// rateLimiter 存储配置未变化时复用 current，内存存储中的令牌桶状态在热加载后保留
+func rateLimiter(cfg *middleware.Config, current *middleware.RateLimiter) *middleware.RateLimiter {
+	if current != nil && current.Reusable(rateLimitConfig(cfg)) {
+		return current
 	}
+
+	return middleware.NewRateLimiter(rateLimitConfig(cfg))
 }
 
 func (s *GenericServer) InstallAPIs() {
+	s.installAPIs(s.Engine)
+}
+
+func (s *GenericServer) installAPIs(e *gin.Engine) {
 	if s.Healthz {
-		s.GET("/healthz", func(c *gin.Context) {
+		e.GET("/healthz", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{
 				"status": "ok",
 			})
 		})
+		e.GET("/readyz", s.readyz)
 	}
-	// 启用Prometheus指标监控
+	// 启用Prometheus指标监控，指标只注册一次，重建 gin.Engine 时复用。
+	// 配置了 MetricsAddress 时 /metrics 由单独的监听器暴露，业务端口上只统计请求
 	if s.EnableMetrics {
-		prometheus := ginprometheus.NewPrometheus("gin")
-		prometheus.Use(s.Engine)
+		if s.prometheus == nil {
+			s.prometheus = ginprometheus.NewPrometheus("gin")
+			metrics.Register(version.NewCollector("iam"))
+		}
+		if s.MetricsAddress == "" {
+			s.prometheus.Use(e)
+		} else {
+			e.Use(s.prometheus.HandlerFunc())
+		}
+		e.Use(metrics.CountErrors())
 	}
 
 	// install pprof handler
 	if s.EnableProfiling {
-		pprof.Register(s.Engine)
+		pprof.Register(e)
 	}
-	// TODO: /version 路由 输出 version
+
+	e.GET("/version", func(c *gin.Context) {
+		c.JSON(http.StatusOK, version.Get())
+	})
+
+	if len(s.AdminMiddlewares) > 0 {
+		installLogLevelAPIs(e.Group("/admin", s.AdminMiddlewares...))
+	}
+}
+
+// InstallRoutes 安装业务路由，fn 会在中间件热加载重建 gin.Engine 时再次调用，需要在 Run 之前调用
+func (s *GenericServer) InstallRoutes(fn func(*gin.Engine) error) error {
+	if err := fn(s.Engine); err != nil {
+		return err
+	}
+	s.routes = append(s.routes, fn)
+
+	return nil
+}
+
+// ReloadMiddlewares 使用新的中间件列表及配置重建 gin.Engine，并原子替换正在处理请求的 gin.Engine，
+// 已经在处理中的请求不受影响
+func (s *GenericServer) ReloadMiddlewares(names []string, cfg *middleware.Config) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	e := gin.New()
+	e.ContextWithFallback = true
+	limiter, err := installMiddlewares(e, names, cfg, s.rateLimiter)
+	if err != nil {
+		return err
+	}
+	s.installAPIs(e)
+	for _, fn := range s.routes {
+		if err := fn(e); err != nil {
+			if limiter != nil && limiter != s.rateLimiter {
+				_ = limiter.Close()
+			}
+
+			return err
+		}
+	}
+
+	s.handler.Store(e)
+	// 仍在使用旧限流器的请求在其关闭后限流失败，按存储出错处理放行
+	if s.rateLimiter != nil && s.rateLimiter != limiter {
+		if err := s.rateLimiter.Close(); err != nil {
+			log.Warnf("failed to close the previous rate limiter: %s", err.Error())
+		}
+	}
+	s.rateLimiter = limiter
+	log.Infof("middlewares reloaded: %s", strings.Join(names, ","))
+
+	return nil
 }
 
-func initGenericServer(s *GenericServer) {
+// ServeHTTP 使用当前的 gin.Engine 处理请求
+func (s *GenericServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.Load().ServeHTTP(w, r)
+}
+
+func initGenericServer(s *GenericServer) error {
 	s.Setup()
-	s.InstallMiddlewares()
+	if err := s.InstallMiddlewares(); err != nil {
+		return err
+	}
 	s.InstallAPIs()
+	s.handler.Store(s.Engine)
+
+	return s.initGRPCServer()
 }
 
 func (s *GenericServer) Run() error {
-	s.insecureServer = &http.Server{
-		Addr:    s.InsecureServing.Address(),
-		Handler: s,
-	}
+	var eg errgroup.Group
+
+	if s.InsecureServing != nil && s.InsecureServing.BindPort != 0 {
+		s.insecureServer = &http.Server{
+			Addr:    s.InsecureServing.Address(),
+			Handler: s,
+		}
 
-	s.secureServer = &http.Server{
-		Addr:    s.SecureServing.Address(),
-		Handler: s,
+		eg.Go(func() error {
+			log.Infof("Start to listening the incoming requests on http address: %s", s.InsecureServing.Address())
+			if err := s.insecureServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err.Error())
+				return err
+			}
+			log.Infof("Server on %s stopped", s.InsecureServing.Address())
+			return nil
+		})
 	}
 
-	var eg errgroup.Group
-	eg.Go(func() error {
-		if err := s.insecureServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Fatal(err.Error())
-			return err
+	if s.SecureServing != nil && s.SecureServing.BindPort != 0 {
+		s.secureServer = &http.Server{
+			Addr:    s.SecureServing.Address(),
+			Handler: s,
 		}
-		return nil
-	})
 
-	eg.Go(func() error {
-		cert, key := s.SecureServing.CertKey.CertFile, s.SecureServing.CertKey.KeyFile
+		eg.Go(func() error {
+			cert, key := s.SecureServing.CertKey.CertFile, s.SecureServing.CertKey.KeyFile
 
-		if err := s.secureServer.ListenAndServeTLS(cert, key); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Fatal(err.Error())
-			return err
+			log.Infof("Start to listening the incoming requests on https address: %s", s.SecureServing.Address())
+			if err := s.secureServer.ListenAndServeTLS(cert, key); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err.Error())
+				return err
+			}
+			log.Infof("Server on %s stopped", s.SecureServing.Address())
+			return nil
+		})
+	}
+
+	if s.grpcServer != nil {
+		eg.Go(s.runGRPC)
+	}
+
+	if s.EnableMetrics && s.MetricsAddress != "" {
+		s.metricsServer = &http.Server{
+			Addr:    s.MetricsAddress,
+			Handler: metricsHandler(),
 		}
-		return nil
-	})
+
+		eg.Go(func() error {
+			log.Infof("Start to serving metrics on http address: %s", s.MetricsAddress)
+			if err := s.metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err.Error())
+				return err
+			}
+			log.Infof("Metrics server on %s stopped", s.MetricsAddress)
+			return nil
+		})
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	if s.Healthz {
+	if s.Healthz && s.insecureServer != nil {
 		if err := s.ping(ctx); err != nil {
 			return err
 		}
@@ -121,17 +296,42 @@ func (s *GenericServer) Run() error {
 	return nil
 }
 
-// Shutdown 优雅关闭服务
+// Shutdown 优雅关闭服务，返回前写入缓冲中的日志
 func (s *GenericServer) Shutdown() error {
+	// 标准输出不支持 Sync 时会返回错误，忽略即可
+	defer func() { _ = log.Flush() }()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if err := s.insecureServer.Shutdown(ctx); err != nil {
-		return err
+	if s.insecureServer != nil {
+		if err := s.insecureServer.Shutdown(ctx); err != nil {
+			return err
+		}
 	}
-	if err := s.secureServer.Shutdown(ctx); err != nil {
-		return err
+	if s.secureServer != nil {
+		if err := s.secureServer.Shutdown(ctx); err != nil {
+			return err
+		}
 	}
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	if s.grpcServer != nil {
+		s.shutdownGRPC(ctx)
+	}
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	if s.rateLimiter != nil {
+		if err := s.rateLimiter.Close(); err != nil {
+			return err
+		}
+		s.rateLimiter = nil
+	}
+
 	return nil
 }
 
@@ -152,7 +352,7 @@ func (s *GenericServer) ping(ctx context.Context) error {
 
 		resp, err := http.DefaultClient.Do(req)
 		if err == nil && resp.StatusCode == http.StatusOK {
-			log.Println("The router has been deployed successfully.")
+			log.Info("The router has been deployed successfully.")
 
 			resp.Body.Close()
 
@@ -160,7 +360,7 @@ func (s *GenericServer) ping(ctx context.Context) error {
 		}
 
 		// Sleep for a second to continue the next ping.
-		log.Println("Waiting for the router, retry in 1 second.")
+		log.Info("Waiting for the router, retry in 1 second.")
 		time.Sleep(1 * time.Second)
 
 		select {
@@ -171,3 +371,11 @@ func (s *GenericServer) ping(ctx context.Context) error {
 	}
 	// return fmt.Errorf("the router has no response, or it might took too long to start up")
 }
+
+// metricsHandler 单独的指标监听器的处理器，只暴露 /metrics
+func metricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return mux
+}
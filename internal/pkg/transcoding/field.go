@@ -0,0 +1,106 @@
+package transcoding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// lookupField 根据 a.b.c 形式的字段路径查找字段，返回字段描述及其所在的消息，中间的消息字段会被创建
+func lookupField(m protoreflect.Message, path string) (protoreflect.FieldDescriptor, protoreflect.Message, error) {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = m.Descriptor().Fields().ByJSONName(name)
+		}
+		if fd == nil {
+			return nil, nil, fmt.Errorf("unknown field %q", name)
+		}
+		if i == len(names)-1 {
+			return fd, m, nil
+		}
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return nil, nil, fmt.Errorf("field %q is not a message", name)
+		}
+		m = m.Mutable(fd).Message()
+	}
+
+	return nil, nil, fmt.Errorf("empty field path")
+}
+
+// setField 将字符串形式的值设置到 path 指定的标量字段，repeated 字段可以有多个值
+func setField(m protoreflect.Message, path string, values []string) error {
+	fd, parent, err := lookupField(m, path)
+	if err != nil {
+		return err
+	}
+	if fd.IsMap() || (fd.Message() != nil && !fd.IsList()) {
+		return fmt.Errorf("field %q is not a scalar", path)
+	}
+
+	if fd.IsList() {
+		list := parent.Mutable(fd).List()
+		for _, s := range values {
+			v, err := parseScalar(fd, s)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+
+		return nil
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+	v, err := parseScalar(fd, values[len(values)-1])
+	if err != nil {
+		return err
+	}
+	parent.Set(fd, v)
+
+	return nil
+}
+
+// parseScalar 解析标量字段的值
+func parseScalar(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(s)), nil
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(s, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(s, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		v, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), err
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", fd.Kind())
+	}
+}
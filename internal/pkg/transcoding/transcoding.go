@@ -0,0 +1,278 @@
+// Package transcoding 根据 proto 中的 google.api.http 注解，将 gRPC 服务转换为 gin REST 路由。
+// 请求的路径参数、查询参数和 body 按照 HttpRule 的规则填充到 gRPC 请求消息中，
+// 响应以 protojson 编码后通过 pkg/core/http 的返回包写回，错误同样使用 ErrResponse 返回
+package transcoding
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+var (
+	marshaler = protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+	}
+	unmarshaler = protojson.UnmarshalOptions{
+		DiscardUnknown: true,
+	}
+)
+
+// Route 由 HttpRule 生成的一条 REST 路由
+type Route struct {
+	Method string
+	// Path gin 格式的路径，例如 /v1/users/:name
+	Path string
+	// FullMethod gRPC 完整方法名，例如 /iam.v1.UserService/GetUser
+	FullMethod string
+}
+
+// Register 将 desc 描述的 gRPC 服务注册到 gin 路由，srv 为服务实现。
+// 没有 google.api.http 注解的方法不会被注册，interceptor 可以为空
+func Register(r gin.IRoutes, desc *grpc.ServiceDesc, srv any, interceptor grpc.UnaryServerInterceptor) ([]Route, error) {
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(desc.ServiceName))
+	if err != nil {
+		return nil, fmt.Errorf("find service %s: %w", desc.ServiceName, err)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", desc.ServiceName)
+	}
+
+	var routes []Route
+	for i := range desc.Methods {
+		m := desc.Methods[i]
+		md := sd.Methods().ByName(protoreflect.Name(m.MethodName))
+		if md == nil {
+			return nil, fmt.Errorf("method %s not found in service %s", m.MethodName, desc.ServiceName)
+		}
+		rule, ok := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		reqType, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+		if err != nil {
+			return nil, fmt.Errorf("find message %s: %w", md.Input().FullName(), err)
+		}
+
+		fullMethod := fmt.Sprintf("/%s/%s", desc.ServiceName, m.MethodName)
+		for _, b := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+			method, tmpl, err := pattern(b)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", fullMethod, err)
+			}
+			path, params, err := parseTemplate(tmpl)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", fullMethod, err)
+			}
+
+			h := &handler{
+				srv:         srv,
+				method:      m,
+				interceptor: interceptor,
+				reqType:     reqType,
+				body:        b.GetBody(),
+				params:      params,
+			}
+			r.Handle(method, path, h.serve)
+			routes = append(routes, Route{Method: method, Path: path, FullMethod: fullMethod})
+		}
+	}
+
+	return routes, nil
+}
+
+// pattern 返回 HttpRule 的 HTTP 方法和路径模板
+func pattern(rule *annotations.HttpRule) (string, string, error) {
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet, p.Get, nil
+	case *annotations.HttpRule_Put:
+		return http.MethodPut, p.Put, nil
+	case *annotations.HttpRule_Post:
+		return http.MethodPost, p.Post, nil
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete, p.Delete, nil
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch, p.Patch, nil
+	case *annotations.HttpRule_Custom:
+		return strings.ToUpper(p.Custom.GetKind()), p.Custom.GetPath(), nil
+	default:
+		return "", "", fmt.Errorf("http rule has no pattern")
+	}
+}
+
+// param 路径参数，name 为 gin 中的参数名，field 为请求消息中的字段路径
+type param struct {
+	name  string
+	field string
+}
+
+// parseTemplate 将 /v1/users/{name} 形式的模板转换为 gin 路径 /v1/users/:name。
+// 支持 {field}、{field=*} 和作为最后一段的 {field=**}
+func parseTemplate(tmpl string) (string, []param, error) {
+	if !strings.HasPrefix(tmpl, "/") {
+		return "", nil, fmt.Errorf("path template %q must start with /", tmpl)
+	}
+
+	segments := strings.Split(tmpl[1:], "/")
+	params := make([]param, 0)
+	for i, seg := range segments {
+		if !strings.HasPrefix(seg, "{") {
+			continue
+		}
+		if !strings.HasSuffix(seg, "}") {
+			return "", nil, fmt.Errorf("unsupported path segment %q in %q", seg, tmpl)
+		}
+
+		field, sub, _ := strings.Cut(seg[1:len(seg)-1], "=")
+		name := strings.ReplaceAll(field, ".", "_")
+		switch sub {
+		case "", "*":
+			segments[i] = ":" + name
+		case "**":
+			if i != len(segments)-1 {
+				return "", nil, fmt.Errorf("** must be the last segment in %q", tmpl)
+			}
+			segments[i] = "*" + name
+		default:
+			return "", nil, fmt.Errorf("unsupported path segment %q in %q", seg, tmpl)
+		}
+		params = append(params, param{name: name, field: field})
+	}
+
+	return "/" + strings.Join(segments, "/"), params, nil
+}
+
+type handler struct {
+	srv         any
+	method      grpc.MethodDesc
+	interceptor grpc.UnaryServerInterceptor
+	reqType     protoreflect.MessageType
+	body        string
+	params      []param
+}
+
+func (h *handler) serve(c *gin.Context) {
+	ctx := c.Request.Context()
+	if username := c.GetString(middleware.UserNameKey); username != "" {
+		ctx = middleware.WithUsername(ctx, username)
+	}
+
+	resp, err := h.method.Handler(h.srv, ctx, func(v any) error {
+		msg, ok := v.(proto.Message)
+		if !ok {
+			return errors.WithCode(code.ErrBind, "request %T is not a proto message", v)
+		}
+
+		return h.decode(c, msg)
+	}, h.interceptor)
+	if err != nil {
+		httpcore.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	data, err := marshaler.Marshal(resp.(proto.Message))
+	if err != nil {
+		httpcore.WriteResponse(c, errors.WrapC(err, code.ErrEncodingJSON, "marshal response"), nil)
+
+		return
+	}
+	httpcore.WriteResponse(c, nil, rawJSON(data))
+}
+
+// decode 按照 body、路径参数、查询参数的顺序填充请求消息
+func (h *handler) decode(c *gin.Context, msg proto.Message) error {
+	m := msg.ProtoReflect()
+
+	bound := make(map[string]bool)
+	if h.body != "" {
+		if err := h.decodeBody(c, m); err != nil {
+			return err
+		}
+		bound[h.body] = true
+	}
+
+	for _, p := range h.params {
+		if err := setField(m, p.field, []string{strings.TrimPrefix(c.Param(p.name), "/")}); err != nil {
+			return errors.WithCode(code.ErrBind, "path parameter %s: %s", p.name, err.Error())
+		}
+		bound[p.field] = true
+	}
+
+	// body 为 * 时请求消息的所有字段都来自 body，不再解析查询参数
+	if h.body == "*" {
+		return nil
+	}
+	for key, values := range c.Request.URL.Query() {
+		if bound[key] || isBoundPrefix(bound, key) {
+			continue
+		}
+		if err := setField(m, key, values); err != nil {
+			return errors.WithCode(code.ErrBind, "query parameter %s: %s", key, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (h *handler) decodeBody(c *gin.Context, m protoreflect.Message) error {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return errors.WrapC(err, code.ErrBind, "read request body")
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	target := m
+	if h.body != "*" {
+		fd, parent, err := lookupField(m, h.body)
+		if err != nil {
+			return errors.WithCode(code.ErrBind, "body field %s: %s", h.body, err.Error())
+		}
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return errors.WithCode(code.ErrBind, "body field %s must be a message", h.body)
+		}
+		target = parent.Mutable(fd).Message()
+	}
+
+	if err := unmarshaler.Unmarshal(data, target.Interface()); err != nil {
+		return errors.WrapC(err, code.ErrBind, "decode request body")
+	}
+
+	return nil
+}
+
+// isBoundPrefix 判断 key 是否为已经绑定字段的子字段，例如 body 为 user 时忽略 user.name
+func isBoundPrefix(bound map[string]bool, key string) bool {
+	for field := range bound {
+		if strings.HasPrefix(key, field+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rawJSON 已经编码好的 JSON，gin 会原样写回
+type rawJSON []byte
+
+func (r rawJSON) MarshalJSON() ([]byte, error) {
+	return r, nil
+}
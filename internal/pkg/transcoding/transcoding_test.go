@@ -0,0 +1,119 @@
+package transcoding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	"github.com/ahang7/go-IAM/internal/pkg/middleware"
+	"github.com/ahang7/go-IAM/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type fakeUsers struct {
+	v1.UnimplementedUserServiceServer
+}
+
+func (fakeUsers) GetUser(ctx context.Context, req *v1.GetUserRequest) (*v1.User, error) {
+	if req.GetName() == "missing" {
+		return nil, errors.WithCode(code.ErrUserNotFound, "user not found")
+	}
+
+	return &v1.User{Name: req.GetName(), Nickname: middleware.UsernameFrom(ctx)}, nil
+}
+
+func (fakeUsers) ListUsers(_ context.Context, req *v1.ListUsersRequest) (*v1.ListUsersResponse, error) {
+	return &v1.ListUsersResponse{TotalCount: req.GetOffset() + req.GetLimit()}, nil
+}
+
+func (fakeUsers) UpdateUser(_ context.Context, req *v1.UpdateUserRequest) (*v1.User, error) {
+	return &v1.User{Name: req.GetName(), Email: req.GetUser().GetEmail()}, nil
+}
+
+func (fakeUsers) DeleteUser(context.Context, *v1.DeleteUserRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func newEngine(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	g := gin.New()
+	auth := func(c *gin.Context) { c.Set(middleware.UserNameKey, "admin") }
+
+	routes, err := Register(g.Group("", auth), &v1.UserService_ServiceDesc, fakeUsers{}, nil)
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if len(routes) != 5 {
+		t.Fatalf("expected 5 routes, got %d: %v", len(routes), routes)
+	}
+
+	return g
+}
+
+func do(g *gin.Engine, method, target, body string) (int, map[string]any) {
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	out := make(map[string]any)
+	_ = json.Unmarshal(w.Body.Bytes(), &out)
+
+	return w.Code, out
+}
+
+func TestRegister(t *testing.T) {
+	g := newEngine(t)
+
+	status, out := do(g, http.MethodGet, "/v1/users/colin", "")
+	if status != http.StatusOK || out["name"] != "colin" || out["nickname"] != "admin" {
+		t.Errorf("get user: %d %v", status, out)
+	}
+
+	status, out = do(g, http.MethodGet, "/v1/users?offset=2&limit=3", "")
+	if status != http.StatusOK || out["total_count"] != "5" {
+		t.Errorf("list users: %d %v", status, out)
+	}
+
+	status, out = do(g, http.MethodPut, "/v1/users/colin", `{"email":"colin@example.com"}`)
+	if status != http.StatusOK || out["name"] != "colin" || out["email"] != "colin@example.com" {
+		t.Errorf("update user: %d %v", status, out)
+	}
+
+	status, out = do(g, http.MethodGet, "/v1/users/missing", "")
+	if status != http.StatusNotFound || out["msg"] != "User not found" || out["code"] != float64(http.StatusNotFound) ||
+		out["errorCode"] != float64(code.ErrUserNotFound) {
+		t.Errorf("get missing user: %d %v", status, out)
+	}
+
+	status, out = do(g, http.MethodGet, "/v1/users?limit=abc", "")
+	if status != http.StatusBadRequest {
+		t.Errorf("bad query: %d %v", status, out)
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		path string
+	}{
+		{"/v1/users", "/v1/users"},
+		{"/v1/users/{name}", "/v1/users/:name"},
+		{"/v1/users/{user.name=*}/secrets", "/v1/users/:user_name/secrets"},
+		{"/v1/files/{path=**}", "/v1/files/*path"},
+	}
+	for _, tt := range tests {
+		path, _, err := parseTemplate(tt.tmpl)
+		if err != nil || path != tt.path {
+			t.Errorf("parseTemplate(%q) = %q, %v; want %q", tt.tmpl, path, err, tt.path)
+		}
+	}
+
+	if _, _, err := parseTemplate("/v1/{path=**}/x"); err == nil {
+		t.Error("expected error for ** in the middle")
+	}
+}
@@ -1,8 +1,10 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -10,6 +12,8 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/ahang7/go-IAM/pkg/tracing"
+	"github.com/ahang7/go-IAM/pkg/version"
 )
 
 type App struct {
@@ -17,8 +21,11 @@ type App struct {
 	appname     string
 	description string
 
-	runFunc RunFunc
-	flags   FlagsOptions
+	runFunc  RunFunc
+	flags    FlagsOptions
+	reloader *Reloader
+	// logOptions options 没有实现 LoggableOptions 时使用的日志配置
+	logOptions *log.Options
 
 	noConfig  bool
 	noVersion bool
@@ -41,6 +48,20 @@ func WithFlags(fi FlagsOptions) Option {
 	}
 }
 
+// WithReloader 启用配置热加载，配置文件变化或收到 SIGHUP 时重新加载配置
+func WithReloader(r *Reloader) Option {
+	return func(app *App) {
+		app.reloader = r
+	}
+}
+
+// WithCommands 添加子命令
+func WithCommands(cmds ...*Command) Option {
+	return func(app *App) {
+		app.commands = append(app.commands, cmds...)
+	}
+}
+
 func WithDescription(desc string) Option {
 	return func(app *App) {
 		app.description = desc
@@ -53,6 +74,7 @@ func WithNoConfig() Option {
 	}
 }
 
+// WithVersion 设置是否禁用 --version 命令行参数
 func WithVersion(noVersion bool) Option {
 	return func(app *App) {
 		app.noVersion = noVersion
@@ -121,70 +143,181 @@ func (a *App) buildCommand() {
 	}
 	cmd.SetOut(os.Stdout)
 	cmd.SetErr(os.Stderr)
+	// 错误由 Run 统一打印
+	cmd.SilenceErrors = true
 	cmd.Flags().SetNormalizeFunc(normalizeFunc)
 
-	if len(a.commands) > 0 {
-		for _, c := range a.commands {
-			cmd.AddCommand(c.buildCommand())
-		}
-		cmd.SetHelpCommand(helpCommand(a.use))
+	for _, c := range a.commands {
+		cmd.AddCommand(c.buildCommand())
 	}
+	cmd.AddCommand(completionCommand(a.appname), manCommand(a.appname))
+	cmd.SetHelpCommand(helpCommand(a.appname))
+	cmd.CompletionOptions.DisableDefaultCmd = true
 	if a.runFunc != nil {
 		cmd.RunE = a.run
+	} else if !a.noVersion {
+		// 根命令需要可以执行，--version 才会经过 PersistentPreRunE 处理
+		cmd.RunE = func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		}
 	}
 
 	// 构建命令行参数解析
 	var appFlags FlagSet
 	if a.flags != nil {
 		appFlags = a.flags.Flags()
-		fs := cmd.Flags()
-		for _, f := range appFlags.flags {
-			fs.AddFlagSet(f)
-		}
 	}
+	if _, ok := a.flags.(LoggableOptions); !ok {
+		a.logOptions = log.NewOptions()
+		a.logOptions.AddFlags(appFlags.Flags(logFlagSection))
+	}
+	cmd.PersistentPreRunE = a.persistentPreRun
 	// config 命令行
 	if !a.noConfig {
 		addConfigFile(a.prefix, a.appname, appFlags.Flags("config"))
 	}
-	// version 命令行
+	// 全局 命令行
 	if !a.noVersion {
-		addConfigFile(a.prefix, a.appname, appFlags.Flags("version"))
+		version.AddFlags(appFlags.Flags("global"))
 	}
-	// 全局 命令行
-	appFlags.Flags("global").BoolP("help", "h", false, fmt.Sprintf("help for %s", color.GreenString(a.appname)))
-	cmd.Flags().AddFlagSet(appFlags.Flags("global"))
+	addHelpCommandFlag(a.appname, appFlags.Flags("global"))
+
+	fs := cmd.Flags()
+	for _, name := range appFlags.Names() {
+		// 默认日志配置的命令行参数对所有子命令生效
+		if name == logFlagSection && a.logOptions != nil {
+			cmd.PersistentFlags().AddFlagSet(appFlags.Flags(name))
+
+			continue
+		}
+		appFlags.Flags(name).VisitAll(func(flag *pflag.Flag) {
+			// --version 在所有子命令的 PersistentPreRunE 中处理，同样需要对所有子命令生效
+			if flag.Name == versionFlagName && !a.noVersion {
+				cmd.PersistentFlags().AddFlag(flag)
+
+				return
+			}
+			fs.AddFlag(flag)
+		})
+	}
+	if !a.noConfig && a.flags != nil {
+		cmd.AddCommand(a.configCommand(fs))
+	}
+	setUsageAndHelpFunc(cmd, appFlags)
 
 	a.cmd = cmd
 }
 
+// Run 执行命令，出错时打印完整的错误信息并以非 0 状态码退出
 func (a *App) Run() {
 	if err := a.cmd.Execute(); err != nil {
-		log.Fatal("app run failed")
+		fmt.Fprintf(os.Stderr, "%v %v\n", color.RedString("Error:"), err)
+		os.Exit(1)
 	}
 }
 
 func (a *App) run(cmd *cobra.Command, args []string) error {
-	printWorkingDir()
-	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
-		log.Debugf("flag %s: %v", flag.Name, flag.Value)
-	})
-	// todo: 输出 --version
+	// 命令行参数解析成功后出现的错误不需要打印帮助信息
+	cmd.SilenceUsage = true
 
 	if !a.noConfig {
+		if configErr != nil {
+			return configErr
+		}
 		if err := viper.BindPFlags(cmd.Flags()); err != nil {
 			return err
 		}
-		if err := viper.Unmarshal(a.flags); err != nil {
+		if err := unmarshalConfig(a.flags); err != nil {
 			return err
 		}
 	}
 
+	if a.flags != nil {
+		if err := validateOptions(a.flags, !a.noConfig); err != nil {
+			return err
+		}
+	}
+
+	if o, ok := a.flags.(LoggableOptions); ok {
+		if err := log.Init(o.LogOptions()); err != nil {
+			return err
+		}
+	}
+	// 第三方库通过 slog、标准库 log、grpclog 等输出的日志同样使用日志配置
+	log.InstallDefaults()
+	if o, ok := a.flags.(TraceableOptions); ok {
+		if err := initTracing(o.TracingOptions(), a.appname); err != nil {
+			return err
+		}
+		defer shutdownTracing()
+	}
+	printWorkingDir()
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if log.IsSensitiveKey(flag.Name) && flag.Value.String() != "" {
+			log.Debugf("flag %s: %s", flag.Name, log.Redacted)
+
+			return
+		}
+		log.Debugf("flag %s: %v", flag.Name, flag.Value)
+	})
+
+	if a.reloader != nil && !a.noConfig {
+		a.reloader.Start(a.flags)
+	}
+
 	if a.runFunc != nil {
 		return a.runFunc(a.appname)
 	}
 	return nil
 }
 
+// persistentPreRun 在根命令及所有子命令执行前处理 --version，并初始化默认日志配置。
+// 配置文件的读取错误在命令执行时才返回，因此指定 --version 时不受配置文件影响
+func (a *App) persistentPreRun(*cobra.Command, []string) error {
+	if !a.noVersion {
+		version.PrintAndExitIfRequested()
+	}
+	if a.logOptions != nil {
+		return initLog(a.logOptions)
+	}
+
+	return nil
+}
+
+// logFlagSection 日志配置命令行参数所在的分组
+const logFlagSection = "log"
+
+// versionFlagName version.AddFlags 注册的命令行参数名称
+const versionFlagName = "version"
+
+// initLog 校验日志配置并替换默认日志记录器
+func initLog(opts *log.Options) error {
+	if errs := opts.Validate(); len(errs) != 0 {
+		return &ValidationError{Errs: errs}
+	}
+
+	return log.Init(opts)
+}
+
+// initTracing 初始化链路追踪，没有配置服务名称时使用程序名称
+func initTracing(opts *tracing.Options, appname string) error {
+	if opts.ServiceName == "" {
+		opts.ServiceName = appname
+	}
+
+	return tracing.Init(opts)
+}
+
+// shutdownTracing 导出剩余的 span，最多等待 5 秒
+func shutdownTracing() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := tracing.Shutdown(ctx); err != nil {
+		log.Errorf("failed to shutdown tracing: %s", err.Error())
+	}
+}
+
 func printWorkingDir() {
 	wd, _ := os.Getwd()
 	log.Infof("%v working dir: %s", color.GreenString("===>"), wd)
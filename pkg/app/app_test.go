@@ -0,0 +1,100 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type testCommandOptions struct {
+	Name  string
+	Count int
+}
+
+func (o *testCommandOptions) Flags() (fs FlagSet) {
+	fs.Flags("user").StringVar(&o.Name, "name", o.Name, "user name")
+	fs.Flags("output").IntVar(&o.Count, "count", o.Count, "number of results")
+
+	return
+}
+
+func (o *testCommandOptions) Validate() []error {
+	return nil
+}
+
+var _ FlagsOptions = (*testCommandOptions)(nil)
+
+func TestCommands(t *testing.T) {
+	opts := &testCommandOptions{}
+	var got []string
+	create := NewCommand("create NAME", "create a user",
+		WithCommandFlags(opts),
+		WithCommandRunFunc(func(args []string) error {
+			got = args
+
+			return nil
+		}),
+	)
+	a := NewApp("test", "testapp", WithNoConfig(), WithVersion(true), WithCommands(NewCommand("user", "manage users")))
+	a.cmd.Commands()[len(a.cmd.Commands())-1].AddCommand(create.buildCommand())
+
+	var out bytes.Buffer
+	a.cmd.SetOut(&out)
+	a.cmd.SetArgs([]string{"user", "create", "--name", "bob", "--count=2", "x"})
+	if err := a.cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Name != "bob" || opts.Count != 2 || len(got) != 1 || got[0] != "x" {
+		t.Errorf("unexpected result %+v %v", opts, got)
+	}
+
+	out.Reset()
+	a.cmd.SetArgs([]string{"user", "create", "--help"})
+	if err := a.cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	help := out.String()
+	user, output := strings.Index(help, "User flags:"), strings.Index(help, "Output flags:")
+	if user == -1 || output == -1 || user > output || !strings.Contains(help, "Global flags:") {
+		t.Errorf("flags are not grouped by section:\n%s", help)
+	}
+}
+
+func TestFlagSetOrder(t *testing.T) {
+	var fs FlagSet
+	for _, name := range []string{"server", "mysql", "global", "server"} {
+		fs.Flags(name)
+	}
+	if got := strings.Join(fs.Names(), ","); got != "server,mysql,global" {
+		t.Errorf("unexpected order %s", got)
+	}
+}
+
+func TestVersionFlag_Subcommand(t *testing.T) {
+	var ran bool
+	migrate := NewCommand("migrate", "migrate the database", WithCommandRunFunc(func([]string) error {
+		ran = true
+
+		return nil
+	}))
+	a := NewApp("test", "testapp", WithNoConfig(), WithCommands(migrate))
+
+	var out bytes.Buffer
+	a.cmd.SetOut(&out)
+	// --version=false 不会退出进程，可以验证子命令能够解析 --version
+	a.cmd.SetArgs([]string{"migrate", "--version=false"})
+	if err := a.cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !ran {
+		t.Error("migrate did not run")
+	}
+
+	a.cmd.SetArgs([]string{"migrate", "--help"})
+	if err := a.cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if help := out.String(); !strings.Contains(help, "--version") {
+		t.Errorf("migrate help does not list --version:\n%s", help)
+	}
+}
@@ -1,18 +1,22 @@
 package app
 
 import (
-	"log"
-	"os"
-
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/ahang7/go-IAM/pkg/log"
 )
 
+// Command 子命令，可以有自己的命令行参数和子命令，通过 WithCommands 添加到 App
 type Command struct {
 	usage string
 	short string
 	long  string
 
 	flags FlagsOptions
+	// config 是否读取 App 的配置文件
+	config bool
 
 	subCommand []*Command
 
@@ -23,6 +27,58 @@ type Command struct {
 
 type RunCommandFunc func(args []string) error
 
+// CommandOption 子命令的配置选项
+type CommandOption func(*Command)
+
+// WithCommandFlags 设置子命令的命令行参数，执行前会依次调用 ApplyFlags、Complete 和 Validate
+func WithCommandFlags(flags FlagsOptions) CommandOption {
+	return func(c *Command) {
+		c.flags = flags
+	}
+}
+
+// WithCommandConfig 子命令与 App 读取同一个配置文件（--config），优先级与 App 相同：命令行参数、环境变量、配置文件、默认值。
+// 子命令的配置结构体只需包含用到的配置段，不检查配置文件中的未知配置键；配置实现 LoggableOptions 时按其初始化日志
+func WithCommandConfig() CommandOption {
+	return func(c *Command) {
+		c.config = true
+	}
+}
+
+// WithCommandLong 设置子命令的详细说明
+func WithCommandLong(long string) CommandOption {
+	return func(c *Command) {
+		c.long = long
+	}
+}
+
+// WithCommandArgs 设置子命令的位置参数校验
+func WithCommandArgs(args cobra.PositionalArgs) CommandOption {
+	return func(c *Command) {
+		c.args = args
+	}
+}
+
+// WithCommandRunFunc 设置子命令的执行函数，没有执行函数的子命令只用于组织下级子命令
+func WithCommandRunFunc(run RunCommandFunc) CommandOption {
+	return func(c *Command) {
+		c.runFunc = run
+	}
+}
+
+// NewCommand 创建子命令，usage 为 cobra 的 Use，例如 "create NAME"
+func NewCommand(usage, short string, opts ...CommandOption) *Command {
+	c := &Command{
+		usage: usage,
+		short: short,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
 func (c *Command) AddCommand(cmd *Command) {
 	c.subCommand = append(c.subCommand, cmd)
 }
@@ -38,7 +94,7 @@ func (c *Command) buildCommand() *cobra.Command {
 		Long:  c.long,
 		Args:  c.args,
 	}
-	cmd.SetOut(os.Stdout)
+	cmd.Flags().SetNormalizeFunc(normalizeFunc)
 
 	// 添加子命令
 	if len(c.subCommand) > 0 {
@@ -47,27 +103,51 @@ func (c *Command) buildCommand() *cobra.Command {
 		}
 	}
 	if c.runFunc != nil {
-		cmd.Run = c.run
+		cmd.RunE = c.run
 	}
 
 	// 添加命令行
+	var cmdFlags FlagSet
 	if c.flags != nil {
-		for _, f := range c.flags.Flags().flags {
-			cmd.Flags().AddFlagSet(f)
-		}
+		cmdFlags = c.flags.Flags()
+	}
+	if c.config {
+		cmdFlags.Flags("config").AddFlag(pflag.Lookup(configFlagName))
 	}
-
 	// 添加Help命令
-	addHelpCommandFlag(c.usage, cmd.Flags())
-	// 添加Version命令
+	addHelpCommandFlag(c.usage, cmdFlags.Flags("global"))
+	for _, name := range cmdFlags.Names() {
+		cmd.Flags().AddFlagSet(cmdFlags.Flags(name))
+	}
+	setUsageAndHelpFunc(cmd, cmdFlags)
 
 	return cmd
 }
 
-func (c *Command) run(cmd *cobra.Command, args []string) {
-	if c.runFunc != nil {
-		if err := c.runFunc(args); err != nil {
-			log.Fatal("run command function failed")
+func (c *Command) run(cmd *cobra.Command, args []string) error {
+	// 命令行参数解析成功后出现的错误不需要打印帮助信息
+	cmd.SilenceUsage = true
+	if c.flags != nil {
+		if c.config {
+			if configErr != nil {
+				return configErr
+			}
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				return err
+			}
+			if err := unmarshalConfig(c.flags); err != nil {
+				return err
+			}
+		}
+		if err := validateOptions(c.flags, false); err != nil {
+			return err
+		}
+		if o, ok := c.flags.(LoggableOptions); ok {
+			if err := log.Init(o.LogOptions()); err != nil {
+				return err
+			}
 		}
 	}
+
+	return c.runFunc(args)
 }
@@ -1,11 +1,16 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/ahang7/go-IAM/pkg/secret"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -19,6 +24,10 @@ const (
 var configFlagFile string
 var configIn string
 
+// configErr 读取配置文件失败的错误，由需要配置文件的命令返回，
+// 不需要配置文件的命令(例如 config defaults)不受影响
+var configErr error
+
 func init() {
 	pflag.StringVarP(&configFlagFile, configFlagName, "c", configFlagFile, "set the configuration file, the default configuration file type is yaml")
 }
@@ -41,17 +50,58 @@ func addConfigFile(prefixFlag string, configName string, fs *pflag.FlagSet) {
 				defaultIn := getRootDir()
 				viper.AddConfigPath(defaultIn + "/config")
 			}
-			viper.SetConfigFile(configName)
+			viper.SetConfigName(configName)
 
 		}
 		viper.SetConfigType(configFileType)
 		if err := viper.ReadInConfig(); err != nil {
-			log.Fatalf("viper read config failed, err: %v", err)
-			os.Exit(1)
+			configErr = fmt.Errorf("read config file: %w", err)
 		}
 	})
 }
 
+// unmarshalConfig 将 viper 中的配置解析到 opts，字符串配置值中的 ${scheme:ref} 密钥引用会在解析时替换为密钥，
+// 解析后记录敏感配置项的值，打印配置及日志时隐藏
+func unmarshalConfig(opts any) error {
+	err := viper.Unmarshal(opts, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		expandSecretHook(),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)))
+	if err != nil {
+		return err
+	}
+	trackSecrets(reflect.ValueOf(opts), "")
+
+	return nil
+}
+
+func expandSecretHook() mapstructure.DecodeHookFuncKind {
+	return func(from reflect.Kind, _ reflect.Kind, data any) (any, error) {
+		if from != reflect.String {
+			return data, nil
+		}
+
+		return secret.Expand(context.Background(), data.(string))
+	}
+}
+
+// trackSecrets 记录敏感配置项(log.IsSensitiveKey 或 `log:"redact"`)的值，包括由密钥引用解析出的值
+// 以及环境变量、命令行参数覆盖后的值，其余配置项即使使用了密钥引用也不会被隐藏
+func trackSecrets(v reflect.Value, prefix string) {
+	for _, f := range configFields(v, prefix) {
+		fv := indirect(f.value)
+		if isSection(fv) {
+			trackSecrets(fv, f.key)
+
+			continue
+		}
+		if f.sensitive() && fv.Kind() == reflect.String {
+			secret.Track(fv.String())
+		}
+	}
+}
+
 func getRootDir() string {
 	pwd, err := os.Getwd()
 	if err != nil {
@@ -65,6 +115,10 @@ func getRootDir() string {
 		}
 
 		parent := filepath.Dir(dir)
+		// 到达根目录仍未找到 go.mod 时使用当前目录
+		if parent == dir {
+			return pwd
+		}
 		return infer(parent)
 	}
 
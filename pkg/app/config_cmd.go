@@ -0,0 +1,405 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/ahang7/go-IAM/pkg/secret"
+)
+
+// 配置项的来源，优先级从高到低
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceFile    = "file"
+	sourceDefault = "default"
+)
+
+// durationPattern time.ParseDuration 接受的时长格式
+const durationPattern = `^[-+]?(0|([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+)$`
+
+// configCommand 返回 config 命令，包含 view、defaults 和 schema 子命令，
+// 均根据配置结构体的 mapstructure tag 生成，配置键与命令行参数名一致
+func (a *App) configCommand(flags *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration of " + a.appname,
+	}
+
+	view := &cobra.Command{
+		Use:   "view",
+		Short: "Print the effective configuration with the source of each key",
+		Long: `Print the effective configuration after merging command line flags, environment variables,
+the configuration file and defaults, in that order of precedence. Every key is annotated with
+the source its value comes from. Keys such as passwords and tokens and fields tagged
+log:"redact" are hidden, including values resolved from ${scheme:ref} references.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if configErr != nil {
+				return configErr
+			}
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				return err
+			}
+			if err := unmarshalConfig(a.flags); err != nil {
+				return err
+			}
+
+			return writeYAML(cmd.OutOrStdout(), configNode(reflect.ValueOf(a.flags), "", func(key string) string {
+				return a.configSource(cmd.Flags(), key)
+			}, secret.Redact))
+		},
+	}
+	view.Flags().AddFlagSet(flags)
+
+	defaults := &cobra.Command{
+		Use:   "defaults",
+		Short: "Print a configuration file template filled with the default values",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return writeYAML(cmd.OutOrStdout(), configNode(reflect.ValueOf(a.flags), "", func(key string) string {
+				if f := flags.Lookup(key); f != nil {
+					return f.Usage
+				}
+
+				return ""
+			}, nil))
+		},
+	}
+
+	schema := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the configuration file",
+		Long: `Print the JSON Schema (draft 2020-12) of the configuration file, which can be used by
+editors to validate and complete the configuration file.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			s := configSchema(reflect.ValueOf(a.flags), "", flags)
+			s.Schema = "https://json-schema.org/draft/2020-12/schema"
+			s.Title = a.appname + " configuration"
+
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			enc.SetEscapeHTML(false)
+
+			return enc.Encode(s)
+		},
+	}
+
+	cmd.AddCommand(view, defaults, schema)
+
+	return cmd
+}
+
+// configSource 返回配置键的值来源，与 viper 的优先级一致：命令行参数、环境变量、配置文件、默认值
+func (a *App) configSource(fs *pflag.FlagSet, key string) string {
+	if f := fs.Lookup(key); f != nil && f.Changed {
+		return sourceFlag
+	}
+	if _, ok := os.LookupEnv(envName(a.prefix, key)); ok {
+		return sourceEnv
+	}
+	if viper.InConfig(key) {
+		return sourceFile
+	}
+
+	return sourceDefault
+}
+
+// envName 返回配置键对应的环境变量名，与 addConfigFile 中 viper 的设置一致
+func envName(prefix, key string) string {
+	name := strings.NewReplacer(".", "_", "-", "_").Replace(strings.ToUpper(key))
+	if prefix == "" {
+		return name
+	}
+
+	return strings.ReplaceAll(strings.ToUpper(prefix), "-", "_") + "_" + name
+}
+
+// configField 配置结构体中的一个配置项
+type configField struct {
+	key   string
+	name  string
+	value reflect.Value
+	// redact 字段的 log 标签为 redact
+	redact bool
+}
+
+// sensitive 判断配置项是否为密码、token 等敏感信息，输出时整体隐藏
+func (f configField) sensitive() bool {
+	return f.redact || log.IsSensitiveKey(f.key)
+}
+
+// configFields 按照 mapstructure tag 返回结构体的配置项，squash 的字段被展开
+func configFields(v reflect.Value, prefix string) []configField {
+	v = indirect(v)
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []configField
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "squash") {
+			fields = append(fields, configFields(v.Field(i), prefix)...)
+
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		name = strings.ToLower(name)
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		fields = append(fields, configField{key: key, name: name, value: v.Field(i), redact: f.Tag.Get("log") == "redact"})
+	}
+
+	return fields
+}
+
+// indirect 解引用指针，nil 指针返回元素类型的零值
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			if v.Kind() == reflect.Interface {
+				return v
+			}
+
+			return reflect.Zero(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// isSection 判断值是否为包含配置项的配置段
+func isSection(v reflect.Value) bool {
+	return v.Kind() == reflect.Struct && v.Type() != reflect.TypeOf(time.Time{})
+}
+
+// configNode 生成配置的 YAML 节点，comment 返回配置项的行尾注释，redact 不为空时用于隐藏字符串中的密钥，
+// 同时隐藏敏感配置项的全部字符串
+func configNode(v reflect.Value, prefix string, comment func(key string) string, redact func(string) string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range configFields(v, prefix) {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: f.name}
+		var value *yaml.Node
+		if fv := indirect(f.value); isSection(fv) {
+			value = configNode(fv, f.key, comment, redact)
+		} else {
+			value = valueNode(f.value, fieldRedact(f, redact))
+			// 多行的值把注释写在配置键后面
+			if value.Kind == yaml.ScalarNode || value.Style == yaml.FlowStyle {
+				value.LineComment = comment(f.key)
+			} else {
+				key.LineComment = comment(f.key)
+			}
+		}
+		node.Content = append(node.Content, key, value)
+	}
+
+	return node
+}
+
+// valueNode 生成配置值的 YAML 节点，结构体按照 mapstructure tag 编码，时长编码为 1h30m 的形式
+func valueNode(v reflect.Value, redact func(string) string) *yaml.Node {
+	v = indirect(v)
+
+	switch {
+	case !v.IsValid() || (v.Kind() == reflect.Interface && v.IsNil()):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: time.Duration(v.Int()).String()}
+	case isSection(v):
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range configFields(v, "") {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.name}, valueNode(f.value, fieldRedact(f, redact)))
+		}
+
+		return node
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		// 元素都是标量时使用 [a, b] 的形式
+		node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for i := 0; i < v.Len(); i++ {
+			item := valueNode(v.Index(i), redact)
+			if item.Kind != yaml.ScalarNode {
+				node.Style = 0
+			}
+			node.Content = append(node.Content, item)
+		}
+
+		return node
+	case v.Kind() == reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range keys {
+			node.Content = append(node.Content, valueNode(k, nil), valueNode(v.MapIndex(k), redact))
+		}
+
+		return node
+	case v.Kind() == reflect.String && redact != nil:
+		v = reflect.ValueOf(redact(v.String()))
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(v.Interface()); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(v.Interface())}
+	}
+
+	return node
+}
+
+// fieldRedact 需要隐藏密钥时，敏感配置项的非空字符串都替换为 log.Redacted
+func fieldRedact(f configField, redact func(string) string) func(string) string {
+	if redact == nil || !f.sensitive() {
+		return redact
+	}
+
+	return func(s string) string {
+		if s == "" {
+			return s
+		}
+
+		return log.Redacted
+	}
+}
+
+func writeYAML(w io.Writer, node *yaml.Node) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// jsonSchema JSON Schema 中用到的关键字
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 any                    `json:"type,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Default              any                    `json:"default,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+}
+
+// configSchema 生成配置段的 JSON Schema，配置项的描述取自同名命令行参数的说明，默认值取自 v。
+// 与 unknownConfigKeys 一致，配置段不允许出现未知的配置键
+func configSchema(v reflect.Value, prefix string, flags *pflag.FlagSet) *jsonSchema {
+	s := &jsonSchema{
+		Type:                 "object",
+		Properties:           make(map[string]*jsonSchema),
+		AdditionalProperties: false,
+	}
+	for _, f := range configFields(v, prefix) {
+		var p *jsonSchema
+		if fv := indirect(f.value); isSection(fv) {
+			p = configSchema(fv, f.key, flags)
+		} else {
+			p = valueSchema(f.value.Type())
+			p.Default = defaultValue(f.value)
+		}
+		if fl := flags.Lookup(f.key); fl != nil {
+			p.Description = fl.Usage
+		}
+		s.Properties[f.name] = p
+	}
+
+	return s
+}
+
+// valueSchema 根据配置值的类型生成 JSON Schema，
+// 与 unmarshalConfig 的解析规则一致：时长可以是 1h30m 形式的字符串，字符串数组可以是逗号分隔的字符串
+func valueSchema(t reflect.Type) *jsonSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == reflect.TypeOf(time.Duration(0)):
+		return &jsonSchema{Type: []string{"string", "integer"}, Pattern: durationPattern}
+	case t == reflect.TypeOf(time.Time{}):
+		return &jsonSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		items := valueSchema(t.Elem())
+		if t.Elem().Kind() == reflect.String {
+			return &jsonSchema{Type: []string{"array", "string"}, Items: items}
+		}
+
+		return &jsonSchema{Type: "array", Items: items}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: valueSchema(t.Elem())}
+	case reflect.Struct:
+		s := configSchema(reflect.Zero(t), "", pflag.NewFlagSet("", pflag.ContinueOnError))
+		for _, p := range s.Properties {
+			p.Default = nil
+		}
+
+		return s
+	default:
+		return &jsonSchema{}
+	}
+}
+
+// defaultValue 返回 JSON Schema 中的默认值，零值不输出
+func defaultValue(v reflect.Value) any {
+	v = indirect(v)
+	if !v.IsValid() || v.IsZero() {
+		return nil
+	}
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+	if v.Kind() == reflect.Slice && v.Len() == 0 {
+		return nil
+	}
+
+	var out any
+	if err := valueNode(v, nil).Decode(&out); err != nil {
+		return nil
+	}
+
+	return out
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+type testConfigCmdOptions struct {
+	testOptions `mapstructure:",squash"`
+	Timeout     time.Duration `json:"timeout" mapstructure:"timeout"`
+	Tags        []string      `json:"tags" mapstructure:"tags"`
+	Token       string        `json:"token" mapstructure:"token"`
+	DSN         string        `json:"dsn" mapstructure:"dsn" log:"redact"`
+	Endpoint    string        `json:"endpoint" mapstructure:"endpoint" log:"redact"`
+}
+
+func TestConfigNode(t *testing.T) {
+	opts := &testConfigCmdOptions{
+		testOptions: testOptions{Server: testServerOptions{Mode: "debug", Limit: 3}, DB: "s3cret"},
+		Timeout:     90 * time.Second,
+		Tags:        []string{"a", "b"},
+		Token:       "plaintext-token",
+		DSN:         "root:pass@tcp(db:3306)/iam",
+	}
+	comment := func(key string) string { return key }
+	redact := func(s string) string { return strings.ReplaceAll(s, "s3cret", "******") }
+
+	var buf bytes.Buffer
+	if err := writeYAML(&buf, configNode(reflect.ValueOf(opts), "", comment, redact)); err != nil {
+		t.Fatal(err)
+	}
+	want := `server:
+  mode: debug # server.mode
+  limit: 3 # server.limit
+db: '******' # db
+timeout: 1m30s # timeout
+tags: [a, b] # tags
+token: '******' # token
+dsn: '******' # dsn
+endpoint: "" # endpoint
+`
+	if buf.String() != want {
+		t.Errorf("unexpected yaml:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	opts := &testConfigCmdOptions{Timeout: time.Second}
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.Duration("timeout", 0, "request timeout")
+
+	s := configSchema(reflect.ValueOf(opts), "", fs)
+	if s.AdditionalProperties != false || len(s.Properties) != 7 {
+		t.Fatalf("unexpected root schema %+v", s)
+	}
+	timeout := s.Properties["timeout"]
+	if timeout.Description != "request timeout" || timeout.Default != "1s" || timeout.Pattern == "" {
+		t.Errorf("unexpected timeout schema %+v", timeout)
+	}
+	if limit := s.Properties["server"].Properties["limit"]; limit.Type != "integer" || limit.Default != nil {
+		t.Errorf("unexpected server.limit schema %+v", limit)
+	}
+	if tags := s.Properties["tags"]; tags.Items == nil || tags.Items.Type != "string" {
+		t.Errorf("unexpected tags schema %+v", tags)
+	}
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/ahang7/go-IAM/pkg/secret"
+)
+
+func TestUnmarshalConfig_TrackSecrets(t *testing.T) {
+	t.Setenv("APP_TEST_MODE", "release-mode")
+	t.Setenv("APP_TEST_TOKEN", "token-from-env")
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("server.mode", "${env:APP_TEST_MODE}")
+	viper.Set("token", "${env:APP_TEST_TOKEN}")
+	viper.Set("dsn", "iam:dsn-password@tcp(db:3306)/iam")
+
+	opts := &testConfigCmdOptions{}
+	if err := unmarshalConfig(opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Server.Mode != "release-mode" || opts.Token != "token-from-env" {
+		t.Fatalf("unmarshalConfig() = %+v, want secret references resolved", opts)
+	}
+
+	// 只隐藏敏感配置项的值，非敏感配置项即使使用了密钥引用也原样输出
+	got := secret.Redact("mode=release-mode token=token-from-env dsn=iam:dsn-password@tcp(db:3306)/iam")
+	want := "mode=release-mode token=" + secret.Mask + " dsn=" + secret.Mask
+	if got != want {
+		t.Errorf("Redact() = %q, want %q", got, want)
+	}
+}
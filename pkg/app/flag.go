@@ -14,11 +14,9 @@ func normalizeFunc(f *pflag.FlagSet, name string) pflag.NormalizedName {
 	//	break
 	//}
 
-	// --my-flag == --my_flag == --my.flag
-	from := []string{"-", "_"}
-	to := "."
-	for _, sep := range from {
-		name = strings.Replace(name, sep, to, -1)
-	}
+	// --my-flag == --my_flag
+	// "." 用于分隔配置段，例如 --mysql.max-idle-connections 对应配置 mysql.max-idle-connections
+	name = strings.Replace(name, "_", "-", -1)
+
 	return pflag.NormalizedName(name)
 }
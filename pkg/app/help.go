@@ -2,34 +2,27 @@ package app
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
 	"github.com/spf13/pflag"
 )
 
-const helpTemplate = `
-Usage:
-	{{.UseLine}}
-Description:
-	{{.Short}}
-Commands:
-{{- range .Commands}}
-	{{.Name}}: {{if .Short}}
-{{- end}}
-`
-
 func helpCommand(name string) *cobra.Command {
 	return &cobra.Command{
-		Use:   "help",
-		Short: "help about any command",
+		Use:   "help [command]",
+		Short: "Help about any command",
 		Long: `Help provides help for any command in the application.
-		Simply type ` + name + `help [path to command] for full details`,
+Simply type ` + name + ` help [path to command] for full details.`,
 		Run: func(c *cobra.Command, args []string) {
 			cmd, _, e := c.Root().Find(args)
 			if cmd == nil || e != nil {
-				c.Printf("Unknown help topic %#q\n", args[0])
+				c.Printf("Unknown help topic %#q\n", args)
+				_ = c.Root().Usage()
 			} else {
 				cmd.InitDefaultHelpFlag()
 				_ = cmd.Help()
@@ -41,8 +34,127 @@ func helpCommand(name string) *cobra.Command {
 func addHelpCommandFlag(use string, fs *pflag.FlagSet) {
 	fs.BoolP(
 		"help",
-		"H",
+		"h",
 		false,
 		fmt.Sprintf("help for the %s command.", color.GreenString(strings.Split(use, " ")[0])),
 	)
 }
+
+// setUsageAndHelpFunc 设置按分组显示命令行参数的帮助信息，每组参数以分组名称为标题，
+// 只作用于 cmd 本身，未设置的下级命令仍使用 cobra 默认的帮助信息
+func setUsageAndHelpFunc(cmd *cobra.Command, fss FlagSet) {
+	defaultUsage, defaultHelp := cmd.UsageFunc(), cmd.HelpFunc()
+
+	cmd.SetUsageFunc(func(c *cobra.Command) error {
+		if c != cmd {
+			return defaultUsage(c)
+		}
+		printUsage(c.OutOrStderr(), c, fss)
+
+		return nil
+	})
+	cmd.SetHelpFunc(func(c *cobra.Command, args []string) {
+		if c != cmd {
+			defaultHelp(c, args)
+
+			return
+		}
+		desc := c.Long
+		if desc == "" {
+			desc = c.Short
+		}
+		if desc = strings.TrimSpace(desc); desc != "" {
+			fmt.Fprintf(c.OutOrStdout(), "%s\n\n", desc)
+		}
+		printUsage(c.OutOrStdout(), c, fss)
+	})
+}
+
+func printUsage(w io.Writer, c *cobra.Command, fss FlagSet) {
+	fmt.Fprintf(w, "Usage:\n  %s\n", c.UseLine())
+	if c.HasAvailableSubCommands() {
+		fmt.Fprintf(w, "  %s [command]\n\nAvailable Commands:\n", c.CommandPath())
+		for _, sub := range c.Commands() {
+			if sub.IsAvailableCommand() || sub.Name() == "help" {
+				fmt.Fprintf(w, "  %-*s %s\n", c.NamePadding(), sub.Name(), sub.Short)
+			}
+		}
+	}
+
+	for _, name := range fss.Names() {
+		fs := fss.Flags(name)
+		if !fs.HasAvailableFlags() {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s flags:\n\n%s", strings.ToUpper(name[:1])+name[1:], fs.FlagUsages())
+	}
+	// 子命令继承的父命令参数，例如默认日志配置的 --log.*
+	if inherited := c.InheritedFlags(); c.HasParent() && inherited.HasAvailableFlags() {
+		fmt.Fprintf(w, "\nInherited flags:\n\n%s", inherited.FlagUsages())
+	}
+
+	if c.HasAvailableSubCommands() {
+		fmt.Fprintf(w, "\nUse \"%s [command] --help\" for more information about a command.\n", c.CommandPath())
+	}
+}
+
+// completionCommand 生成 shell 自动补全脚本
+func completionCommand(name string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate the autocompletion script for the specified shell",
+		Long: `Generate the autocompletion script for ` + name + ` for the specified shell.
+
+To load completions in the current bash session:
+
+	source <(` + name + ` completion bash)
+
+To load completions for every new zsh session, execute once:
+
+	` + name + ` completion zsh > "${fpath[1]}/_` + name + `"`,
+		DisableFlagsInUseLine: true,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, out := cmd.Root(), cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			default:
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+		},
+	}
+}
+
+// manCommand 生成所有命令的 man page
+func manCommand(name string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "man [DIR]",
+		Short: "Generate man pages for all commands",
+		Long: `Generate man pages for ` + name + ` and all of its subcommands into DIR,
+the current directory by default.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+
+			root := cmd.Root()
+			root.DisableAutoGenTag = true
+
+			return doc.GenManTree(root, &doc.GenManHeader{
+				Title:   strings.ToUpper(root.Name()),
+				Section: "1",
+			}, dir)
+		},
+	}
+}
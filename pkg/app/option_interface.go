@@ -1,6 +1,11 @@
 package app
 
-import "github.com/spf13/pflag"
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/ahang7/go-IAM/pkg/tracing"
+)
 
 // FlagsIntf 提供命令行接口，定义命令行的具体实现
 // 该接口的实现由子配置结构体实现
@@ -42,6 +47,18 @@ type PrintableOptions interface {
 	String() string
 }
 
+// LoggableOptions 抽象包含日志配置的options，App 在调用 runFunc 之前使用该配置初始化日志。
+// 没有实现该接口的 App 使用默认日志配置，并添加 --log.* 命令行参数
+type LoggableOptions interface {
+	LogOptions() *log.Options
+}
+
+// TraceableOptions 抽象包含链路追踪配置的options，App 在调用 runFunc 之前使用该配置初始化链路追踪，
+// runFunc 返回后导出剩余的 span
+type TraceableOptions interface {
+	TracingOptions() *tracing.Options
+}
+
 // OptionsIntf 提供Options接口，定义Options的具体实现
 type OptionsIntf interface {
 	FlagsOptions
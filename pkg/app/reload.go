@@ -0,0 +1,255 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+)
+
+// ReloadFunc 配置热加载的回调，opts 为校验通过的新配置
+type ReloadFunc func(opts FlagsOptions) error
+
+type subscriber struct {
+	sections []string
+	fn       ReloadFunc
+}
+
+// Reloader 监听配置文件变化及 SIGHUP 信号，重新读取配置并执行 Validate，
+// 校验通过后通知订阅了发生变化的配置段的订阅者，全部应用成功后原子替换当前配置。
+// 只有被订阅的配置段可以热加载，其余配置段的变化只会被记录，需要重启后生效
+type Reloader struct {
+	newOptions func() FlagsOptions
+
+	// mu 保证同一时间只有一次重新加载
+	mu      sync.Mutex
+	current atomic.Value
+
+	subMu       sync.RWMutex
+	subscribers []subscriber
+
+	startOnce sync.Once
+}
+
+// NewReloader 创建配置热加载器，newOptions 返回带有默认值的新配置，用于接收重新读取的配置
+func NewReloader(newOptions func() FlagsOptions) *Reloader {
+	return &Reloader{newOptions: newOptions}
+}
+
+// Subscribe 订阅配置段，sections 为 mapstructure 配置键的前缀，例如 middleware、server.middlewares。
+// 任一配置段发生变化时调用一次 fn，被订阅的配置段视为可热加载
+func (r *Reloader) Subscribe(fn ReloadFunc, sections ...string) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	r.subscribers = append(r.subscribers, subscriber{sections: sections, fn: fn})
+}
+
+// Current 返回最近一次校验通过的配置
+func (r *Reloader) Current() FlagsOptions {
+	opts, _ := r.current.Load().(FlagsOptions)
+
+	return opts
+}
+
+// Start 以 opts 作为当前配置，开始监听配置文件变化及 SIGHUP 信号，只会启动一次
+func (r *Reloader) Start(opts FlagsOptions) {
+	r.startOnce.Do(func() {
+		r.current.Store(opts)
+
+		viper.OnConfigChange(func(e fsnotify.Event) {
+			log.Infof("config file %s changed, reloading", e.Name)
+			_ = r.Reload()
+		})
+		viper.WatchConfig()
+
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go func() {
+			for range hup {
+				log.Info("received SIGHUP, reloading config")
+				_ = r.Reload()
+			}
+		}()
+	})
+}
+
+// Reload 重新读取配置文件并校验，校验失败时保留当前配置并返回错误
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.reload(); err != nil {
+		log.Errorf("config reload rejected, keep the current config: %s", err.Error())
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *Reloader) reload() error {
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	opts := r.newOptions()
+	if err := unmarshalConfig(opts); err != nil {
+		return fmt.Errorf("unmarshal config: %w", err)
+	}
+	if err := validateOptions(opts, true); err != nil {
+		return err
+	}
+
+	changed, err := diffKeys(r.Current(), opts)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		log.Info("config reloaded, nothing changed")
+
+		return nil
+	}
+
+	r.subMu.RLock()
+	subscribers := append([]subscriber(nil), r.subscribers...)
+	r.subMu.RUnlock()
+
+	var reloadable, restart []string
+	for _, key := range changed {
+		if subscribed(subscribers, key) {
+			reloadable = append(reloadable, key)
+		} else {
+			restart = append(restart, key)
+		}
+	}
+
+	// 所有订阅者都应用成功后才发布新配置，任一订阅者失败时拒绝本次重新加载，不再通知其余订阅者
+	for _, s := range subscribers {
+		if !anyMatch(s.sections, reloadable) {
+			continue
+		}
+		if err := s.apply(opts); err != nil {
+			return fmt.Errorf("apply reloaded config of %s: %w", strings.Join(s.sections, ","), err)
+		}
+	}
+	r.current.Store(opts)
+
+	if len(reloadable) != 0 {
+		log.Infow("config reloaded", "applied", reloadable)
+	}
+	if len(restart) != 0 {
+		log.Warnw("config changed but requires restart to take effect", "keys", restart)
+	}
+
+	return nil
+}
+
+// apply 调用订阅者，订阅者 panic 时转换为错误，避免终止正在运行的服务
+func (s subscriber) apply(opts FlagsOptions) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return s.fn(opts)
+}
+
+// subscribed 判断配置键是否被订阅
+func subscribed(subscribers []subscriber, key string) bool {
+	for _, s := range subscribers {
+		if anyMatch(s.sections, []string{key}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// anyMatch 判断 keys 中是否有配置键属于 sections 中的配置段
+func anyMatch(sections, keys []string) bool {
+	for _, section := range sections {
+		for _, key := range keys {
+			if key == section || strings.HasPrefix(key, section+".") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// diffKeys 返回两份配置中值不同的配置键，配置键以 . 分隔
+func diffKeys(old, new FlagsOptions) ([]string, error) {
+	oldKeys, err := flatten(old)
+	if err != nil {
+		return nil, err
+	}
+	newKeys, err := flatten(new)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for key, v := range newKeys {
+		if ov, ok := oldKeys[key]; !ok || !reflect.DeepEqual(ov, v) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range oldKeys {
+		if _, ok := newKeys[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+// flatten 将配置按 json tag 展开为 配置键 -> 值，数组作为一个整体
+func flatten(opts FlagsOptions) (map[string]any, error) {
+	out := make(map[string]any)
+	if opts == nil {
+		return out, nil
+	}
+
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("unmarshal config: %w", err)
+	}
+
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		if sub, ok := v.(map[string]any); ok && len(sub) != 0 {
+			for k, sv := range sub {
+				key := k
+				if prefix != "" {
+					key = prefix + "." + k
+				}
+				walk(key, sv)
+			}
+
+			return
+		}
+		out[prefix] = v
+	}
+	walk("", m)
+
+	return out, nil
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+type testServerOptions struct {
+	Mode  string `json:"mode" mapstructure:"mode"`
+	Limit int    `json:"limit" mapstructure:"limit"`
+}
+
+type testOptions struct {
+	Server testServerOptions `json:"server" mapstructure:"server"`
+	DB     string            `json:"db" mapstructure:"db"`
+}
+
+func (o *testOptions) Flags() (fs FlagSet) {
+	o.AddFlags(fs.Flags("test"))
+
+	return
+}
+
+func (o *testOptions) AddFlags(*pflag.FlagSet) {}
+
+func (o *testOptions) Validate() []error {
+	if o.Server.Limit < 0 {
+		return []error{errors.New("limit must not be negative")}
+	}
+
+	return nil
+}
+
+func writeConfig(t *testing.T, file, content string) {
+	t.Helper()
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloader_Reload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.yaml")
+	writeConfig(t, file, "server:\n  mode: debug\n  limit: 1\ndb: a\n")
+	viper.Reset()
+	viper.SetConfigFile(file)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	initial := &testOptions{}
+	if err := viper.Unmarshal(initial); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewReloader(func() FlagsOptions { return &testOptions{} })
+	r.current.Store(FlagsOptions(initial))
+
+	var got *testOptions
+	calls := 0
+	r.Subscribe(func(opts FlagsOptions) error {
+		calls++
+		got = opts.(*testOptions)
+
+		return nil
+	}, "server.limit")
+
+	// 非订阅配置段变化不会通知订阅者，但会发布新配置
+	writeConfig(t, file, "server:\n  mode: release\n  limit: 1\ndb: b\n")
+	if err := r.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("subscriber should not be called, got %d calls", calls)
+	}
+	if r.Current().(*testOptions).DB != "b" {
+		t.Errorf("current config not swapped")
+	}
+
+	writeConfig(t, file, "server:\n  mode: release\n  limit: 5\ndb: b\n")
+	if err := r.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if calls != 1 || got.Server.Limit != 5 {
+		t.Errorf("subscriber called %d times with %+v", calls, got)
+	}
+
+	// 校验失败时保留当前配置
+	writeConfig(t, file, "server:\n  mode: release\n  limit: -1\ndb: c\n")
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected invalid config to be rejected")
+	}
+	if cur := r.Current().(*testOptions); cur.Server.Limit != 5 || cur.DB != "b" {
+		t.Errorf("current config changed after rejected reload: %+v", cur)
+	}
+	if calls != 1 {
+		t.Errorf("subscriber called after rejected reload")
+	}
+}
+
+func TestDiffKeys(t *testing.T) {
+	old := &testOptions{Server: testServerOptions{Mode: "debug", Limit: 1}, DB: "a"}
+	cur := &testOptions{Server: testServerOptions{Mode: "release", Limit: 1}, DB: "b"}
+
+	keys, err := diffKeys(old, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0] != "db" || keys[1] != "server.mode" {
+		t.Errorf("unexpected changed keys %v", keys)
+	}
+}
+
+func TestReloader_SubscriberPanic(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.yaml")
+	writeConfig(t, file, "server:\n  mode: debug\n  limit: 1\ndb: a\n")
+	viper.Reset()
+	viper.SetConfigFile(file)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	initial := &testOptions{}
+	if err := viper.Unmarshal(initial); err != nil {
+		t.Fatal(err)
+	}
+	r := NewReloader(func() FlagsOptions { return &testOptions{} })
+	r.current.Store(FlagsOptions(initial))
+
+	fail := true
+	r.Subscribe(func(opts FlagsOptions) error {
+		if fail {
+			panic("bad config")
+		}
+
+		return nil
+	}, "server.limit")
+
+	writeConfig(t, file, "server:\n  mode: debug\n  limit: 5\ndb: b\n")
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected the reload to be rejected when a subscriber panics")
+	}
+	if cur := r.Current().(*testOptions); cur.Server.Limit != 1 || cur.DB != "a" {
+		t.Errorf("current config changed after a failed apply: %+v", cur)
+	}
+
+	fail = false
+	if err := r.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if cur := r.Current().(*testOptions); cur.Server.Limit != 5 {
+		t.Errorf("current config = %+v, want the reloaded config", cur)
+	}
+}
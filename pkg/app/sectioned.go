@@ -4,8 +4,10 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// FlagSet 按名称分组的命令行参数，帮助信息中每组参数单独显示，顺序与创建顺序一致
 type FlagSet struct {
 	flags map[string]*pflag.FlagSet
+	order []string
 }
 
 func (fs *FlagSet) Flags(name string) *pflag.FlagSet {
@@ -14,6 +16,12 @@ func (fs *FlagSet) Flags(name string) *pflag.FlagSet {
 	}
 	if _, ok := fs.flags[name]; !ok {
 		fs.flags[name] = pflag.NewFlagSet(name, pflag.ExitOnError)
+		fs.order = append(fs.order, name)
 	}
 	return fs.flags[name]
 }
+
+// Names 按创建顺序返回分组名称
+func (fs *FlagSet) Names() []string {
+	return fs.order
+}
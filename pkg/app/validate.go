@@ -0,0 +1,188 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ValidationError 配置校验失败时返回，包含全部问题
+type ValidationError struct {
+	Errs []error
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid configuration, %d problem(s) found:", len(e.Errs))
+	for _, err := range e.Errs {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+
+	return b.String()
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.Errs
+}
+
+// validateOptions 依次调用 ApplyFlags、Complete 和 Validate，并检查配置文件中的未知配置键，汇总所有问题
+func validateOptions(opts FlagsOptions, checkConfigFile bool) error {
+	var errs []error
+
+	if checkConfigFile {
+		errs = append(errs, unknownConfigKeys(opts)...)
+	}
+	if o, ok := opts.(ConfigurableOptions); ok {
+		errs = append(errs, o.ApplyFlags()...)
+	}
+	if o, ok := opts.(CompletableOptions); ok {
+		if err := o.Complete(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, opts.Validate()...)
+
+	if len(errs) != 0 {
+		return &ValidationError{Errs: errs}
+	}
+
+	return nil
+}
+
+// unknownConfigKeys 返回配置文件中没有对应配置项的配置键，并给出最相近的配置键
+func unknownConfigKeys(opts any) []error {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		return nil
+	}
+	v := viper.New()
+	v.SetConfigFile(file)
+	v.SetConfigType(configFileType)
+	if err := v.ReadInConfig(); err != nil {
+		return []error{fmt.Errorf("read config file %s: %w", file, err)}
+	}
+
+	known := make(map[string]bool)
+	prefixes := make([]string, 0)
+	collectKeys(reflect.TypeOf(opts), "", known, &prefixes)
+	candidates := make([]string, 0, len(known))
+	for key := range known {
+		candidates = append(candidates, key)
+	}
+	sort.Strings(candidates)
+
+	var errs []error
+	for _, key := range v.AllKeys() {
+		if known[key] || underPrefix(key, prefixes) {
+			continue
+		}
+		if suggestion := closest(key, candidates); suggestion != "" {
+			errs = append(errs, fmt.Errorf("unknown config key %q in %s, did you mean %q?", key, file, suggestion))
+		} else {
+			errs = append(errs, fmt.Errorf("unknown config key %q in %s", key, file))
+		}
+	}
+
+	return errs
+}
+
+// collectKeys 根据 mapstructure tag 收集配置键，map 类型的配置项下可以有任意配置键，记录在 prefixes 中
+func collectKeys(t reflect.Type, prefix string, known map[string]bool, prefixes *[]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Map || t.Kind() == reflect.Interface:
+		if prefix != "" {
+			known[prefix] = true
+			*prefixes = append(*prefixes, prefix)
+		}
+
+		return
+	case t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}):
+		known[prefix] = true
+
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "squash") {
+			collectKeys(f.Type, prefix, known, prefixes)
+
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		key := strings.ToLower(name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		collectKeys(f.Type, key, known, prefixes)
+	}
+}
+
+func underPrefix(key string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(key, p+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// closest 返回编辑距离最小且足够相近的候选配置键，没有时返回空字符串
+func closest(key string, candidates []string) string {
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		d := levenshtein(key, c)
+		if bestDist == -1 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+
+	// 允许的编辑距离随配置键的最后一段的长度增加
+	last := key[strings.LastIndex(key, ".")+1:]
+	if bestDist == -1 || bestDist > max(2, len(last)/3) {
+		return ""
+	}
+
+	return best
+}
+
+// levenshtein 计算两个字符串的编辑距离
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnknownConfigKeys(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.yaml")
+	content := "server:\n  mode: debug\n  limt: 1\ndb: a\nlabels:\n  any: x\nextra: 1\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	viper.Reset()
+	viper.SetConfigFile(file)
+
+	type opts struct {
+		testOptions `mapstructure:",squash"`
+		Labels      map[string]string `mapstructure:"labels"`
+	}
+	errs := unknownConfigKeys(&opts{})
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 unknown keys, got %v", errs)
+	}
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	all := strings.Join(msgs, "\n")
+	if !strings.Contains(all, `"server.limt"`) || !strings.Contains(all, `did you mean "server.limit"`) {
+		t.Errorf("missing suggestion for server.limt: %s", all)
+	}
+	if !strings.Contains(all, `"extra"`) || strings.Contains(all, `"extra" in `+file+`, did you mean`) {
+		t.Errorf("unexpected report for extra: %s", all)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := validateOptions(&testOptions{Server: testServerOptions{Limit: -1}}, false)
+	if err == nil || !strings.Contains(err.Error(), "1 problem(s)") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// HMACAudience 使用用户密钥签发的 token 的 audience，与服务端的校验一致
+	HMACAudience = "iam.authz.ch.com"
+
+	// defaultHMACTTL 使用用户密钥签发的 token 的有效期
+	defaultHMACTTL = 5 * time.Minute
+	// expiryDelta token 在过期前这段时间内即视为过期，避免请求途中过期
+	expiryDelta = time.Minute
+)
+
+// Authenticator 为请求添加认证信息
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// invalidator 凭据被服务端拒绝(401)时由客户端调用，下一次认证时重新获取凭据，
+// 返回 false 表示凭据无法更新，不需要重试
+type invalidator interface {
+	invalidate() bool
+}
+
+// Token 登录获得的 JWT
+type Token struct {
+	AccessToken string
+	Expiry      time.Time
+}
+
+// Valid 判断 token 是否可用，Expiry 为零值表示永不过期
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Until(t.Expiry) > expiryDelta)
+}
+
+// TokenSource 提供 Bearer 认证使用的 token，每个请求都会调用 Token，实现需要自行缓存 token。
+// 实现了 Invalidate 方法的 TokenSource 会在 token 被服务端拒绝时得到通知
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// StaticToken 返回始终提供同一个 token 的 TokenSource
+func StaticToken(token string) TokenSource {
+	return staticToken{token: &Token{AccessToken: token}}
+}
+
+type staticToken struct {
+	token *Token
+}
+
+func (s staticToken) Token(context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+type basicAuth struct {
+	username string
+	password string
+}
+
+func (a *basicAuth) Authenticate(_ context.Context, req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+
+	return nil
+}
+
+type bearerAuth struct {
+	source TokenSource
+}
+
+func (a *bearerAuth) Authenticate(ctx context.Context, req *http.Request) error {
+	token, err := a.source.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	return nil
+}
+
+func (a *bearerAuth) invalidate() bool {
+	s, ok := a.source.(interface{ Invalidate() })
+	if ok {
+		s.Invalidate()
+	}
+
+	return ok
+}
+
+// loginTokenSource 使用用户名和密码登录获取 token，token 即将过期时先尝试刷新，刷新失败时重新登录
+type loginTokenSource struct {
+	client   *Client
+	username string
+	password string
+
+	mu    sync.Mutex
+	token *Token
+	// rejected token 被服务端拒绝，需要重新获取
+	rejected bool
+}
+
+func (s *loginTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.rejected && s.token.Valid() {
+		return s.token, nil
+	}
+	if s.token != nil {
+		if token, err := s.client.RefreshToken(ctx, s.token.AccessToken); err == nil {
+			s.token, s.rejected = token, false
+
+			return token, nil
+		}
+	}
+
+	token, err := s.client.Login(ctx, s.username, s.password)
+	if err != nil {
+		return nil, err
+	}
+	s.token, s.rejected = token, false
+
+	return token, nil
+}
+
+func (s *loginTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rejected = true
+}
+
+// hmacAuth 使用用户密钥为每个请求签发短期 token，token 头部的 kid 为 SecretID
+type hmacAuth struct {
+	secretID  string
+	secretKey string
+	ttl       time.Duration
+}
+
+func (a *hmacAuth) Authenticate(_ context.Context, req *http.Request) error {
+	now := time.Now()
+	token := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.RegisteredClaims{
+		Audience:  gojwt.ClaimStrings{HMACAudience},
+		IssuedAt:  gojwt.NewNumericDate(now),
+		NotBefore: gojwt.NewNumericDate(now),
+		ExpiresAt: gojwt.NewNumericDate(now.Add(a.ttl)),
+	})
+	token.Header["kid"] = a.secretID
+
+	signed, err := token.SignedString([]byte(a.secretKey))
+	if err != nil {
+		return fmt.Errorf("sign token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+signed)
+
+	return nil
+}
+
+// tokenResponse /login 和 /refresh 的响应
+type tokenResponse struct {
+	Token  string `json:"token"`
+	Expire string `json:"expire"`
+}
+
+// Login 使用用户名和密码登录，返回 token
+func (c *Client) Login(ctx context.Context, username, password string) (*Token, error) {
+	data, err := c.do(ctx, http.MethodPost, "/login", nil, &basicAuth{username: username, password: password})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseToken(data)
+}
+
+// RefreshToken 使用 token 换取新的 token，过期的 token 在服务端允许的刷新期限内同样可以刷新
+func (c *Client) RefreshToken(ctx context.Context, token string) (*Token, error) {
+	data, err := c.do(ctx, http.MethodPost, "/refresh", nil, &bearerAuth{source: StaticToken(token)})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseToken(data)
+}
+
+// Logout 使用客户端的认证方式通知服务端登出
+func (c *Client) Logout(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodPost, "/logout", nil, c.auth)
+
+	return err
+}
+
+func parseToken(data []byte) (*Token, error) {
+	var resp tokenResponse
+	if err := json.Unmarshal(data, &resp); err != nil || resp.Token == "" {
+		return nil, fmt.Errorf("unexpected token response: %s", data)
+	}
+	expiry, err := time.Parse(time.RFC3339, resp.Expire)
+	if err != nil {
+		return nil, fmt.Errorf("parse token expiry %q: %w", resp.Expire, err)
+	}
+
+	return &Token{AccessToken: resp.Token, Expiry: expiry}, nil
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+)
+
+// AuthzClient 鉴权接口
+type AuthzClient struct {
+	c *Client
+}
+
+// Authorize 判断 subject 是否可以对 resource 执行 action
+func (a *AuthzClient) Authorize(ctx context.Context, req *v1.AuthorizeRequest) (*v1.AuthorizeResponse, error) {
+	resp := &v1.AuthorizeResponse{}
+	if err := a.c.call(ctx, http.MethodPost, "/v1/authz", nil, req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,351 @@
+// Package client 是 IAM REST API 的 Go SDK。请求和响应使用 api/iam/v1 中的消息类型，
+// 支持 Basic、Bearer(自动登录及刷新 token)和使用用户密钥的 HMAC 签名三种认证方式，
+// 失败的请求按照退避策略重试，服务端返回的错误会还原为带有 pkg/errors 错误码的错误
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/errors"
+)
+
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultMaxRetries = 2
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 2 * time.Second
+)
+
+var (
+	marshaler   = protojson.MarshalOptions{UseProtoNames: true}
+	unmarshaler = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// Client IAM REST API 客户端，可以被多个 goroutine 同时使用
+type Client struct {
+	endpoint  string
+	http      *http.Client
+	auth      Authenticator
+	userAgent string
+
+	// timeout ctx 没有截止时间时，一次调用(包括重试)的超时时间，0 表示不限制
+	timeout    time.Duration
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// Option 客户端配置选项
+type Option func(*Client)
+
+// WithHTTPClient 设置发送请求使用的 http.Client
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithAuth 设置认证方式
+func WithAuth(auth Authenticator) Option {
+	return func(c *Client) {
+		c.auth = auth
+	}
+}
+
+// WithBasicAuth 每个请求都使用用户名和密码进行 Basic 认证
+func WithBasicAuth(username, password string) Option {
+	return WithAuth(&basicAuth{username: username, password: password})
+}
+
+// WithLogin 使用用户名和密码登录获取 token 进行 Bearer 认证，token 即将过期或被拒绝时自动刷新或重新登录
+func WithLogin(username, password string) Option {
+	return func(c *Client) {
+		c.auth = &bearerAuth{source: &loginTokenSource{client: c, username: username, password: password}}
+	}
+}
+
+// WithTokenSource 使用 src 提供的 token 进行 Bearer 认证
+func WithTokenSource(src TokenSource) Option {
+	return WithAuth(&bearerAuth{source: src})
+}
+
+// WithHMAC 使用用户密钥对每个请求签发短期 token 进行认证
+func WithHMAC(secretID, secretKey string) Option {
+	return WithAuth(&hmacAuth{secretID: secretID, secretKey: secretKey, ttl: defaultHMACTTL})
+}
+
+// WithTimeout 设置 ctx 没有截止时间时一次调用(包括重试)的超时时间，0 表示不限制
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetry 设置最大重试次数及指数退避的最小、最大间隔，maxRetries 为 0 表示不重试
+func WithRetry(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithUserAgent 设置 User-Agent 请求头
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// New 创建客户端，endpoint 为 IAM API 的地址，例如 http://127.0.0.1:8080
+func New(endpoint string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid endpoint %q: scheme must be http or https", endpoint)
+	}
+
+	c := &Client{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		http:       http.DefaultClient,
+		userAgent:  "iam-go-client",
+		timeout:    defaultTimeout,
+		maxRetries: defaultMaxRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c, nil
+}
+
+// Users 返回用户接口
+func (c *Client) Users() *UsersClient {
+	return &UsersClient{c: c}
+}
+
+// Secrets 返回密钥接口
+func (c *Client) Secrets() *SecretsClient {
+	return &SecretsClient{c: c}
+}
+
+// Policies 返回策略接口
+func (c *Client) Policies() *PoliciesClient {
+	return &PoliciesClient{c: c}
+}
+
+// Authz 返回鉴权接口
+func (c *Client) Authz() *AuthzClient {
+	return &AuthzClient{c: c}
+}
+
+// APIError 服务端返回的错误响应，Code 为 pkg/errors 的错误码。
+// 带有错误码的响应会被包装为 pkg/errors 的错误，可以使用 errors.IsCode 判断，errors.As 获取 APIError
+type APIError struct {
+	HTTPStatus int
+	Code       int
+	Message    string
+	Reference  string
+}
+
+func (e *APIError) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("%s (http status %d)", e.Message, e.HTTPStatus)
+	}
+
+	return fmt.Sprintf("%s (code %d, http status %d)", e.Message, e.Code, e.HTTPStatus)
+}
+
+// decodeError 解析错误响应，错误码在 errorCode 字段中，code 字段为 HTTP 状态码；
+// 认证中间件返回的错误只有 message 字段，没有错误码
+func decodeError(status int, data []byte) error {
+	var body struct {
+		httpcore.ErrResponse
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(data, &body)
+
+	apiErr := &APIError{
+		HTTPStatus: status,
+		Code:       body.ErrorCode,
+		Message:    body.Msg,
+		Reference:  body.Reference,
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = body.Message
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+	if apiErr.Code == 0 {
+		return apiErr
+	}
+
+	return errors.WrapC(apiErr, apiErr.Code, "%s", apiErr.Message)
+}
+
+// call 调用需要认证的 REST API，in 为空时不发送请求体，out 为空时忽略响应体
+func (c *Client) call(ctx context.Context, method, path string, query url.Values, in, out proto.Message) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = marshaler.Marshal(in); err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+	}
+	if len(query) != 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := c.do(ctx, method, path, body, c.auth)
+	if err != nil || out == nil || len(data) == 0 {
+		return err
+	}
+	if err := unmarshaler.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("unmarshal response of %s %s: %w", method, path, err)
+	}
+
+	return nil
+}
+
+// do 发送请求并按照退避策略重试，返回 2xx 响应的响应体。
+// 连接错误及 502、503、504 只对幂等的请求重试，429 对所有请求重试；401 时让认证方式刷新凭据后重试一次
+func (c *Client) do(ctx context.Context, method, path string, body []byte, auth Authenticator) ([]byte, error) {
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", c.userAgent)
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		// 将 ctx 中的 span 以 W3C trace context 传递给服务端
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+		if auth != nil {
+			if err := auth.Authenticate(ctx, req); err != nil {
+				return nil, fmt.Errorf("authenticate: %w", err)
+			}
+		}
+
+		status, header, data, err := c.send(req)
+		var retryAfter time.Duration
+		switch {
+		case err != nil:
+			if !idempotent(method) || attempt >= c.maxRetries || ctx.Err() != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+		case status == http.StatusUnauthorized && !reauthenticated:
+			if inv, ok := auth.(invalidator); !ok || !inv.invalidate() {
+				return nil, decodeError(status, data)
+			}
+			reauthenticated = true
+			attempt--
+
+			continue
+		case status == http.StatusTooManyRequests && attempt < c.maxRetries:
+			retryAfter = parseRetryAfter(header.Get("Retry-After"))
+		case retryableStatus(status) && idempotent(method) && attempt < c.maxRetries:
+		case status < 200 || status > 299:
+			return nil, decodeError(status, data)
+		default:
+			return data, nil
+		}
+
+		if err := sleep(ctx, max(retryAfter, c.backoff(attempt))); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", method, path, err)
+		}
+	}
+}
+
+func (c *Client) send(req *http.Request) (int, http.Header, []byte, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	return resp.StatusCode, resp.Header, data, nil
+}
+
+// backoff 返回第 attempt 次重试前的等待时间，指数增长并带有随机抖动
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.minBackoff << attempt
+	if d <= 0 || d > c.maxBackoff {
+		d = c.maxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	//nolint:gosec // 抖动不需要安全的随机数
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func retryableStatus(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// parseRetryAfter 解析以秒为单位的 Retry-After 响应头
+func parseRetryAfter(v string) time.Duration {
+	sec, err := strconv.Atoi(v)
+	if err != nil || sec < 0 {
+		return 0
+	}
+
+	return time.Duration(sec) * time.Second
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+	"github.com/ahang7/go-IAM/internal/pkg/code"
+	httpcore "github.com/ahang7/go-IAM/pkg/core/http"
+	"github.com/ahang7/go-IAM/pkg/errors"
+)
+
+func newTestClient(t *testing.T, mux *http.ServeMux, opts ...Option) *Client {
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	opts = append([]Option{WithRetry(2, time.Millisecond, 5*time.Millisecond)}, opts...)
+	c, err := New(srv.URL, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func writeToken(w http.ResponseWriter, token string) {
+	_ = json.NewEncoder(w).Encode(tokenResponse{Token: token, Expire: time.Now().Add(time.Hour).Format(time.RFC3339)})
+}
+
+func TestLogin(t *testing.T) {
+	var logins, refreshes atomic.Int32
+	var valid atomic.Value
+	valid.Store("t1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		logins.Add(1)
+		writeToken(w, valid.Load().(string))
+	})
+	mux.HandleFunc("POST /refresh", func(w http.ResponseWriter, r *http.Request) {
+		refreshes.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("GET /v1/users/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+valid.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"token is expired"}`))
+
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"` + r.PathValue("name") + `"}`))
+	})
+	c := newTestClient(t, mux, WithLogin("admin", "secret"))
+
+	ctx := context.Background()
+	for range 2 {
+		user, err := c.Users().Get(ctx, "colin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.GetName() != "colin" {
+			t.Errorf("unexpected user %v", user)
+		}
+	}
+	if logins.Load() != 1 {
+		t.Errorf("expected one login, got %d", logins.Load())
+	}
+
+	// token 被拒绝后先尝试刷新，刷新失败时重新登录
+	valid.Store("t2")
+	if _, err := c.Users().Get(ctx, "colin"); err != nil {
+		t.Fatal(err)
+	}
+	if logins.Load() != 2 || refreshes.Load() != 1 {
+		t.Errorf("expected a refresh and a re-login, got %d refreshes %d logins", refreshes.Load(), logins.Load())
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var gets, posts, limited atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/users", func(w http.ResponseWriter, r *http.Request) {
+		if gets.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+		_, _ = w.Write([]byte(`{"total_count":1,"items":[{"name":"colin"}]}`))
+	})
+	mux.HandleFunc("POST /v1/users", func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("POST /v1/authz", func(w http.ResponseWriter, r *http.Request) {
+		if limited.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+		_, _ = w.Write([]byte(`{"allowed":true}`))
+	})
+	c := newTestClient(t, mux, WithBasicAuth("admin", "secret"))
+	ctx := context.Background()
+
+	resp, err := c.Users().List(ctx, &v1.ListUsersRequest{})
+	if err != nil || len(resp.GetItems()) != 1 || gets.Load() != 3 {
+		t.Errorf("GET should be retried until success, got %v %v after %d attempts", resp, err, gets.Load())
+	}
+
+	// 非幂等请求不重试
+	if _, err := c.Users().Create(ctx, &v1.CreateUserRequest{Name: "colin"}); err == nil || posts.Load() != 1 {
+		t.Errorf("POST should not be retried, got %v after %d attempts", err, posts.Load())
+	}
+
+	// 429 对所有请求重试
+	if _, err := c.Authz().Authorize(ctx, &v1.AuthorizeRequest{}); err != nil || limited.Load() != 2 {
+		t.Errorf("429 should be retried, got %v after %d attempts", err, limited.Load())
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/secrets/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("username") != "bob" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(httpcore.ErrResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: code.ErrSecretNotFound,
+			Msg:       "Secret not found",
+			Reference: "https://example.com/errors",
+		})
+	})
+	c := newTestClient(t, mux)
+
+	_, err := c.Secrets().Get(context.Background(), &v1.GetSecretRequest{Username: "bob", Name: "s1"})
+	if !errors.IsCode(err, code.ErrSecretNotFound) {
+		t.Fatalf("expected code %d, got %v", code.ErrSecretNotFound, err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusNotFound || apiErr.Reference != "https://example.com/errors" {
+		t.Errorf("unexpected api error %#v", apiErr)
+	}
+}
+
+func TestHMAC(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/policies", func(w http.ResponseWriter, r *http.Request) {
+		raw := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token, err := gojwt.ParseWithClaims(raw, &gojwt.RegisteredClaims{}, func(token *gojwt.Token) (any, error) {
+			if token.Header["kid"] != "id1" {
+				return nil, errors.New("unknown kid")
+			}
+
+			return []byte("key1"), nil
+		})
+		if err != nil || !token.Claims.(*gojwt.RegisteredClaims).VerifyAudience(HMACAudience, true) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+	c := newTestClient(t, mux, WithHMAC("id1", "key1"))
+
+	if _, err := c.Policies().List(context.Background(), &v1.ListPoliciesRequest{}); err != nil {
+		t.Fatal(err)
+	}
+}
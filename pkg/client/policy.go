@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+)
+
+// PoliciesClient 策略接口，请求中的 username 为空时表示当前用户，只有管理员可以操作其他用户的策略
+type PoliciesClient struct {
+	c *Client
+}
+
+// Create 创建策略
+func (p *PoliciesClient) Create(ctx context.Context, req *v1.CreatePolicyRequest) (*v1.Policy, error) {
+	policy := &v1.Policy{}
+	if err := p.c.call(ctx, http.MethodPost, "/v1/policies", nil, req, policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// Get 获取策略
+func (p *PoliciesClient) Get(ctx context.Context, req *v1.GetPolicyRequest) (*v1.Policy, error) {
+	policy := &v1.Policy{}
+	path := "/v1/policies/" + url.PathEscape(req.GetName())
+	if err := p.c.call(ctx, http.MethodGet, path, ownerQuery(req.GetUsername()), nil, policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// List 分页获取策略列表
+func (p *PoliciesClient) List(ctx context.Context, req *v1.ListPoliciesRequest) (*v1.ListPoliciesResponse, error) {
+	resp := &v1.ListPoliciesResponse{}
+	query := pageQuery(req.GetUsername(), req.GetOffset(), req.GetLimit())
+	if err := p.c.call(ctx, http.MethodGet, "/v1/policies", query, nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Update 更新策略，req.Policy 中只有 statement 会被更新
+func (p *PoliciesClient) Update(ctx context.Context, req *v1.UpdatePolicyRequest) (*v1.Policy, error) {
+	policy := &v1.Policy{}
+	path := "/v1/policies/" + url.PathEscape(req.GetName())
+	if err := p.c.call(ctx, http.MethodPut, path, ownerQuery(req.GetUsername()), req.GetPolicy(), policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// Delete 删除策略
+func (p *PoliciesClient) Delete(ctx context.Context, req *v1.DeletePolicyRequest) error {
+	path := "/v1/policies/" + url.PathEscape(req.GetName())
+
+	return p.c.call(ctx, http.MethodDelete, path, ownerQuery(req.GetUsername()), nil, nil)
+}
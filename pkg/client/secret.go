@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+)
+
+// SecretsClient 密钥接口，请求中的 username 为空时表示当前用户，只有管理员可以操作其他用户的密钥
+type SecretsClient struct {
+	c *Client
+}
+
+// Create 创建密钥，返回的 Secret 中包含 secret_key
+func (s *SecretsClient) Create(ctx context.Context, req *v1.CreateSecretRequest) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	if err := s.c.call(ctx, http.MethodPost, "/v1/secrets", nil, req, secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// Get 获取密钥
+func (s *SecretsClient) Get(ctx context.Context, req *v1.GetSecretRequest) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	path := "/v1/secrets/" + url.PathEscape(req.GetName())
+	if err := s.c.call(ctx, http.MethodGet, path, ownerQuery(req.GetUsername()), nil, secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// List 分页获取密钥列表
+func (s *SecretsClient) List(ctx context.Context, req *v1.ListSecretsRequest) (*v1.ListSecretsResponse, error) {
+	resp := &v1.ListSecretsResponse{}
+	query := pageQuery(req.GetUsername(), req.GetOffset(), req.GetLimit())
+	if err := s.c.call(ctx, http.MethodGet, "/v1/secrets", query, nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Update 更新密钥，req.Secret 中只有 expires、description 会被更新
+func (s *SecretsClient) Update(ctx context.Context, req *v1.UpdateSecretRequest) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	path := "/v1/secrets/" + url.PathEscape(req.GetName())
+	if err := s.c.call(ctx, http.MethodPut, path, ownerQuery(req.GetUsername()), req.GetSecret(), secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// Delete 删除密钥
+func (s *SecretsClient) Delete(ctx context.Context, req *v1.DeleteSecretRequest) error {
+	path := "/v1/secrets/" + url.PathEscape(req.GetName())
+
+	return s.c.call(ctx, http.MethodDelete, path, ownerQuery(req.GetUsername()), nil, nil)
+}
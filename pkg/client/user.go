@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	v1 "github.com/ahang7/go-IAM/api/iam/v1"
+)
+
+// UsersClient 用户接口
+type UsersClient struct {
+	c *Client
+}
+
+// Create 创建用户
+func (u *UsersClient) Create(ctx context.Context, req *v1.CreateUserRequest) (*v1.User, error) {
+	user := &v1.User{}
+	if err := u.c.call(ctx, http.MethodPost, "/v1/users", nil, req, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Get 获取用户
+func (u *UsersClient) Get(ctx context.Context, name string) (*v1.User, error) {
+	user := &v1.User{}
+	if err := u.c.call(ctx, http.MethodGet, "/v1/users/"+url.PathEscape(name), nil, nil, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// List 分页获取用户列表
+func (u *UsersClient) List(ctx context.Context, req *v1.ListUsersRequest) (*v1.ListUsersResponse, error) {
+	resp := &v1.ListUsersResponse{}
+	query := pageQuery("", req.GetOffset(), req.GetLimit())
+	if err := u.c.call(ctx, http.MethodGet, "/v1/users", query, nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Update 更新用户，req.User 中只有 nickname、email、phone、is_admin 会被更新，未设置的字段会被清空
+func (u *UsersClient) Update(ctx context.Context, req *v1.UpdateUserRequest) (*v1.User, error) {
+	user := &v1.User{}
+	if err := u.c.call(ctx, http.MethodPut, "/v1/users/"+url.PathEscape(req.GetName()), nil, req.GetUser(), user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Delete 删除用户
+func (u *UsersClient) Delete(ctx context.Context, name string) error {
+	return u.c.call(ctx, http.MethodDelete, "/v1/users/"+url.PathEscape(name), nil, nil, nil)
+}
+
+// ownerQuery 返回资源所属用户的查询参数，username 为空时表示当前用户
+func ownerQuery(username string) url.Values {
+	if username == "" {
+		return nil
+	}
+
+	return url.Values{"username": []string{username}}
+}
+
+// pageQuery 返回列表接口的查询参数
+func pageQuery(username string, offset, limit int64) url.Values {
+	q := url.Values{}
+	if username != "" {
+		q.Set("username", username)
+	}
+	if offset != 0 {
+		q.Set("offset", strconv.FormatInt(offset, 10))
+	}
+	if limit != 0 {
+		q.Set("limit", strconv.FormatInt(limit, 10))
+	}
+
+	return q
+}
@@ -12,22 +12,27 @@ type Response struct {
 	Data interface{} `json:"data"`
 }
 
-// ErrResponse 错误返回包
+// ErrResponse 错误返回包，Code 为 HTTP 状态码，ErrorCode 为 pkg/errors 的错误码，客户端可以据此还原错误
 type ErrResponse struct {
 	Code      int    `json:"code"`
+	ErrorCode int    `json:"errorCode,omitempty"`
 	Msg       string `json:"msg"`
 	Reference string `json:"reference,omitempty"`
 }
 
-// WriteResponse 返回http response
+// WriteResponse 返回http response，err 同时通过 c.Error 记录，供访问日志、链路追踪和错误指标使用
 func WriteResponse(c *gin.Context, err error, data any) {
 	if err != nil {
+		_ = c.Error(err)
 		coder := errors.ParseCoder(err)
 		c.JSON(coder.HTTPStatus(), &ErrResponse{
 			Code:      coder.HTTPStatus(),
+			ErrorCode: coder.Code(),
 			Msg:       coder.String(),
 			Reference: coder.Reference(),
 		})
+
+		return
 	}
 	c.JSON(200, data)
 }
@@ -0,0 +1,124 @@
+// Package db 创建 gorm 数据库客户端，支持 MySQL、PostgreSQL 和 SQLite，
+// 并提供链路追踪插件、连接池指标以及通过 pkg/log 输出的日志记录器
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 支持的数据库驱动
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Drivers 返回支持的数据库驱动
+func Drivers() []string {
+	return []string{DriverMySQL, DriverPostgres, DriverSQLite}
+}
+
+// Options 与驱动无关的数据库配置。DSN 不为空时使用 DSN 连接，忽略 Host、UserName、Password、Database；
+// SQLite 的 Database 为数据库文件路径，":memory:" 表示内存数据库
+type Options struct {
+	// Driver 数据库驱动，为空时使用 DriverMySQL
+	Driver string
+	DSN    string
+
+	Host     string
+	UserName string
+	Password string
+	Database string
+
+	// TLS 连接 MySQL、PostgreSQL 时的 TLS 配置，SQLite 不支持
+	TLS TLSOptions
+
+	MaxIdleConnections    int
+	MaxOpenConnections    int
+	MaxConnectionLifeTime time.Duration
+	MaxConnectionIdleTime time.Duration
+
+	// LogLevel GORM 日志级别，1: silent、2: error、3: warn、4: info
+	LogLevel int
+	// SlowThreshold 慢查询阈值，为 0 时不检测慢查询
+	SlowThreshold time.Duration
+	// Logger GORM 日志记录器，为空时使用 NewLogger(LogLevel, SlowThreshold)
+	Logger logger.Interface
+}
+
+// NewClient 根据 opts.Driver 创建数据库客户端，并安装 TracingPlugin、设置连接池。
+// 数据库错误转换为 gorm.ErrDuplicatedKey 等与驱动无关的错误
+func NewClient(opts *Options) (*gorm.DB, error) {
+	var (
+		dialector gorm.Dialector
+		err       error
+	)
+	switch opts.Driver {
+	case "", DriverMySQL:
+		dialector, err = mysqlDialector(opts)
+	case DriverPostgres:
+		dialector, err = postgresDialector(opts)
+	case DriverSQLite:
+		dialector, err = sqliteDialector(opts)
+	default:
+		err = fmt.Errorf("unsupported database driver %q, must be one of %v", opts.Driver, Drivers())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l := opts.Logger
+	if l == nil {
+		l = NewLogger(logger.LogLevel(opts.LogLevel), opts.SlowThreshold)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: l, TranslateError: true})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(TracingPlugin{}); err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Driver == DriverSQLite && isSQLiteMemory(opts) {
+		// 内存数据库属于单个连接，连接关闭后数据丢失，只能使用一个不过期的连接
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+
+		return db, nil
+	}
+	sqlDB.SetMaxOpenConns(opts.MaxOpenConnections)
+	sqlDB.SetMaxIdleConns(opts.MaxIdleConnections)
+	sqlDB.SetConnMaxLifetime(opts.MaxConnectionLifeTime)
+	sqlDB.SetConnMaxIdleTime(opts.MaxConnectionIdleTime)
+
+	return db, nil
+}
+
+// Ping 检测 NewClient 创建的客户端是否可用，可以用于就绪检查
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(ctx)
+}
+
+// Close 关闭 NewClient 创建的客户端的连接池，用于优雅关闭
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.Close()
+}
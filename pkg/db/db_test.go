@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewClient_SQLite(t *testing.T) {
+	db, err := NewClient(&Options{Driver: DriverSQLite, Database: ":memory:", MaxConnectionLifeTime: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+
+	if got := sqlDB.Stats().MaxOpenConnections; got != 1 {
+		t.Errorf("MaxOpenConnections = %d, want 1 for an in-memory database", got)
+	}
+	var n int
+	if err := db.Raw("SELECT 1").Scan(&n).Error; err != nil || n != 1 {
+		t.Errorf("SELECT 1 = %d, %v", n, err)
+	}
+}
+
+func TestNewClient_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"unknown driver", Options{Driver: "oracle"}},
+		{"sqlite without file", Options{Driver: DriverSQLite}},
+		{"sqlite with tls", Options{Driver: DriverSQLite, Database: ":memory:", TLS: TLSOptions{Mode: TLSRequire}}},
+		{"unknown tls mode", Options{Driver: DriverPostgres, Host: "127.0.0.1:5432", TLS: TLSOptions{Mode: "prefer"}}},
+		{"missing ca", Options{Driver: DriverMySQL, Host: "127.0.0.1:3306", TLS: TLSOptions{Mode: TLSVerifyFull, CAFile: "/nonexistent"}}},
+		{"invalid mysql dsn", Options{Driver: DriverMySQL, DSN: "not a dsn"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClient(&tt.opts); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestDSN(t *testing.T) {
+	opts := &Options{Host: "db:5432", UserName: "iam", Password: "p@ss:word", Database: "iam"}
+
+	if got, want := postgresDSN(opts), "postgres://iam:p%40ss%3Aword@db:5432/iam?sslmode=disable"; got != want {
+		t.Errorf("postgresDSN() = %s, want %s", got, want)
+	}
+
+	cfg, err := mysqlConfig(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.FormatDSN(), "iam:p@ss:word@tcp(db:5432)/iam?loc=Local&parseTime=true&charset=utf8mb4"; got != want {
+		t.Errorf("mysql dsn = %s, want %s", got, want)
+	}
+}
+
+func TestTLSOptions(t *testing.T) {
+	if cfg, err := (TLSOptions{Mode: TLSDisable}).config("db"); cfg != nil || err != nil {
+		t.Errorf("disable = %v, %v, want nil", cfg, err)
+	}
+
+	cfg, err := TLSOptions{Mode: TLSRequire}.config("db")
+	if err != nil || !cfg.InsecureSkipVerify {
+		t.Errorf("require = %+v, %v, want InsecureSkipVerify", cfg, err)
+	}
+
+	cfg, err = TLSOptions{Mode: TLSVerifyCA}.config("db")
+	if err != nil || !cfg.InsecureSkipVerify || cfg.VerifyConnection == nil {
+		t.Errorf("verify-ca = %+v, %v, want chain verification only", cfg, err)
+	}
+
+	cfg, err = TLSOptions{Mode: TLSVerifyFull, ServerName: "db.internal"}.config("db")
+	if err != nil || cfg.InsecureSkipVerify || cfg.ServerName != "db.internal" {
+		t.Errorf("verify-full = %+v, %v, want full verification of db.internal", cfg, err)
+	}
+}
+
+func TestPingClose(t *testing.T) {
+	db, err := NewClient(&Options{Driver: DriverSQLite, Database: ":memory:", LogLevel: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Ping(context.Background(), db); err != nil {
+		t.Fatalf("Ping() = %v", err)
+	}
+	if err := Close(db); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	if err := Ping(context.Background(), db); err == nil {
+		t.Error("Ping() after Close should fail")
+	}
+}
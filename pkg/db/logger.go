@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// DefaultSlowThreshold 默认的慢查询阈值
+const DefaultSlowThreshold = 200 * time.Millisecond
+
+// Logger 通过 pkg/log 输出日志的 GORM 日志记录器，日志记录器名称为 gorm，通过 log.L 添加 ctx 中的 requestID、trace_id 等字段。
+// 级别为 logger.Error 及以上时输出执行出错的 SQL（记录不存在除外），logger.Warn 及以上时输出慢查询，logger.Info 时输出所有 SQL。
+// pkg/log 没有开启 debug 级别时，SQL 中的参数值以占位符输出
+type Logger struct {
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+var (
+	_ logger.Interface  = (*Logger)(nil)
+	_ gorm.ParamsFilter = (*Logger)(nil)
+)
+
+// NewLogger 创建 GORM 日志记录器，level 为 1（silent）到 4（info），slowThreshold 为 0 时不检测慢查询
+func NewLogger(level logger.LogLevel, slowThreshold time.Duration) *Logger {
+	return &Logger{level: level, slowThreshold: slowThreshold}
+}
+
+// LogMode 实现 logger.Interface，返回级别为 level 的副本，如 db.Debug() 使用 logger.Info
+func (l *Logger) LogMode(level logger.LogLevel) logger.Interface {
+	nl := *l
+	nl.level = level
+
+	return &nl
+}
+
+func (l *Logger) Info(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Info {
+		l.log(ctx).Infow(fmt.Sprintf(msg, data...), "source", source())
+	}
+}
+
+func (l *Logger) Warn(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Warn {
+		l.log(ctx).Warnw(fmt.Sprintf(msg, data...), "source", source())
+	}
+}
+
+func (l *Logger) Error(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Error {
+		l.log(ctx).Errorw(fmt.Sprintf(msg, data...), "source", source())
+	}
+}
+
+// Trace 实现 logger.Interface，在每条 SQL 执行后调用
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		l.log(ctx).Errorw("query failed", append(traceKeysAndValues(fc, elapsed), "error", err)...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		l.log(ctx).Warnw("slow query", append(traceKeysAndValues(fc, elapsed), "slow", true, "threshold", l.slowThreshold)...)
+	case l.level >= logger.Info:
+		l.log(ctx).Infow("query", traceKeysAndValues(fc, elapsed)...)
+	}
+}
+
+// ParamsFilter 实现 gorm.ParamsFilter，pkg/log 没有开启 debug 级别时去掉参数值，SQL 中保留占位符
+func (l *Logger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	if l.log(ctx).V(log.DebugLevel).Enabled() {
+		return sql, params
+	}
+
+	return sql, nil
+}
+
+func (l *Logger) log(ctx context.Context) log.Logger {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return log.L(ctx).WithName("gorm")
+}
+
+// traceKeysAndValues 返回 SQL、影响的行数、耗时以及调用位置，影响的行数未知时不输出
+func traceKeysAndValues(fc func() (string, int64), elapsed time.Duration) []any {
+	sql, rows := fc()
+	kvs := []any{"sql", sql, "elapsed", elapsed, "source", source()}
+	if rows >= 0 {
+		kvs = append(kvs, "rows", rows)
+	}
+
+	return kvs
+}
+
+var (
+	// gormDir gorm 源码所在目录
+	gormDir = func() string {
+		file, _ := runtime.FuncForPC(reflect.ValueOf(gorm.Open).Pointer()).FileLine(0)
+		return filepath.Dir(file) + "/"
+	}()
+	// packageDir 本包源码所在目录
+	packageDir = func() string {
+		_, file, _, _ := runtime.Caller(0)
+		return filepath.Dir(file) + "/"
+	}()
+)
+
+// source 返回执行 SQL 的业务代码位置，跳过 gorm 及本包除测试以外的调用栈。
+// gorm 的 utils.FileWithLineNum 只跳过 gorm 自身，会返回本文件的位置
+func source() string {
+	pcs := [16]uintptr{}
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	for {
+		f, more := frames.Next()
+		internal := strings.HasPrefix(f.File, gormDir) || strings.HasPrefix(f.File, packageDir)
+		if f.File != "" && (!internal || strings.HasSuffix(f.File, "_test.go")) {
+			return f.File + ":" + strconv.Itoa(f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/ahang7/go-IAM/pkg/log/logtest"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestLogger_Trace(t *testing.T) {
+	fc := func() (string, int64) { return "SELECT * FROM `user` WHERE name = ?", 1 }
+	tests := []struct {
+		name    string
+		level   logger.LogLevel
+		elapsed time.Duration
+		err     error
+		want    []logtest.Entry
+	}{
+		{"silent", logger.Silent, time.Second, errors.New("failed"), nil},
+		{"error", logger.Error, 0, errors.New("failed"), []logtest.Entry{{Level: log.ErrorLevel, Message: "query failed"}}},
+		{"record not found", logger.Info, 0, gorm.ErrRecordNotFound, []logtest.Entry{{Level: log.InfoLevel, Message: "query"}}},
+		{"slow", logger.Warn, time.Second, nil, []logtest.Entry{{Level: log.WarnLevel, Message: "slow query"}}},
+		{"slow below warn", logger.Error, time.Second, nil, nil},
+		{"fast", logger.Warn, 0, nil, nil},
+		{"info", logger.Info, 0, nil, []logtest.Entry{{Level: log.InfoLevel, Message: "query"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := logtest.Observe(t, log.DebugLevel)
+			l := NewLogger(tt.level, 100*time.Millisecond)
+			l.Trace(context.Background(), time.Now().Add(-tt.elapsed), fc, tt.err)
+
+			got := logtest.FromObserved(logs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d entries, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				if got[i].Level != w.Level || got[i].Message != w.Message || got[i].Name != "gorm" {
+					t.Errorf("entry %d = %s %q %q, want %s %q gorm", i, got[i].Level, got[i].Name, got[i].Message, w.Level, w.Message)
+				}
+				if got[i].Fields["sql"] != "SELECT * FROM `user` WHERE name = ?" || got[i].Fields["rows"] != int64(1) {
+					t.Errorf("entry %d fields = %v", i, got[i].Fields)
+				}
+			}
+		})
+	}
+}
+
+func TestLogger_ParamsFilter(t *testing.T) {
+	l := NewLogger(logger.Info, 0)
+
+	logtest.Observe(t, log.InfoLevel)
+	if _, params := l.ParamsFilter(context.Background(), "SELECT ?", "secret"); params != nil {
+		t.Errorf("params = %v, want hidden when debug is off", params)
+	}
+
+	logtest.Observe(t, log.DebugLevel)
+	if _, params := l.ParamsFilter(context.Background(), "SELECT ?", "secret"); len(params) != 1 {
+		t.Errorf("params = %v, want kept when debug is on", params)
+	}
+}
@@ -0,0 +1,18 @@
+package db
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// NewStatsCollector 返回采集连接池状态 sql.DB.Stats() 的 prometheus 采集器，
+// 指标名称以 go_sql_ 开头，name 作为 db_name 标签区分不同的数据库
+func NewStatsCollector(db *gorm.DB, name string) (prometheus.Collector, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	return collectors.NewDBStatsCollector(sqlDB, name), nil
+}
@@ -0,0 +1,80 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lockRetryInterval PostgreSQL 重试获取咨询锁的间隔
+const lockRetryInterval = 500 * time.Millisecond
+
+// lock 在 conn 上获取咨询锁，返回释放锁的函数。锁的名称由当前数据库及版本表的名称组成，
+// 同一个服务器上的不同数据库互不影响
+func (m *Migrator) lock(ctx context.Context, conn *gorm.DB) (func() error, error) {
+	name := conn.Migrator().CurrentDatabase() + "." + m.table
+
+	switch dialect := conn.Dialector.Name(); dialect {
+	case "mysql":
+		return mysqlLock(conn, name, m.lockTimeout)
+	case "postgres":
+		return postgresLock(ctx, conn, name, m.lockTimeout)
+	case "sqlite":
+		return func() error { return nil }, nil
+	default:
+		return nil, fmt.Errorf("advisory lock is not supported by %s", dialect)
+	}
+}
+
+// mysqlLock GET_LOCK 在超时前获取到锁时返回 1，超时返回 0，出错返回 NULL
+func mysqlLock(conn *gorm.DB, name string, timeout time.Duration) (func() error, error) {
+	var acquired *int
+	if err := conn.Raw("SELECT GET_LOCK(?, ?)", name, int(timeout.Seconds())).Scan(&acquired).Error; err != nil {
+		return nil, fmt.Errorf("acquire migration lock: %w", err)
+	}
+	if acquired == nil || *acquired != 1 {
+		return nil, fmt.Errorf("acquire migration lock %s: timed out after %s", name, timeout)
+	}
+
+	return func() error {
+		return unlockConn(conn).Exec("SELECT RELEASE_LOCK(?)", name).Error
+	}, nil
+}
+
+// postgresLock 咨询锁的键为名称的 64 位哈希，pg_advisory_lock 会一直阻塞，使用 pg_try_advisory_lock 重试直到超时
+func postgresLock(ctx context.Context, conn *gorm.DB, name string, timeout time.Duration) (func() error, error) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	key := int64(h.Sum64())
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var acquired bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", key).Scan(&acquired).Error; err != nil {
+			return nil, fmt.Errorf("acquire migration lock: %w", err)
+		}
+		if acquired {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("acquire migration lock %s: timed out after %s", name, timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("acquire migration lock: %w", ctx.Err())
+		case <-time.After(lockRetryInterval):
+		}
+	}
+
+	return func() error {
+		return unlockConn(conn).Exec("SELECT pg_advisory_unlock(?)", key).Error
+	}, nil
+}
+
+// unlockConn 释放锁不受 ctx 取消的影响，否则连接回到连接池后仍然持有锁
+func unlockConn(conn *gorm.DB) *gorm.DB {
+	return conn.WithContext(context.Background())
+}
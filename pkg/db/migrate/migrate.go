@@ -0,0 +1,407 @@
+// Package migrate 执行版本化的数据库迁移。
+//
+// 迁移文件命名为 <版本>_<名称>.up.sql 和 <版本>_<名称>.down.sql，版本为正整数，按版本从小到大执行，
+// 没有 .down.sql 的迁移不能回滚。已执行的版本记录在 schema_migrations 表中。
+// 每个迁移在事务中执行，MySQL 的 DDL 会隐式提交事务，执行失败时需要手动清理。
+//
+// 执行迁移前获取数据库的咨询锁（MySQL 的 GET_LOCK、PostgreSQL 的 pg_try_advisory_lock），
+// 多个副本同时启动并执行迁移时依次进行，后获取到锁的副本不会重复执行。SQLite 为嵌入式数据库，不加锁。
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultTable 默认记录已执行版本的表
+	DefaultTable = "schema_migrations"
+	// DefaultLockTimeout 默认等待咨询锁的时间
+	DefaultLockTimeout = time.Minute
+)
+
+// Migration 一个版本的迁移
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	// Down 为空时不能回滚
+	Down string
+}
+
+// Status 迁移的执行状态
+type Status struct {
+	Migration
+	Applied bool
+	// AppliedAt 执行时间，未执行时为零值
+	AppliedAt time.Time
+}
+
+// Migrator 执行迁移
+type Migrator struct {
+	db          *gorm.DB
+	migrations  []Migration
+	table       string
+	lockTimeout time.Duration
+}
+
+// Option Migrator 的配置选项
+type Option func(*Migrator)
+
+// WithTable 设置记录已执行版本的表，默认为 DefaultTable
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// WithLockTimeout 设置等待咨询锁的时间，默认为 DefaultLockTimeout
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTimeout = timeout
+	}
+}
+
+// New 读取 fsys 根目录下的迁移文件并创建 Migrator
+func New(db *gorm.DB, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{
+		db:          db,
+		migrations:  migrations,
+		table:       DefaultTable,
+		lockTimeout: DefaultLockTimeout,
+	}
+	for _, o := range opts {
+		o(m)
+	}
+
+	return m, nil
+}
+
+// Load 读取 fsys 根目录下的迁移文件，按版本排序，忽略其它文件
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		base := strings.TrimSuffix(e.Name(), ".sql")
+		base, direction, ok := cutLast(base, ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("migration %s: name must end with .up.sql or .down.sql", e.Name())
+		}
+		v, name, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s: version must be a positive integer", e.Name())
+		}
+		data, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %s: version %d is also used by %s", e.Name(), version, m.Name)
+		}
+		sql := &m.Up
+		if direction == "down" {
+			sql = &m.Down
+		}
+		*sql = string(data)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %d %s has no up migration", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
+
+// Migrations 返回所有迁移
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Latest 返回最新的版本，没有迁移时返回 0
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up 执行所有未执行的迁移，返回本次执行的迁移。
+// 数据库中有比所有迁移更新的版本时（例如滚动升级时旧版本的副本重启）忽略这些版本
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		done, err = m.migrate(ctx, conn, applied, m.Latest())
+
+		return err
+	})
+
+	return done, err
+}
+
+// Down 回滚最新的一个已执行的迁移，没有已执行的迁移时什么都不做
+func (m *Migrator) Down(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		var current, target uint64
+		for v := range applied {
+			current = max(current, v)
+		}
+		if current == 0 {
+			return nil
+		}
+		for _, mg := range m.migrations {
+			if mg.Version < current && applied[mg.Version] != nil {
+				target = mg.Version
+			}
+		}
+		if err := m.checkUnknown(applied, target); err != nil {
+			return err
+		}
+		done, err = m.migrate(ctx, conn, applied, target)
+
+		return err
+	})
+
+	return done, err
+}
+
+// To 迁移到 version：回滚高于 version 的已执行迁移，并执行不高于 version 的未执行迁移，version 为 0 时回滚所有迁移
+func (m *Migrator) To(ctx context.Context, version uint64) ([]Migration, error) {
+	if version != 0 && m.find(version) == nil {
+		return nil, fmt.Errorf("unknown migration version %d", version)
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		if err := m.checkUnknown(applied, version); err != nil {
+			return err
+		}
+		done, err = m.migrate(ctx, conn, applied, version)
+
+		return err
+	})
+
+	return done, err
+}
+
+// Status 返回所有迁移的执行状态，不加锁
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	db := m.db.WithContext(ctx)
+	var applied map[uint64]*schemaMigration
+	if db.Migrator().HasTable(m.table) {
+		var err error
+		if applied, err = m.applied(db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		s := Status{Migration: mg}
+		if r := applied[mg.Version]; r != nil {
+			s.Applied, s.AppliedAt = true, r.AppliedAt
+		}
+		statuses = append(statuses, s)
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) find(version uint64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+
+	return nil
+}
+
+// checkUnknown 数据库中有高于 target 且没有迁移文件的版本时无法回滚，返回错误
+func (m *Migrator) checkUnknown(applied map[uint64]*schemaMigration, target uint64) error {
+	for v, r := range applied {
+		if v > target && m.find(v) == nil {
+			return fmt.Errorf("cannot roll back migration %d %s: migration file not found", v, r.Name)
+		}
+	}
+
+	return nil
+}
+
+// migrate 从高到低回滚高于 target 的已执行迁移，再从低到高执行不高于 target 的未执行迁移
+func (m *Migrator) migrate(ctx context.Context, conn *gorm.DB, applied map[uint64]*schemaMigration, target uint64) ([]Migration, error) {
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mg := m.migrations[i]
+		if mg.Version <= target || applied[mg.Version] == nil {
+			continue
+		}
+		if strings.TrimSpace(mg.Down) == "" {
+			return done, fmt.Errorf("migration %d %s is irreversible", mg.Version, mg.Name)
+		}
+		log.L(ctx).Infof("rolling back migration %d %s", mg.Version, mg.Name)
+		if err := m.run(conn, mg, mg.Down, false); err != nil {
+			return done, err
+		}
+		done = append(done, mg)
+	}
+	for _, mg := range m.migrations {
+		if mg.Version > target || applied[mg.Version] != nil {
+			continue
+		}
+		log.L(ctx).Infof("applying migration %d %s", mg.Version, mg.Name)
+		if err := m.run(conn, mg, mg.Up, true); err != nil {
+			return done, err
+		}
+		done = append(done, mg)
+	}
+
+	return done, nil
+}
+
+// run 在事务中执行迁移的 SQL，并记录或删除版本
+func (m *Migrator) run(conn *gorm.DB, mg Migration, sql string, up bool) error {
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range statements(sql) {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		if up {
+			return tx.Table(m.table).Create(&schemaMigration{Version: mg.Version, Name: mg.Name, AppliedAt: time.Now().UTC()}).Error
+		}
+
+		return tx.Table(m.table).Where("version = ?", mg.Version).Delete(&schemaMigration{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("migration %d %s: %w", mg.Version, mg.Name, err)
+	}
+
+	return nil
+}
+
+// statements 将 SQL 按行尾的分号拆分为多条语句，MySQL 驱动默认不支持一次执行多条语句。
+// 只包含注释的语句会被忽略，语句中间的分号需要避免出现在行尾
+func statements(sql string) []string {
+	var (
+		stmts []string
+		b     strings.Builder
+	)
+	flush := func() {
+		if s := strings.TrimSpace(b.String()); s != "" {
+			stmts = append(stmts, s)
+		}
+		b.Reset()
+	}
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		if strings.HasSuffix(trimmed, ";") {
+			b.WriteString(strings.TrimSuffix(trimmed, ";"))
+			flush()
+
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	flush()
+
+	return stmts
+}
+
+// schemaMigration 已执行的版本
+type schemaMigration struct {
+	Version   uint64    `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name;size:255;not null"`
+	AppliedAt time.Time `gorm:"column:applied_at;not null"`
+}
+
+// applied 返回已执行的版本
+func (m *Migrator) applied(db *gorm.DB) (map[uint64]*schemaMigration, error) {
+	var rows []*schemaMigration
+	if err := db.Table(m.table).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("read applied migrations: %w", err)
+	}
+
+	applied := make(map[uint64]*schemaMigration, len(rows))
+	for _, r := range rows {
+		applied[r.Version] = r
+	}
+
+	return applied, nil
+}
+
+// withLock 在同一个连接上获取咨询锁、创建版本表并执行 fn
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) (err error) {
+		unlock, err := m.lock(ctx, conn)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.Join(err, unlock())
+		}()
+
+		if err := conn.Table(m.table).AutoMigrate(&schemaMigration{}); err != nil {
+			return fmt.Errorf("create table %s: %w", m.table, err)
+		}
+
+		return fn(conn)
+	})
+}
@@ -0,0 +1,155 @@
+package migrate
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ahang7/go-IAM/pkg/db"
+	"gorm.io/gorm"
+)
+
+var testFS = fstest.MapFS{
+	"0001_create_a.up.sql":   {Data: []byte("-- table a\nCREATE TABLE a (id INTEGER);\nCREATE INDEX idx_a ON a (id);\n")},
+	"0001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	"0002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER);")},
+	"0002_create_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	"0003_create_c.up.sql":   {Data: []byte("CREATE TABLE c (id INTEGER);")},
+	"README.md":              {Data: []byte("ignored")},
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, *gorm.DB) {
+	t.Helper()
+
+	gdb, err := db.NewClient(&db.Options{Driver: db.DriverSQLite, Database: ":memory:", LogLevel: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sqlDB, _ := gdb.DB()
+		_ = sqlDB.Close()
+	})
+	m, err := New(gdb, testFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m, gdb
+}
+
+func versions(migrations []Migration) []uint64 {
+	vs := []uint64{}
+	for _, m := range migrations {
+		vs = append(vs, m.Version)
+	}
+
+	return vs
+}
+
+func applied(t *testing.T, m *Migrator) []uint64 {
+	t.Helper()
+
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs := []uint64{}
+	for _, s := range statuses {
+		if s.Applied {
+			vs = append(vs, s.Version)
+		}
+	}
+
+	return vs
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	m, gdb := newTestMigrator(t)
+
+	if got := applied(t, m); len(got) != 0 {
+		t.Fatalf("applied before Up = %v", got)
+	}
+	done, err := m.To(ctx, 2)
+	if err != nil || !reflect.DeepEqual(versions(done), []uint64{1, 2}) {
+		t.Fatalf("To(2) = %v, %v", versions(done), err)
+	}
+	if done, err = m.Up(ctx); err != nil || !reflect.DeepEqual(versions(done), []uint64{3}) {
+		t.Fatalf("Up() = %v, %v", versions(done), err)
+	}
+	if done, err = m.Up(ctx); err != nil || len(done) != 0 {
+		t.Fatalf("second Up() = %v, %v, want nothing to do", versions(done), err)
+	}
+	if !gdb.Migrator().HasTable("c") {
+		t.Error("table c was not created")
+	}
+
+	// 版本 3 没有 down 迁移
+	if _, err := m.Down(ctx); err == nil {
+		t.Error("Down() of an irreversible migration should fail")
+	}
+	if got := applied(t, m); !reflect.DeepEqual(got, []uint64{1, 2, 3}) {
+		t.Errorf("applied after failed Down = %v", got)
+	}
+	gdb.Exec("DELETE FROM schema_migrations WHERE version = 3")
+
+	if done, err = m.Down(ctx); err != nil || !reflect.DeepEqual(versions(done), []uint64{2}) {
+		t.Fatalf("Down() = %v, %v", versions(done), err)
+	}
+	if gdb.Migrator().HasTable("b") {
+		t.Error("table b was not dropped")
+	}
+	if done, err = m.To(ctx, 0); err != nil || !reflect.DeepEqual(versions(done), []uint64{1}) {
+		t.Fatalf("To(0) = %v, %v", versions(done), err)
+	}
+	if _, err := m.To(ctx, 9); err == nil {
+		t.Error("To() an unknown version should fail")
+	}
+}
+
+func TestMigrator_UnknownVersion(t *testing.T) {
+	ctx := context.Background()
+	m, gdb := newTestMigrator(t)
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// 更新的副本执行了版本 4
+	gdb.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (4, 'newer', CURRENT_TIMESTAMP)")
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Errorf("Up() with a newer version applied = %v, want nil", err)
+	}
+	if _, err := m.To(ctx, 3); err == nil {
+		t.Error("To() below an unknown applied version should fail")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"bad suffix", fstest.MapFS{"0001_a.sql": {}}},
+		{"bad version", fstest.MapFS{"x_a.up.sql": {Data: []byte("SELECT 1;")}}},
+		{"zero version", fstest.MapFS{"0_a.up.sql": {Data: []byte("SELECT 1;")}}},
+		{"duplicate version", fstest.MapFS{"1_a.up.sql": {Data: []byte("SELECT 1;")}, "1_b.up.sql": {Data: []byte("SELECT 1;")}}},
+		{"down only", fstest.MapFS{"1_a.down.sql": {Data: []byte("SELECT 1;")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.fsys); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestStatements(t *testing.T) {
+	sql := "-- comment\nCREATE TABLE a (\n  id INTEGER\n);\n\nINSERT INTO a VALUES (1);\nSELECT 1"
+	want := []string{"CREATE TABLE a (\n  id INTEGER\n)", "INSERT INTO a VALUES (1)", "SELECT 1"}
+	if got := statements(sql); !reflect.DeepEqual(got, want) {
+		t.Errorf("statements() = %q, want %q", got, want)
+	}
+}
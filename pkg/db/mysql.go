@@ -1,11 +1,15 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
+	"net"
 	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 type MySQLOptions struct {
@@ -19,36 +23,92 @@ type MySQLOptions struct {
 	MaxConnectionLifeTime time.Duration
 	MaxConnectionIdleTime time.Duration
 
+	// LogLevel GORM 日志级别，1: silent、2: error、3: warn、4: info
 	LogLevel int
-	//Logger   logger.Interface
+	// SlowThreshold 慢查询阈值，为 0 时不检测慢查询
+	SlowThreshold time.Duration
+	// Logger GORM 日志记录器，为空时使用 NewLogger(LogLevel, SlowThreshold)
+	Logger logger.Interface
 }
 
-// NewMySQLClient 通过MysqlOptions struct做传值
+// NewMySQLClient 通过MysqlOptions struct做传值，等同于 Driver 为 DriverMySQL 的 NewClient
 func NewMySQLClient(opts *MySQLOptions) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		opts.UserName,
-		opts.Password,
-		opts.Host,
-		opts.Database,
-	)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	return NewClient(&Options{
+		Driver:                DriverMySQL,
+		Host:                  opts.Host,
+		UserName:              opts.UserName,
+		Password:              opts.Password,
+		Database:              opts.Database,
+		MaxIdleConnections:    opts.MaxIdleConnections,
+		MaxOpenConnections:    opts.MaxOpenConnections,
+		MaxConnectionLifeTime: opts.MaxConnectionLifeTime,
+		MaxConnectionIdleTime: opts.MaxConnectionIdleTime,
+		LogLevel:              opts.LogLevel,
+		SlowThreshold:         opts.SlowThreshold,
+		Logger:                opts.Logger,
+	})
+}
+
+// mysqlConfig 解析 DSN 或由结构化字段生成 MySQL 驱动配置，生成的配置使用 utf8mb4 字符集并将时间解析为本地时间
+func mysqlConfig(opts *Options) (*mysqldriver.Config, error) {
+	if opts.DSN != "" {
+		cfg, err := mysqldriver.ParseDSN(opts.DSN)
+		if err != nil {
+			return nil, fmt.Errorf("parse mysql dsn: %w", err)
+		}
+
+		return cfg, nil
+	}
+
+	cfg := mysqldriver.NewConfig()
+	cfg.User = opts.UserName
+	cfg.Passwd = opts.Password
+	cfg.Net = "tcp"
+	cfg.Addr = opts.Host
+	cfg.DBName = opts.Database
+	cfg.Params = map[string]string{"charset": "utf8mb4"}
+	cfg.ParseTime = true
+	cfg.Loc = time.Local
+
+	return cfg, nil
+}
+
+func mysqlDialector(opts *Options) (gorm.Dialector, error) {
+	cfg, err := mysqlConfig(opts)
 	if err != nil {
 		return nil, err
 	}
-
-	sqlDB, err := db.DB()
+	if err := mysqlTLS(cfg, opts.TLS); err != nil {
+		return nil, err
+	}
+	sqlDB, err := openMySQL(cfg)
 	if err != nil {
 		return nil, err
 	}
-	sqlDB.SetMaxOpenConns(opts.MaxOpenConnections)
-	sqlDB.SetMaxIdleConns(opts.MaxIdleConnections)
-	sqlDB.SetConnMaxLifetime(opts.MaxConnectionLifeTime)
-	sqlDB.SetConnMaxIdleTime(opts.MaxConnectionIdleTime)
 
-	return db, nil
+	return mysql.New(mysql.Config{Conn: sqlDB, DSNConfig: cfg}), nil
 }
 
-// NewMySQLClientWith TODO：通过Options模式创建MySQL客户端
-//func NewMySQLClientWith() {
-//
-//}
+// mysqlTLS 启用 TLS 时根据 tlsOpts 设置 cfg.TLS，未启用时保留 DSN 中的 tls 参数
+func mysqlTLS(cfg *mysqldriver.Config, tlsOpts TLSOptions) error {
+	if !tlsOpts.Enabled() {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		host = cfg.Addr
+	}
+	cfg.TLS, err = tlsOpts.config(host)
+
+	return err
+}
+
+// openMySQL 通过 Connector 传入 tls.Config，不需要在驱动中注册全局的 TLS 配置
+func openMySQL(cfg *mysqldriver.Config) (*sql.DB, error) {
+	connector, err := mysqldriver.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.OpenDB(connector), nil
+}
@@ -0,0 +1,411 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+const (
+	defaultMySQLConnectTimeout = 10 * time.Second
+	defaultMySQLMinBackoff     = 500 * time.Millisecond
+	defaultMySQLMaxBackoff     = 10 * time.Second
+)
+
+// MySQLOption NewMySQLClientWith 的配置选项
+type MySQLOption func(*mysqlClientOptions)
+
+type mysqlClientOptions struct {
+	dsn      string
+	host     string
+	username string
+	password string
+	database string
+
+	tls            TLSOptions
+	params         map[string]string
+	loc            *time.Location
+	connectTimeout time.Duration
+	replicas       []string
+
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	maxIdleConnections    int
+	maxOpenConnections    int
+	maxConnectionLifeTime time.Duration
+	maxConnectionIdleTime time.Duration
+
+	logLevel      int
+	slowThreshold time.Duration
+	logger        logger.Interface
+
+	closeHooks []func() error
+}
+
+// WithDSN 使用 DSN 连接主库，忽略 WithAddress、WithCredentials、WithDatabase
+func WithDSN(dsn string) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.dsn = dsn
+	}
+}
+
+// WithAddress 设置主库地址，格式为 host:port
+func WithAddress(host string) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.host = host
+	}
+}
+
+// WithCredentials 设置连接主库的用户名和密码
+func WithCredentials(username, password string) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.username = username
+		o.password = password
+	}
+}
+
+// WithDatabase 设置数据库名
+func WithDatabase(name string) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.database = name
+	}
+}
+
+// WithConnectTimeout 设置建立连接的超时时间，ctx 没有截止时间时 Ping 也使用该超时，0 表示不限制
+func WithConnectTimeout(timeout time.Duration) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.connectTimeout = timeout
+	}
+}
+
+// WithRetry 设置启动时连接失败的最大重试次数及指数退避的最小、最大间隔，maxRetries 为 0 表示不重试
+func WithRetry(maxRetries int, minBackoff, maxBackoff time.Duration) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.maxRetries = maxRetries
+		o.minBackoff = minBackoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithTLS 设置主库和只读副本的 TLS 配置，tlsOpts.CAFile 为验证服务端证书的自定义 CA
+func WithTLS(tlsOpts TLSOptions) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.tls = tlsOpts
+	}
+}
+
+// WithReplicas 设置只读副本的 DSN，配置后查询由副本处理，写操作和事务仍由主库处理
+func WithReplicas(dsns ...string) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.replicas = append(o.replicas, dsns...)
+	}
+}
+
+// WithParams 设置 DSN 参数，可以多次调用。驱动不识别的参数会作为会话变量在连接时设置，
+// 例如 {"time_zone": "'+00:00'"} 设置会话时区
+func WithParams(params map[string]string) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		if o.params == nil {
+			o.params = make(map[string]string, len(params))
+		}
+		maps.Copy(o.params, params)
+	}
+}
+
+// WithLocation 设置解析 DATETIME、TIMESTAMP 使用的时区，等同于 DSN 中的 loc 参数
+func WithLocation(loc *time.Location) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.loc = loc
+	}
+}
+
+// WithPool 设置主库和每个只读副本的连接池
+func WithPool(maxIdle, maxOpen int, maxLifeTime, maxIdleTime time.Duration) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.maxIdleConnections = maxIdle
+		o.maxOpenConnections = maxOpen
+		o.maxConnectionLifeTime = maxLifeTime
+		o.maxConnectionIdleTime = maxIdleTime
+	}
+}
+
+// WithLogLevel 设置 GORM 日志级别(1: silent、2: error、3: warn、4: info)及慢查询阈值
+func WithLogLevel(level int, slowThreshold time.Duration) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.logLevel = level
+		o.slowThreshold = slowThreshold
+	}
+}
+
+// WithLogger 设置 GORM 日志记录器，设置后忽略 WithLogLevel
+func WithLogger(l logger.Interface) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.logger = l
+	}
+}
+
+// WithCloseHook 添加 Close 时执行的函数，按添加顺序在关闭连接之前执行
+func WithCloseHook(fn func() error) MySQLOption {
+	return func(o *mysqlClientOptions) {
+		o.closeHooks = append(o.closeHooks, fn)
+	}
+}
+
+// MySQLClient NewMySQLClientWith 创建的 MySQL 客户端，可以被多个 goroutine 同时使用
+type MySQLClient struct {
+	*gorm.DB
+
+	primary  *sql.DB
+	replicas []mysqlReplica
+	timeout  time.Duration
+	hooks    []func() error
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+type mysqlReplica struct {
+	addr string
+	db   *sql.DB
+}
+
+// NewMySQLClientWith 通过 Options 模式创建 MySQL 客户端。连接主库或只读副本失败时按 WithRetry 重试，
+// 配置错误不重试。WithTLS、WithConnectTimeout、WithParams、WithLocation 同时作用于主库和只读副本，
+// 覆盖 DSN 中的同名设置
+func NewMySQLClientWith(opts ...MySQLOption) (*MySQLClient, error) {
+	return NewMySQLClientWithContext(context.Background(), opts...)
+}
+
+// NewMySQLClientWithContext 与 NewMySQLClientWith 相同，ctx 取消后停止重试并返回 ctx 的错误，
+// 用于启动过程中收到退出信号时不再等待数据库
+func NewMySQLClientWithContext(ctx context.Context, opts ...MySQLOption) (*MySQLClient, error) {
+	o := &mysqlClientOptions{
+		host:                  "127.0.0.1:3306",
+		connectTimeout:        defaultMySQLConnectTimeout,
+		minBackoff:            defaultMySQLMinBackoff,
+		maxBackoff:            defaultMySQLMaxBackoff,
+		maxIdleConnections:    100,
+		maxOpenConnections:    100,
+		maxConnectionLifeTime: 10 * time.Second,
+		maxConnectionIdleTime: 24 * time.Minute,
+		logLevel:              int(logger.Silent),
+		slowThreshold:         DefaultSlowThreshold,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	primary, err := o.config(o.dsn)
+	if err != nil {
+		return nil, err
+	}
+	replicas := make([]*mysqldriver.Config, 0, len(o.replicas))
+	for _, dsn := range o.replicas {
+		if dsn == "" {
+			return nil, fmt.Errorf("mysql replica dsn cannot be empty")
+		}
+		cfg, err := o.config(dsn)
+		if err != nil {
+			return nil, err
+		}
+		replicas = append(replicas, cfg)
+	}
+
+	for attempt := 0; ; attempt++ {
+		c, err := o.open(primary, replicas)
+		if err == nil {
+			return c, nil
+		}
+		if attempt >= o.maxRetries {
+			return nil, fmt.Errorf("connect to mysql %s: %w", primary.Addr, err)
+		}
+		d := o.backoff(attempt)
+		log.Warnf("failed to connect to mysql %s, retry in %s: %s", primary.Addr, d, err.Error())
+		if err := sleep(ctx, d); err != nil {
+			return nil, fmt.Errorf("connect to mysql %s: %w", primary.Addr, err)
+		}
+	}
+}
+
+// sleep 等待 d 或者 ctx 取消，ctx 取消时返回 ctx 的错误
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// config 生成主库或只读副本的驱动配置，dsn 为空时由结构化字段生成
+func (o *mysqlClientOptions) config(dsn string) (*mysqldriver.Config, error) {
+	cfg, err := mysqlConfig(&Options{
+		DSN:      dsn,
+		Host:     o.host,
+		UserName: o.username,
+		Password: o.password,
+		Database: o.database,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if o.connectTimeout > 0 {
+		cfg.Timeout = o.connectTimeout
+	}
+	if o.loc != nil {
+		cfg.Loc = o.loc
+	}
+	if len(o.params) > 0 {
+		if cfg.Params == nil {
+			cfg.Params = make(map[string]string, len(o.params))
+		}
+		maps.Copy(cfg.Params, o.params)
+	}
+	if err := mysqlTLS(cfg, o.tls); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// open 连接主库和只读副本，失败时关闭已经打开的连接池
+func (o *mysqlClientOptions) open(primary *mysqldriver.Config, replicas []*mysqldriver.Config) (*MySQLClient, error) {
+	c := &MySQLClient{timeout: o.connectTimeout, hooks: o.closeHooks}
+	if err := c.connect(o, primary, replicas); err != nil {
+		_ = c.closePools()
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *MySQLClient) connect(o *mysqlClientOptions, primary *mysqldriver.Config, replicas []*mysqldriver.Config) error {
+	var err error
+	if c.primary, err = openMySQL(primary); err != nil {
+		return err
+	}
+	l := o.logger
+	if l == nil {
+		l = NewLogger(logger.LogLevel(o.logLevel), o.slowThreshold)
+	}
+	// gorm.Open 会 Ping 主库，dbresolver 注册时会 Ping 每个只读副本
+	c.DB, err = gorm.Open(mysql.New(mysql.Config{Conn: c.primary, DSNConfig: primary}), &gorm.Config{Logger: l, TranslateError: true})
+	if err != nil {
+		return err
+	}
+	if err := c.DB.Use(TracingPlugin{}); err != nil {
+		return err
+	}
+
+	if len(replicas) > 0 {
+		dialectors := make([]gorm.Dialector, 0, len(replicas))
+		for _, cfg := range replicas {
+			sqlDB, err := openMySQL(cfg)
+			if err != nil {
+				return err
+			}
+			c.replicas = append(c.replicas, mysqlReplica{addr: cfg.Addr, db: sqlDB})
+			dialectors = append(dialectors, mysql.New(mysql.Config{Conn: sqlDB, DSNConfig: cfg}))
+		}
+		if err := c.DB.Use(dbresolver.Register(dbresolver.Config{Replicas: dialectors})); err != nil {
+			return fmt.Errorf("connect to mysql replicas: %w", err)
+		}
+	}
+
+	for _, sqlDB := range c.pools() {
+		sqlDB.SetMaxOpenConns(o.maxOpenConnections)
+		sqlDB.SetMaxIdleConns(o.maxIdleConnections)
+		sqlDB.SetConnMaxLifetime(o.maxConnectionLifeTime)
+		sqlDB.SetConnMaxIdleTime(o.maxConnectionIdleTime)
+	}
+
+	return nil
+}
+
+// backoff 返回第 attempt 次重试前的等待时间，指数增长并带有随机抖动
+func (o *mysqlClientOptions) backoff(attempt int) time.Duration {
+	d := o.minBackoff << attempt
+	if d <= 0 || d > o.maxBackoff {
+		d = o.maxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	//nolint:gosec // 抖动不需要安全的随机数
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// Ping 检测主库和所有只读副本是否可用，可以用于就绪检查。ctx 没有截止时间时使用连接超时
+func (c *MySQLClient) Ping(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	if err := c.primary.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping mysql primary: %w", err)
+	}
+	for _, r := range c.replicas {
+		if err := r.db.PingContext(ctx); err != nil {
+			return fmt.Errorf("ping mysql replica %s: %w", r.addr, err)
+		}
+	}
+
+	return nil
+}
+
+// Close 执行 WithCloseHook 添加的函数后关闭主库和只读副本的连接池，用于优雅关闭。
+// 重复调用时返回第一次调用的结果
+func (c *MySQLClient) Close() error {
+	c.closeOnce.Do(func() {
+		errs := make([]error, 0, len(c.hooks)+1)
+		for _, hook := range c.hooks {
+			errs = append(errs, hook())
+		}
+		errs = append(errs, c.closePools())
+		c.closeErr = errors.Join(errs...)
+	})
+
+	return c.closeErr
+}
+
+func (c *MySQLClient) pools() []*sql.DB {
+	pools := make([]*sql.DB, 0, len(c.replicas)+1)
+	if c.primary != nil {
+		pools = append(pools, c.primary)
+	}
+	for _, r := range c.replicas {
+		pools = append(pools, r.db)
+	}
+
+	return pools
+}
+
+func (c *MySQLClient) closePools() error {
+	var errs []error
+	for _, sqlDB := range c.pools() {
+		errs = append(errs, sqlDB.Close())
+	}
+
+	return errors.Join(errs...)
+}
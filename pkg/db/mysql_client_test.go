@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMySQLClientOptions_Config(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	o := &mysqlClientOptions{}
+	for _, opt := range []MySQLOption{
+		WithAddress("db:3306"),
+		WithCredentials("iam", "secret"),
+		WithDatabase("iam"),
+		WithConnectTimeout(time.Second),
+		WithLocation(loc),
+		WithParams(map[string]string{"time_zone": "'+08:00'"}),
+		WithTLS(TLSOptions{Mode: TLSRequire}),
+	} {
+		opt(o)
+	}
+
+	cfg, err := o.config("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != "db:3306" || cfg.User != "iam" || cfg.DBName != "iam" {
+		t.Errorf("config() = %s@%s/%s", cfg.User, cfg.Addr, cfg.DBName)
+	}
+	if cfg.Timeout != time.Second || cfg.Loc != loc || cfg.TLS == nil {
+		t.Errorf("config() timeout = %s, loc = %s, tls = %v", cfg.Timeout, cfg.Loc, cfg.TLS)
+	}
+	if cfg.Params["charset"] != "utf8mb4" || cfg.Params["time_zone"] != "'+08:00'" {
+		t.Errorf("config() params = %v", cfg.Params)
+	}
+
+	// 选项覆盖副本 DSN 中的同名设置
+	cfg, err = o.config("ro:pass@tcp(replica:3306)/iam?loc=UTC&timeout=30s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != "replica:3306" || cfg.User != "ro" || cfg.Timeout != time.Second || cfg.Loc != loc {
+		t.Errorf("config(replica) = %s@%s timeout = %s, loc = %s", cfg.User, cfg.Addr, cfg.Timeout, cfg.Loc)
+	}
+}
+
+func TestNewMySQLClientWith_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []MySQLOption
+	}{
+		{"invalid dsn", []MySQLOption{WithDSN("not a dsn")}},
+		{"empty replica", []MySQLOption{WithReplicas("")}},
+		{"missing ca", []MySQLOption{WithTLS(TLSOptions{Mode: TLSVerifyCA, CAFile: "/nonexistent"})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMySQLClientWith(tt.opts...); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestNewMySQLClientWith_Retry(t *testing.T) {
+	start := time.Now()
+	_, err := NewMySQLClientWith(
+		WithAddress("127.0.0.1:1"),
+		WithConnectTimeout(time.Second),
+		WithRetry(2, 10*time.Millisecond, 20*time.Millisecond),
+	)
+	if err == nil {
+		t.Fatal("expected an error connecting to a closed port")
+	}
+	// 两次重试至少等待 5ms + 10ms
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("NewMySQLClientWith() returned after %s, want retries with backoff", elapsed)
+	}
+}
+
+func TestNewMySQLClientWithContext_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewMySQLClientWithContext(ctx,
+		WithAddress("127.0.0.1:1"),
+		WithConnectTimeout(time.Second),
+		WithRetry(10, time.Minute, time.Minute),
+	)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("NewMySQLClientWithContext() = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("NewMySQLClientWithContext() returned after %s, want the backoff interrupted", elapsed)
+	}
+}
+
+func TestMySQLClient_PingClose(t *testing.T) {
+	gdb, err := NewClient(&Options{Driver: DriverSQLite, Database: ":memory:", LogLevel: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := gdb.DB()
+
+	var calls []string
+	c := &MySQLClient{DB: gdb, primary: sqlDB, hooks: []func() error{
+		func() error { calls = append(calls, "first"); return nil },
+		func() error { calls = append(calls, "second"); return errors.New("hook failed") },
+	}}
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() = %v", err)
+	}
+
+	if err := c.Close(); err == nil {
+		t.Error("Close() should return the hook error")
+	}
+	if err := c.Close(); err == nil || len(calls) != 2 || calls[0] != "first" {
+		t.Errorf("second Close() = %v, hooks called %v, want hooks called once in order", err, calls)
+	}
+	if err := c.Ping(context.Background()); err == nil {
+		t.Error("Ping() after Close should fail")
+	}
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// postgresDSN 由结构化字段生成 PostgreSQL 连接 URL，TLS 由 TLSOptions 单独设置
+func postgresDSN(opts *Options) string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(opts.UserName, opts.Password),
+		Host:     opts.Host,
+		Path:     "/" + opts.Database,
+		RawQuery: "sslmode=disable",
+	}
+
+	return u.String()
+}
+
+func postgresDialector(opts *Options) (gorm.Dialector, error) {
+	dsn := opts.DSN
+	if dsn == "" {
+		dsn = postgresDSN(opts)
+	}
+	cfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		// pgx 的错误中包含隐藏了密码的 DSN
+		return nil, fmt.Errorf("parse postgres dsn: %w", err)
+	}
+	if opts.TLS.Enabled() {
+		if cfg.TLSConfig, err = opts.TLS.config(cfg.Host); err != nil {
+			return nil, err
+		}
+		cfg.Fallbacks = nil
+	}
+
+	return postgres.New(postgres.Config{Conn: stdlib.OpenDB(*cfg)}), nil
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteDSN 返回 SQLite 的 DSN，没有设置 DSN 时使用 Database 作为数据库文件路径
+func sqliteDSN(opts *Options) string {
+	if opts.DSN != "" {
+		return opts.DSN
+	}
+
+	return opts.Database
+}
+
+// isSQLiteMemory 判断是否为内存数据库，如 ":memory:"、"file::memory:?cache=shared"、"file:test?mode=memory"
+func isSQLiteMemory(opts *Options) bool {
+	dsn := sqliteDSN(opts)
+
+	return strings.Contains(dsn, ":memory:") || strings.Contains(dsn, "mode=memory")
+}
+
+func sqliteDialector(opts *Options) (gorm.Dialector, error) {
+	if opts.TLS.Enabled() {
+		return nil, errors.New("sqlite does not support TLS")
+	}
+	dsn := sqliteDSN(opts)
+	if dsn == "" {
+		return nil, errors.New("sqlite database file is required")
+	}
+
+	return sqlite.Open(dsn), nil
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLS 模式，与 PostgreSQL 的 sslmode 含义相同
+const (
+	// TLSDisable 不使用 TLS
+	TLSDisable = "disable"
+	// TLSRequire 使用 TLS 但不校验服务端证书
+	TLSRequire = "require"
+	// TLSVerifyCA 校验服务端证书由 CA 签发，不校验主机名
+	TLSVerifyCA = "verify-ca"
+	// TLSVerifyFull 校验服务端证书由 CA 签发且与主机名匹配
+	TLSVerifyFull = "verify-full"
+)
+
+// TLSModes 返回支持的 TLS 模式
+func TLSModes() []string {
+	return []string{TLSDisable, TLSRequire, TLSVerifyCA, TLSVerifyFull}
+}
+
+// TLSOptions 数据库连接的 TLS 配置
+type TLSOptions struct {
+	// Mode TLS 模式，为空时与 TLSDisable 相同
+	Mode string
+	// CAFile 校验服务端证书的 CA 证书，为空时使用系统的 CA
+	CAFile string
+	// CertFile、KeyFile 客户端证书及私钥，服务端要求客户端证书时设置
+	CertFile string
+	KeyFile  string
+	// ServerName 校验证书的主机名，为空时使用连接地址中的主机名
+	ServerName string
+}
+
+// Enabled 返回是否使用 TLS
+func (o TLSOptions) Enabled() bool {
+	return o.Mode != "" && o.Mode != TLSDisable
+}
+
+// config 创建连接 host 的 tls.Config，不使用 TLS 时返回 nil
+func (o TLSOptions) config(host string) (*tls.Config, error) {
+	if !o.Enabled() {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: o.ServerName}
+	if cfg.ServerName == "" {
+		cfg.ServerName = host
+	}
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load database client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read database CA file: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in database CA file %s", o.CAFile)
+		}
+	}
+
+	switch o.Mode {
+	case TLSRequire:
+		cfg.InsecureSkipVerify = true
+	case TLSVerifyCA:
+		// 跳过默认的校验，只校验证书链
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = verifyChain(cfg.RootCAs)
+	case TLSVerifyFull:
+	default:
+		return nil, fmt.Errorf("unsupported database TLS mode %q, must be one of %v", o.Mode, TLSModes())
+	}
+
+	return cfg, nil
+}
+
+// verifyChain 返回只校验服务端证书链、不校验主机名的 tls.Config.VerifyConnection，roots 为空时使用系统的 CA
+func verifyChain(roots *x509.CertPool) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("database server did not present a certificate")
+		}
+		opts := x509.VerifyOptions{Roots: roots, Intermediates: x509.NewCertPool()}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(opts)
+
+		return err
+	}
+}
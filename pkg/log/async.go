@@ -0,0 +1,170 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultQueueSize 异步写入的默认队列长度，单位为条
+const DefaultQueueSize = 8192
+
+// maxBatchSize 异步写入时合并为一次写入的最大字节数
+const maxBatchSize = 64 << 10
+
+// AsyncWriter 异步写入的 zapcore.WriteSyncer。日志先进入有界队列，由后台 goroutine 合并后写入 ws，
+// 队列已满时丢弃日志并计数，不阻塞调用方。Sync 等待调用前写入的日志全部写入 ws 后同步 ws
+type AsyncWriter struct {
+	ws    zapcore.WriteSyncer
+	queue chan []byte
+	flush chan chan error
+	done  chan struct{}
+	// stopped 后台 goroutine 退出后关闭
+	stopped chan struct{}
+
+	// mu 保护对 ws 的写入，关闭后调用方直接写入 ws
+	mu      sync.Mutex
+	closed  atomic.Bool
+	dropped atomic.Uint64
+	once    sync.Once
+}
+
+var _ zapcore.WriteSyncer = (*AsyncWriter)(nil)
+
+// NewAsyncWriter 创建异步写入器，size 为队列长度，小于等于 0 时使用 DefaultQueueSize
+func NewAsyncWriter(ws zapcore.WriteSyncer, size int) *AsyncWriter {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	w := &AsyncWriter{
+		ws:      ws,
+		queue:   make(chan []byte, size),
+		flush:   make(chan chan error),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.run()
+
+	return w
+}
+
+// Write 实现 io.Writer，p 会被复制，队列已满时丢弃
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	if w.closed.Load() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		return w.ws.Write(p)
+	}
+
+	b := make([]byte, len(p))
+	copy(b, p)
+	select {
+	case w.queue <- b:
+	default:
+		w.dropped.Add(1)
+		drops.queueFull.Add(1)
+	}
+
+	return len(p), nil
+}
+
+// Sync 等待已进入队列的日志全部写入后同步底层的 WriteSyncer
+func (w *AsyncWriter) Sync() error {
+	if w.closed.Load() {
+		return w.ws.Sync()
+	}
+
+	errc := make(chan error, 1)
+	select {
+	case w.flush <- errc:
+		return <-errc
+	case <-w.done:
+		return w.ws.Sync()
+	}
+}
+
+// Close 停止后台 goroutine 并写入队列中的日志，之后的日志同步写入
+func (w *AsyncWriter) Close() error {
+	w.once.Do(func() {
+		w.closed.Store(true)
+		close(w.done)
+	})
+	<-w.stopped
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for {
+		select {
+		case b := <-w.queue:
+			_, _ = w.ws.Write(b)
+		default:
+			return w.ws.Sync()
+		}
+	}
+}
+
+// Dropped 返回因队列已满丢弃的日志条数
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.stopped)
+
+	buf := make([]byte, 0, maxBatchSize)
+	for {
+		select {
+		case b := <-w.queue:
+			buf = w.write(append(buf[:0], b...))
+		case errc := <-w.flush:
+			// 写入 Sync 调用前进入队列的日志
+			for len(w.queue) > 0 {
+				buf = w.write(buf[:0])
+			}
+			errc <- w.ws.Sync()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// write 合并队列中已有的日志后写入，返回可复用的缓冲区
+func (w *AsyncWriter) write(buf []byte) []byte {
+batch:
+	for len(buf) < maxBatchSize {
+		select {
+		case b := <-w.queue:
+			buf = append(buf, b...)
+		default:
+			break batch
+		}
+	}
+	if len(buf) == 0 {
+		return buf
+	}
+	w.mu.Lock()
+	_, _ = w.ws.Write(buf)
+	w.mu.Unlock()
+
+	return buf
+}
+
+// Drops 进程内所有日志记录器丢弃的日志条数
+type Drops struct {
+	// Sampled 因采样丢弃的条数
+	Sampled uint64
+	// QueueFull 因异步写入队列已满丢弃的条数
+	QueueFull uint64
+}
+
+var drops struct {
+	sampled   atomic.Uint64
+	queueFull atomic.Uint64
+}
+
+// DroppedLogs 返回进程内所有日志记录器丢弃的日志条数
+func DroppedLogs() Drops {
+	return Drops{Sampled: drops.sampled.Load(), QueueFull: drops.queueFull.Load()}
+}
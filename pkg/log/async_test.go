@@ -0,0 +1,175 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// blockingWriter 在 release 关闭前阻塞写入
+type blockingWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) Sync() error { return nil }
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.String()
+}
+
+func TestAsyncWriter(t *testing.T) {
+	ws := &blockingWriter{release: make(chan struct{})}
+	w := NewAsyncWriter(ws, 2)
+
+	// 后台 goroutine 阻塞在第一次写入时，队列中最多再容纳 2 条
+	for i := 0; i < 10; i++ {
+		_, _ = w.Write([]byte("line\n"))
+	}
+	if w.Dropped() == 0 {
+		t.Error("expected logs to be dropped when the queue is full")
+	}
+	close(ws.release)
+	if err := w.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(ws.String(), "line\n"); uint64(got)+w.Dropped() != 10 {
+		t.Errorf("written %d, dropped %d, want 10 in total", got, w.Dropped())
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("after close\n"))
+	if !strings.Contains(ws.String(), "after close") {
+		t.Error("expected synchronous write after Close")
+	}
+}
+
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, DebugLevel, WithSampling(map[Level]SamplingOptions{InfoLevel: {Initial: 2, Thereafter: 3}}))
+
+	before := DroppedLogs().Sampled
+	for i := 0; i < 8; i++ {
+		l.Info("repeated")
+		l.Warn("not sampled")
+	}
+
+	// 第 1、2 条以及之后每 3 条中的第 1 条：1、2、5、8
+	got := buf.String()
+	if n := strings.Count(got, `"repeated"`); n != 4 {
+		t.Errorf("got %d sampled info logs, want 4", n)
+	}
+	if n := strings.Count(got, `"not sampled"`); n != 8 {
+		t.Errorf("got %d warn logs, want 8", n)
+	}
+	if dropped := DroppedLogs().Sampled - before; dropped != 4 {
+		t.Errorf("dropped %d, want 4", dropped)
+	}
+}
+
+func TestOptionsAsync(t *testing.T) {
+	path := t.TempDir() + "/async.log"
+	opts := NewOptions()
+	opts.Outputs = []OutputOptions{{Path: path}}
+	opts.Async.Enabled = true
+	opts.Sampling = map[string]SamplingOptions{"info": {Initial: 1}}
+	l, err := opts.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.closeWriters()
+
+	l.Info("first")
+	l.Info("first")
+	l.Error("error")
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); strings.Count(got, `"first"`) != 1 || !strings.Contains(got, `"error"`) {
+		t.Errorf("unexpected log:\n%s", got)
+	}
+}
+
+func benchmarkLogger(b *testing.B, opts ...Option) {
+	benchmarkWriter(b, zapcore.AddSync(io.Discard), opts...)
+}
+
+func benchmarkWriter(b *testing.B, ws zapcore.WriteSyncer, opts ...Option) {
+	l := New(ws, InfoLevel, opts...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Info("request handled", String("path", "/v1/users"), Int("status", 200))
+		}
+	})
+	b.StopTimer()
+	_ = l.Flush()
+}
+
+// slowWriter 模拟写入较慢的磁盘
+type slowWriter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// 每次写入做少量计算，模拟系统调用的开销
+	for i := 0; i < 2000; i++ {
+		w.n += i
+	}
+
+	return len(p), nil
+}
+
+func (w *slowWriter) Sync() error { return nil }
+
+func BenchmarkSync(b *testing.B) {
+	benchmarkLogger(b)
+}
+
+func BenchmarkSampled(b *testing.B) {
+	benchmarkLogger(b, WithSampling(map[Level]SamplingOptions{InfoLevel: {Initial: 100, Thereafter: 100}}))
+}
+
+func BenchmarkAsync(b *testing.B) {
+	w := NewAsyncWriter(zapcore.AddSync(io.Discard), DefaultQueueSize)
+	defer w.Close()
+	benchmarkWriter(b, w)
+}
+
+func BenchmarkSlowWriterSync(b *testing.B) {
+	benchmarkWriter(b, &slowWriter{})
+}
+
+func BenchmarkSlowWriterAsync(b *testing.B) {
+	w := NewAsyncWriter(&slowWriter{}, DefaultQueueSize)
+	defer w.Close()
+	benchmarkWriter(b, w)
+}
@@ -0,0 +1,54 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"github.com/ahang7/go-IAM/pkg/log/logtest"
+)
+
+func TestConformance(t *testing.T) {
+	t.Run("zap", func(t *testing.T) {
+		logtest.RunConformance(t, func(t *testing.T, level log.Level) logtest.Subject {
+			var buf bytes.Buffer
+
+			return logtest.Subject{
+				Logger: log.New(&buf, level),
+				Entries: func() []logtest.Entry {
+					entries, err := logtest.ParseJSON(buf.Bytes())
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					return entries
+				},
+			}
+		})
+	})
+
+	t.Run("observer", func(t *testing.T) {
+		logtest.RunConformance(t, func(_ *testing.T, level log.Level) logtest.Subject {
+			l, logs := log.NewObserver(level)
+
+			return logtest.Subject{Logger: l, Entries: func() []logtest.Entry { return logtest.FromObserved(logs) }}
+		})
+	})
+
+	t.Run("noop", func(t *testing.T) {
+		logtest.RunConformance(t, func(*testing.T, log.Level) logtest.Subject {
+			return logtest.Subject{Logger: log.NewNop()}
+		})
+	})
+}
+
+func TestObserve(t *testing.T) {
+	logs := logtest.Observe(t, log.DebugLevel)
+
+	log.Debugw("observed", "password", "s3cret")
+
+	entries := logs.FilterMessage("observed").All()
+	if len(entries) != 1 || entries[0].ContextMap()["password"] != log.Redacted {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+}
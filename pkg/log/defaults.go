@@ -0,0 +1,32 @@
+package log
+
+import (
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/grpclog"
+)
+
+// defaultsInstalled 是否已经通过 InstallDefaults 安装为进程默认的日志记录器
+var defaultsInstalled atomic.Bool
+
+// InstallDefaults 将默认日志记录器安装为进程内其他日志库的默认输出，使第三方库的日志与 IAM 的日志
+// 使用相同的编码器、级别及敏感信息隐藏：
+//   - slog.Default 及标准库 log，标准库 log 以 info 级别输出
+//   - gRPC 内部日志 grpclog，名称为 grpc
+//   - OpenTelemetry 内部日志，名称为 otel
+//
+// 使用 logr 的库可以通过 logr.New(NewLogSink(Default())) 接入，如 klog.SetLogger。
+// 安装后通过 Init 替换默认日志记录器时会重新安装。grpclog 不是并发安全的，需要在使用 gRPC 之前调用
+func InstallDefaults() {
+	defaultsInstalled.Store(true)
+	installDefaults(std)
+}
+
+func installDefaults(l *zapLogger) {
+	slog.SetDefault(slog.New(NewSlogHandler(l)))
+	grpclog.SetLoggerV2(NewGRPCLogger(l))
+	otel.SetLogger(logr.New(NewLogSink(l)).WithName("otel"))
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/grpclog"
+)
+
+// grpcLogger 使用 zapLogger 输出 gRPC 内部日志的 grpclog.LoggerV2。
+// gRPC 的 info 日志主要是连接状态变化，数量较多，以 debug 级别输出
+type grpcLogger struct {
+	s *zap.SugaredLogger
+	l *zapLogger
+}
+
+var _ grpclog.LoggerV2 = (*grpcLogger)(nil)
+
+// NewGRPCLogger 返回使用 l 输出日志的 grpclog.LoggerV2，日志记录器名称为 grpc。
+// gRPC 日志经过 grpclog 的多层调用，不输出 caller
+func NewGRPCLogger(l *zapLogger) grpclog.LoggerV2 {
+	gl := l.with(l.zapL.Named("grpc").WithOptions(zap.WithCaller(false)))
+
+	return &grpcLogger{s: gl.zapL.Sugar(), l: gl}
+}
+
+func (g *grpcLogger) Info(args ...any)                    { g.s.Debug(args...) }
+func (g *grpcLogger) Infoln(args ...any)                  { g.s.Debug(sprintln(args)) }
+func (g *grpcLogger) Infof(format string, args ...any)    { g.s.Debugf(format, args...) }
+func (g *grpcLogger) Warning(args ...any)                 { g.s.Warn(args...) }
+func (g *grpcLogger) Warningln(args ...any)               { g.s.Warn(sprintln(args)) }
+func (g *grpcLogger) Warningf(format string, args ...any) { g.s.Warnf(format, args...) }
+func (g *grpcLogger) Error(args ...any)                   { g.s.Error(args...) }
+func (g *grpcLogger) Errorln(args ...any)                 { g.s.Error(sprintln(args)) }
+func (g *grpcLogger) Errorf(format string, args ...any)   { g.s.Errorf(format, args...) }
+func (g *grpcLogger) Fatal(args ...any)                   { g.s.Fatal(args...) }
+func (g *grpcLogger) Fatalln(args ...any)                 { g.s.Fatal(sprintln(args)) }
+func (g *grpcLogger) Fatalf(format string, args ...any)   { g.s.Fatalf(format, args...) }
+
+// V 实现 grpclog.LoggerV2，输出 debug 日志时输出所有 verbosity 的日志
+func (g *grpcLogger) V(_ int) bool {
+	return g.l.enabled(DebugLevel)
+}
+
+// sprintln 与 fmt.Sprintln 相同，去掉末尾的换行符
+func sprintln(args []any) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
@@ -0,0 +1,279 @@
+package log
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelSetting 日志记录器的级别，Name 为空表示全局级别
+type LevelSetting struct {
+	Name  string `json:"name,omitempty"`
+	Level Level  `json:"level"`
+	// ExpiresAt 临时级别恢复为原级别的时间，永久生效时为空
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// levels 日志记录器的动态级别，包括全局级别以及按 WithName 名称覆盖的级别，
+// 同一个日志记录器派生出的日志记录器共享同一个 levels
+type levels struct {
+	root zap.AtomicLevel
+
+	mu    sync.RWMutex
+	named map[string]Level
+	temps map[string]*tempLevel
+	// floor 按名称覆盖的级别中的最低级别，没有覆盖时为 InvalidLevel
+	floor atomic.Int32
+}
+
+// tempLevel 一次临时的级别修改，到期后恢复为修改之前的级别
+type tempLevel struct {
+	timer     *time.Timer
+	expiresAt time.Time
+	prev      Level
+	// prevSet 修改之前该名称是否有覆盖的级别，没有时到期后删除覆盖
+	prevSet bool
+}
+
+func newLevels(root Level) *levels {
+	l := &levels{
+		root:  zap.NewAtomicLevelAt(root),
+		named: map[string]Level{},
+		temps: map[string]*tempLevel{},
+	}
+	l.floor.Store(int32(zapcore.InvalidLevel))
+
+	return l
+}
+
+// enabled 返回名称为 name 的日志记录器是否输出 level 级别的日志，
+// 使用名称相同或以 "覆盖名称." 开头的最长覆盖名称的级别，没有匹配时使用全局级别
+func (l *levels) enabled(name string, level Level) bool {
+	if Level(l.floor.Load()) == zapcore.InvalidLevel {
+		return l.root.Enabled(level)
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	matched, threshold := -1, l.root.Level()
+	for n, lv := range l.named {
+		if len(n) > matched && (name == n || strings.HasPrefix(name, n+".")) {
+			matched, threshold = len(n), lv
+		}
+	}
+
+	return level >= threshold
+}
+
+// min 返回任意日志记录器可能输出的最低级别
+func (l *levels) min() Level {
+	root := l.root.Level()
+	if floor := Level(l.floor.Load()); floor != zapcore.InvalidLevel && floor < root {
+		return floor
+	}
+
+	return root
+}
+
+// get 返回 name 当前的级别，ok 表示按名称覆盖的级别是否存在
+func (l *levels) get(name string) (level Level, ok bool) {
+	if name == "" {
+		return l.root.Level(), true
+	}
+	level, ok = l.named[name]
+
+	return level, ok
+}
+
+// store 修改 name 的级别，调用方需要持有写锁
+func (l *levels) store(name string, level Level, ok bool) {
+	switch {
+	case name == "":
+		l.root.SetLevel(level)
+
+		return
+	case ok:
+		l.named[name] = level
+	default:
+		delete(l.named, name)
+	}
+	l.updateFloor()
+}
+
+// updateFloor 重新计算 floor，调用方需要持有写锁
+func (l *levels) updateFloor() {
+	floor := zapcore.InvalidLevel
+	for _, lv := range l.named {
+		if floor == zapcore.InvalidLevel || lv < floor {
+			floor = lv
+		}
+	}
+	l.floor.Store(int32(floor))
+}
+
+// cancel 取消 name 的临时级别，返回被取消的临时级别
+func (l *levels) cancel(name string) *tempLevel {
+	t, ok := l.temps[name]
+	if ok {
+		t.timer.Stop()
+		delete(l.temps, name)
+	}
+
+	return t
+}
+
+// set 修改 name 的级别，ttl 大于 0 时在 ttl 之后恢复为第一次临时修改之前的级别
+func (l *levels) set(name string, level Level, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if ttl <= 0 {
+		l.cancel(name)
+		l.store(name, level, true)
+
+		return
+	}
+
+	// 每次修改都使用新的 tempLevel，已经触发、正在等待锁的 expire 持有旧的 tempLevel，不会恢复本次修改
+	t := &tempLevel{}
+	if old := l.cancel(name); old != nil {
+		t.prev, t.prevSet = old.prev, old.prevSet
+	} else {
+		t.prev, t.prevSet = l.get(name)
+	}
+	t.expiresAt = time.Now().Add(ttl)
+	t.timer = time.AfterFunc(ttl, func() { l.expire(name, t) })
+	l.temps[name] = t
+	l.store(name, level, true)
+}
+
+// expire 临时级别到期，t 已经被新的修改替换时忽略
+func (l *levels) expire(name string, t *tempLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.temps[name] != t {
+		return
+	}
+	delete(l.temps, name)
+	l.store(name, t.prev, t.prevSet)
+}
+
+// revert 立即恢复 name 的临时级别，没有临时级别时返回 false
+func (l *levels) revert(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t := l.cancel(name)
+	if t == nil {
+		return false
+	}
+	l.store(name, t.prev, t.prevSet)
+
+	return true
+}
+
+// reset 使用配置中的级别替换所有级别，并取消所有临时级别
+func (l *levels) reset(root Level, named map[string]Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for name := range l.temps {
+		l.cancel(name)
+	}
+	for name := range l.named {
+		delete(l.named, name)
+	}
+	for name, level := range named {
+		l.named[name] = level
+	}
+	l.updateFloor()
+	l.root.SetLevel(root)
+}
+
+// settings 返回全局级别及所有按名称覆盖的级别，全局级别在第一个
+func (l *levels) settings() []LevelSetting {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	names := make([]string, 0, len(l.named))
+	for name := range l.named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	settings := make([]LevelSetting, 0, len(names)+1)
+	for _, name := range append([]string{""}, names...) {
+		level, _ := l.get(name)
+		s := LevelSetting{Name: name, Level: level}
+		if t, ok := l.temps[name]; ok {
+			expiresAt := t.expiresAt
+			s.ExpiresAt = &expiresAt
+		}
+		settings = append(settings, s)
+	}
+
+	return settings
+}
+
+// levelCore 按日志记录器名称过滤日志级别，被包装的 core 不再根据全局级别过滤
+type levelCore struct {
+	zapcore.Core
+	levels *levels
+}
+
+func newLevelCore(core zapcore.Core, l *levels) zapcore.Core {
+	return &levelCore{Core: core, levels: l}
+}
+
+func (c *levelCore) Enabled(level Level) bool {
+	return level >= c.levels.min() && c.Core.Enabled(level)
+}
+
+// Level 实现 zapcore.LevelOf 使用的接口
+func (c *levelCore) Level() Level {
+	return c.levels.min()
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return newLevelCore(c.Core.With(fields), c.levels)
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.enabled(ent.LoggerName, ent.Level) {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
+
+// GetLevel 返回默认日志记录器的全局级别
+func GetLevel() Level {
+	return std.levels.root.Level()
+}
+
+// SetLevel 永久修改默认日志记录器的全局级别，并取消全局级别的临时修改
+func SetLevel(level Level) {
+	std.SetLevel(level)
+}
+
+// SetTemporaryLevel 临时修改默认日志记录器中名称为 name 的日志记录器的级别，name 为空时修改全局级别。
+// ttl 之后恢复为第一次临时修改之前的级别，再次修改会重新计时
+func SetTemporaryLevel(name string, level Level, ttl time.Duration) {
+	std.levels.set(name, level, ttl)
+}
+
+// RevertLevel 立即恢复 name 的临时级别，没有临时级别时返回 false
+func RevertLevel(name string) bool {
+	return std.levels.revert(name)
+}
+
+// Levels 返回默认日志记录器的全局级别及所有按名称覆盖的级别，全局级别在第一个
+func Levels() []LevelSetting {
+	return std.levels.settings()
+}
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNamedLevels(t *testing.T) {
+	var buf bytes.Buffer
+	old := Default()
+	ReplaceDefault(New(&buf, InfoLevel))
+	defer ReplaceDefault(old)
+
+	opts := NewOptions()
+	opts.NamedLevels = map[string]string{"authz": "debug", "authz.cache": "error"}
+	if err := opts.ApplyLevels(); err != nil {
+		t.Fatal(err)
+	}
+
+	WithName("authz").Debug("authz debug")
+	WithName("authz").WithName("policy").Debug("policy debug")
+	WithName("authz").WithName("cache").Warn("cache warn")
+	WithName("authzx").Debug("authzx debug")
+	Debug("root debug")
+
+	got := buf.String()
+	for _, msg := range []string{"authz debug", "policy debug"} {
+		if !strings.Contains(got, msg) {
+			t.Errorf("expected %q in:\n%s", msg, got)
+		}
+	}
+	for _, msg := range []string{"cache warn", "authzx debug", "root debug"} {
+		if strings.Contains(got, msg) {
+			t.Errorf("unexpected %q in:\n%s", msg, got)
+		}
+	}
+}
+
+func TestTemporaryLevel(t *testing.T) {
+	old := Default()
+	ReplaceDefault(New(&bytes.Buffer{}, InfoLevel))
+	defer ReplaceDefault(old)
+
+	SetTemporaryLevel("", DebugLevel, time.Hour)
+	// 再次修改重新计时，到期后恢复为第一次修改之前的级别
+	SetTemporaryLevel("", WarnLevel, 50*time.Millisecond)
+	SetTemporaryLevel("store", DebugLevel, 50*time.Millisecond)
+
+	levels := Levels()
+	if len(levels) != 2 || levels[0].Level != WarnLevel || levels[0].ExpiresAt == nil ||
+		levels[1].Name != "store" || levels[1].Level != DebugLevel {
+		t.Fatalf("unexpected levels %+v", levels)
+	}
+	if !WithName("store").V(DebugLevel).Enabled() {
+		t.Error("expected debug to be enabled for store")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if levels := Levels(); len(levels) != 1 || levels[0].Level != InfoLevel || levels[0].ExpiresAt != nil {
+		t.Errorf("expected levels to be reverted, got %+v", levels)
+	}
+
+	SetTemporaryLevel("", DebugLevel, time.Hour)
+	if !RevertLevel("") || GetLevel() != InfoLevel || RevertLevel("") {
+		t.Errorf("expected revert to restore info, got %s", GetLevel())
+	}
+}
+
+func TestTemporaryLevel_StaleExpire(t *testing.T) {
+	l := newLevels(InfoLevel)
+	l.set("store", DebugLevel, time.Hour)
+	stale := l.temps["store"]
+
+	// 模拟已经触发、在再次修改之后才拿到锁的 expire，不能恢复新的修改
+	l.set("store", WarnLevel, time.Hour)
+	l.expire("store", stale)
+	if level, ok := l.get("store"); !ok || level != WarnLevel {
+		t.Errorf("level after a stale expire = %s (%v), want warn", level, ok)
+	}
+
+	// 新的修改到期后仍然恢复为第一次修改之前的状态
+	l.expire("store", l.temps["store"])
+	if _, ok := l.get("store"); ok {
+		t.Error("expected the store level override to be removed")
+	}
+}
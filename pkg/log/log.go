@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -75,31 +77,36 @@ func (n *noopInfoLogger) Enabled() bool { return false }
 
 var disableInfoLogger = &noopInfoLogger{}
 
+// infoLogger 以固定级别输出日志的 InfoLogger，由 zapLogger.V 返回。
+// 每次输出时重新判断级别，SetLevel、SetTemporaryLevel 对已经返回的 infoLogger 同样生效
 type infoLogger struct {
-	l     *zap.Logger
-	level zapcore.Level
+	l     *zapLogger
+	level Level
 }
 
 func (l *infoLogger) Info(msg string, fields ...Field) {
-	if checkedEntry := l.l.Check(l.level, msg); checkedEntry != nil {
+	if checkedEntry := l.l.zapL.Check(l.level, msg); checkedEntry != nil {
 		checkedEntry.Write(fields...)
 	}
 }
 
 func (l *infoLogger) Infof(format string, v ...any) {
-	if checkedEntry := l.l.Check(l.level, fmt.Sprintf(format, v...)); checkedEntry != nil {
+	if !l.Enabled() {
+		return
+	}
+	if checkedEntry := l.l.zapL.Check(l.level, fmt.Sprintf(format, v...)); checkedEntry != nil {
 		checkedEntry.Write()
 	}
 }
 
 func (l *infoLogger) Infow(msg string, keysAndValues ...any) {
-	if checkedEntry := l.l.Check(l.level, msg); checkedEntry != nil {
-		checkedEntry.Write(handleFields(l.l, keysAndValues)...)
+	if checkedEntry := l.l.zapL.Check(l.level, msg); checkedEntry != nil {
+		checkedEntry.Write(handleFields(l.l.zapL, keysAndValues)...)
 	}
 }
 
 func (l *infoLogger) Enabled() bool {
-	return true
+	return l.l.enabled(l.level)
 }
 
 // handleFields converts a bunch of arbitrary key-value pairs into Zap fields.  It takes
@@ -156,31 +163,35 @@ func handleFields(l *zap.Logger, args []interface{}, additional ...zap.Field) []
 type Level = zapcore.Level
 
 type zapLogger struct {
-	// infoLogger
 	zapL *zap.Logger
-	al   *zap.AtomicLevel
+	// levels 全局级别及按名称覆盖的级别
+	levels *levels
+	// writers 异步写入的输出位置，替换默认日志记录器后关闭
+	writers []*AsyncWriter
 }
 
-// Enabled implements Logger.
-// Subtle: this method shadows the method (infoLogger).Enabled of zapLogger.infoLogger.
+// Enabled 返回是否输出 info 级别的日志
 func (l *zapLogger) Enabled() bool {
-	panic("unimplemented")
+	return l.enabled(InfoLevel)
+}
+
+// enabled 判断是否输出 level 级别的日志，按名称覆盖的级别同样生效，不经过采样
+func (l *zapLogger) enabled(level Level) bool {
+	if !l.zapL.Core().Enabled(level) {
+		return false
+	}
+
+	return l.levels == nil || l.levels.enabled(l.zapL.Name(), level)
 }
 
-// Info implements Logger.
-// Subtle: this method shadows the method (infoLogger).Info of zapLogger.infoLogger.
 func (l *zapLogger) Info(msg string, fields ...zapcore.Field) {
 	l.zapL.Info(msg, fields...)
 }
 
-// Infof implements Logger.
-// Subtle: this method shadows the method (infoLogger).Infof of zapLogger.infoLogger.
 func (l *zapLogger) Infof(format string, v ...any) {
 	l.zapL.Sugar().Infof(format, v...)
 }
 
-// Infow implements Logger.
-// Subtle: this method shadows the method (infoLogger).Infow of zapLogger.infoLogger.
 func (l *zapLogger) Infow(msg string, keysAndValues ...any) {
 	l.zapL.Sugar().Infow(msg, keysAndValues...)
 }
@@ -239,15 +250,9 @@ func (l *zapLogger) Fatalw(msg string, keysAndValues ...any) {
 	l.zapL.Sugar().Fatalw(msg, keysAndValues...)
 }
 
-// V 返回一个日志记录器，该记录器仅记录级别大于等于给定级别的日志。
+// V 返回以 level 级别输出日志的 InfoLogger，是否输出在每次调用时按当前的级别判断
 func (l *zapLogger) V(level Level) InfoLogger {
-	if l.zapL.Core().Enabled(level) {
-		return &infoLogger{
-			l:     l.zapL,
-			level: level,
-		}
-	}
-	return disableInfoLogger
+	return &infoLogger{l: l, level: level}
 }
 
 // L 从 Context 中取出指定的keyValue， 作为上下文添加到日志输出中
@@ -263,6 +268,9 @@ func (l *zapLogger) L(ctx context.Context) *zapLogger {
 	if watcherName := ctx.Value("watcher"); watcherName != nil {
 		lg.zapL = lg.zapL.With(zap.Any("watcher", watcherName))
 	}
+	if fields := traceFields(ctx); len(fields) != 0 {
+		lg.zapL = lg.zapL.With(fields...)
+	}
 
 	return lg
 }
@@ -274,22 +282,27 @@ func (l *zapLogger) clone() *zapLogger {
 	return &copy
 }
 
+// Write 以 info 级别输出 p，去掉末尾的换行符，用于作为标准库 log 等的输出
 func (l *zapLogger) Write(p []byte) (n int, err error) {
-	l.zapL.Info(string(p))
+	l.zapL.Info(strings.TrimSuffix(string(p), "\n"))
 
 	return len(p), nil
 }
 
 func (l *zapLogger) WithValues(keysAndValues ...any) Logger {
-	newLogger := l.zapL.With(handleFields(l.zapL, keysAndValues)...)
-
-	return newLoggerWithX(newLogger)
+	return l.with(l.zapL.With(handleFields(l.zapL, keysAndValues)...))
 }
 
 func (l *zapLogger) WithName(name string) Logger {
-	newLogger := l.zapL.Named(name)
+	return l.with(l.zapL.Named(name))
+}
+
+// with 返回使用 zl 输出日志的副本，与 l 共享级别
+func (l *zapLogger) with(zl *zap.Logger) *zapLogger {
+	lg := l.clone()
+	lg.zapL = zl
 
-	return newLoggerWithX(newLogger)
+	return lg
 }
 
 func (l *zapLogger) Flush() error {
@@ -300,13 +313,17 @@ func (l *zapLogger) Sync() error {
 	return l.zapL.Sync()
 }
 
-// TODO: 设置日志编码器 && 日志写入器
-// func (l *zapLogger) SetEncoder(encoderType LogEncoder) {}
-// func (l *zapLogger) SetWriter() zapcore.WriteSyncer {}
+// closeWriters 写入并关闭异步写入的输出位置，之后的日志同步写入
+func (l *zapLogger) closeWriters() {
+	for _, w := range l.writers {
+		_ = w.Close()
+	}
+}
 
+// SetLevel 永久修改全局级别，并取消全局级别的临时修改
 func (l *zapLogger) SetLevel(level Level) {
-	if l.al != nil {
-		l.al.SetLevel(level)
+	if l.levels != nil {
+		l.levels.set("", level, 0)
 	}
 }
 
@@ -318,28 +335,18 @@ func New(out io.Writer, level Level, opts ...Option) *zapLogger {
 		out = os.Stdout
 	}
 
-	al := zap.NewAtomicLevelAt(level)
+	lv := newLevels(level)
 	cfg := zap.NewProductionEncoderConfig()
 	cfg.EncodeTime = zapcore.RFC3339TimeEncoder
 
-	core := zapcore.NewCore(
+	core := newRedactCore(zapcore.NewCore(
 		zapcore.NewJSONEncoder(cfg),
 		zapcore.AddSync(out),
-		al,
-	)
+		zapcore.DebugLevel,
+	))
 	return &zapLogger{
-		zapL: zap.New(core, opts...),
-		al:   &al,
-	}
-}
-
-func newLoggerWithX(l *zap.Logger) Logger {
-	return &zapLogger{
-		zapL: l,
-		// infoLogger: infoLogger{
-		// 	l:     l,
-		// 	level: InfoLevel,
-		// },
+		zapL:   zap.New(newLevelCore(core, lv), opts...),
+		levels: lv,
 	}
 }
 
@@ -360,10 +367,6 @@ func ReplaceDefault(l *zapLogger) {
 	std = l
 }
 
-// func SetLevel(level Level) {
-// 	std.SetLevel(level)
-// }
-
 // CheckInternal 函数根据传入的级别值，检查当前 logger 是否能够记录该级别的日志
 func CheckInternal(level int32) bool {
 	var lvl Level
@@ -382,76 +385,77 @@ func CheckInternal(level int32) bool {
 	return checkEntry != nil
 }
 
+// 包级别的日志函数直接调用 zap.Logger，与 zapLogger 的方法保持相同的调用深度，caller 才能指向调用方
 func Info(msg string, fields ...Field) {
-	std.Info(msg, fields...)
+	std.zapL.Info(msg, fields...)
 }
 
 func Infof(format string, v ...any) {
-	std.Infof(format, v...)
+	std.zapL.Sugar().Infof(format, v...)
 }
 
 func Infow(msg string, keysAndValues ...any) {
-	std.Infow(msg, keysAndValues...)
+	std.zapL.Sugar().Infow(msg, keysAndValues...)
 }
 
 func Debug(msg string, fields ...Field) {
-	std.Debug(msg, fields...)
+	std.zapL.Debug(msg, fields...)
 }
 
 func Debugf(format string, v ...any) {
-	std.Debugf(format, v...)
+	std.zapL.Sugar().Debugf(format, v...)
 }
 
 func Debugw(msg string, keysAndValues ...any) {
-	std.Debugw(msg, keysAndValues...)
+	std.zapL.Sugar().Debugw(msg, keysAndValues...)
 }
 
 func Warn(msg string, fields ...Field) {
-	std.Warn(msg, fields...)
+	std.zapL.Warn(msg, fields...)
 }
 
 func Warnf(format string, v ...any) {
-	std.Warnf(format, v...)
+	std.zapL.Sugar().Warnf(format, v...)
 }
 
 func Warnw(msg string, keysAndValues ...any) {
-	std.Warnw(msg, keysAndValues...)
+	std.zapL.Sugar().Warnw(msg, keysAndValues...)
 }
 
 func Error(msg string, fields ...Field) {
-	std.Error(msg, fields...)
+	std.zapL.Error(msg, fields...)
 }
 
 func Errorf(format string, v ...any) {
-	std.Errorf(format, v...)
+	std.zapL.Sugar().Errorf(format, v...)
 }
 
 func Errorw(msg string, keysAndValues ...any) {
-	std.Errorw(msg, keysAndValues...)
+	std.zapL.Sugar().Errorw(msg, keysAndValues...)
 }
 
 func Panic(msg string, fields ...Field) {
-	std.Panic(msg, fields...)
+	std.zapL.Panic(msg, fields...)
 }
 
 func Panicf(format string, v ...any) {
-	std.Panicf(format, v...)
+	std.zapL.Sugar().Panicf(format, v...)
 }
 
 func Panicw(msg string, keysAndValues ...any) {
-	std.Panicw(msg, keysAndValues...)
+	std.zapL.Sugar().Panicw(msg, keysAndValues...)
 }
 
 func Fatal(msg string, fields ...Field) {
-	std.Fatal(msg, fields...)
+	std.zapL.Fatal(msg, fields...)
 }
 
 func Fatalf(format string, v ...any) {
-	std.Fatalf(format, v...)
+	std.zapL.Sugar().Fatalf(format, v...)
 }
 
 func Fatalw(msg string, keysAndValues ...any) {
-	std.Fatalw(msg, keysAndValues...)
+	std.zapL.Sugar().Fatalw(msg, keysAndValues...)
 }
 
 func Flush() error {
@@ -470,6 +474,34 @@ func V(level Level) InfoLogger {
 	return std.V(level)
 }
 
+// L 返回 ctx 中通过 WithContext 保存的日志记录器，没有保存时使用默认日志记录器，
+// 并将 ctx 中的 requestID、username 以及 span 的 trace_id、span_id 作为字段添加到日志中
 func L(ctx context.Context) Logger {
+	if lg, ok := ctx.Value(logContextKey).(*zapLogger); ok {
+		// 日志记录器通常在认证及创建 span 之前保存，username 和 trace_id 在取出时追加
+		fields := traceFields(ctx)
+		if username, _ := ctx.Value("username").(string); username != "" {
+			fields = append(fields, zap.String("username", username))
+		}
+		if len(fields) == 0 {
+			return lg
+		}
+
+		return lg.with(lg.zapL.With(fields...))
+	}
+	if lg, ok := ctx.Value(logContextKey).(Logger); ok {
+		return lg
+	}
+
 	return std.L(ctx)
 }
+
+// traceFields 返回 ctx 中 span 的 trace_id 和 span_id，没有 span 时返回空
+func traceFields(ctx context.Context) []Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return []Field{zap.String("trace_id", sc.TraceID().String()), zap.String("span_id", sc.SpanID().String())}
+}
@@ -1,8 +1,12 @@
 package log
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"testing"
+
+	"go.uber.org/zap/zapcore"
 )
 
 func TestLog_std(t *testing.T) {
@@ -32,17 +36,30 @@ func TestLog_std(t *testing.T) {
 	// Panicf("This is a formatted %s message", "panic")
 	// Panicw("Message printed with panicw", "X-Request-ID", "fbf54504-64da-4088-9b86-67824a7fb508")
 
-	// faltal log
-	Fatal("This is a faltal message", String("key1", "value1"))
-	// Fatalf("This is a formatted %s message", "faltal")
-	// Fatalw("Message printed with faltalw", "X-Request-ID", "fbf54504-64da-4088-9b86-67824a7fb508")
+	// fatal log 会退出进程，见 TestLog_Fatal
+}
+
+func TestLog_Fatal(t *testing.T) {
+	var buf bytes.Buffer
+	// 使用 panic 代替退出进程
+	l := New(&buf, InfoLevel, WithFatalHook(zapcore.WriteThenPanic))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Fatal to call the fatal hook")
+		}
+		if !strings.Contains(buf.String(), "This is a fatal message") {
+			t.Errorf("fatal message not written:\n%s", buf.String())
+		}
+	}()
+	l.Fatal("This is a fatal message", String("key1", "value1"))
 }
 
 func TestLog_std_V(t *testing.T) {
-	// V Level 通过整型数值来灵活指定日志级别，数值越大，优先级越低
-	V(1).Info("This is a V level message")
-	V(2).Infof("This is a %s V level message", "formatted")
-	V(3).Infow("This is a V level message with fields", "X-Request-ID", "7a7b9f24-4cae-4b2a-9464-69088b45b904")
+	// V 返回以指定级别输出日志的 InfoLogger，级别低于当前级别时不输出
+	V(InfoLevel).Info("This is a V level message")
+	V(WarnLevel).Infof("This is a %s V level message", "formatted")
+	V(DebugLevel).Infow("This is a V level message with fields", "X-Request-ID", "7a7b9f24-4cae-4b2a-9464-69088b45b904")
 }
 
 func TestLog_std_WithValues(t *testing.T) {
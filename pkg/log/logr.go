@@ -0,0 +1,65 @@
+package log
+
+import (
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+)
+
+// logSink 使用 zapLogger 输出日志的 logr.LogSink
+type logSink struct {
+	l *zapLogger
+}
+
+var (
+	_ logr.LogSink          = (*logSink)(nil)
+	_ logr.CallDepthLogSink = (*logSink)(nil)
+)
+
+// NewLogSink 返回使用 l 输出日志的 logr.LogSink，logr 的 V(0) 对应 info 级别，V(1) 及以上对应 debug 级别，
+// 如 logr.New(log.NewLogSink(log.Default()))
+func NewLogSink(l *zapLogger) logr.LogSink {
+	return &logSink{l: l}
+}
+
+// logrLevel 将 logr 的 verbosity 映射为 zap 的级别
+func logrLevel(v int) Level {
+	if v <= 0 {
+		return InfoLevel
+	}
+
+	return DebugLevel
+}
+
+// Init 实现 logr.LogSink，跳过 logr.Logger 的方法，使 caller 指向调用方
+func (s *logSink) Init(info logr.RuntimeInfo) {
+	s.l = s.l.with(s.l.zapL.WithOptions(zap.AddCallerSkip(info.CallDepth)))
+}
+
+func (s *logSink) Enabled(level int) bool {
+	return s.l.enabled(logrLevel(level))
+}
+
+func (s *logSink) Info(level int, msg string, keysAndValues ...any) {
+	if ce := s.l.zapL.Check(logrLevel(level), msg); ce != nil {
+		ce.Write(handleFields(s.l.zapL, keysAndValues)...)
+	}
+}
+
+func (s *logSink) Error(err error, msg string, keysAndValues ...any) {
+	if ce := s.l.zapL.Check(ErrorLevel, msg); ce != nil {
+		ce.Write(handleFields(s.l.zapL, keysAndValues, zap.Error(err))...)
+	}
+}
+
+func (s *logSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &logSink{l: s.l.with(s.l.zapL.With(handleFields(s.l.zapL, keysAndValues)...))}
+}
+
+func (s *logSink) WithName(name string) logr.LogSink {
+	return &logSink{l: s.l.with(s.l.zapL.Named(name))}
+}
+
+// WithCallDepth 实现 logr.CallDepthLogSink
+func (s *logSink) WithCallDepth(depth int) logr.LogSink {
+	return &logSink{l: s.l.with(s.l.zapL.WithOptions(zap.AddCallerSkip(depth)))}
+}
@@ -0,0 +1,214 @@
+package logtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+)
+
+// Subject 一致性测试的被测日志记录器
+type Subject struct {
+	Logger log.Logger
+	// Entries 返回已输出的日志，为 nil 时表示不输出任何日志的实现，只校验调用不会 panic 且 Enabled 为 false
+	Entries func() []Entry
+}
+
+// NewSubject 创建最低输出级别为 level 的被测日志记录器，每个测试用例调用一次
+type NewSubject func(t *testing.T, level log.Level) Subject
+
+// RunConformance 校验 log.Logger 实现的行为：
+//   - 低于最低级别的日志不输出，各级别的 f、w 方法按格式及键值对输出
+//   - Enabled、V 按当前级别判断，V 返回的 InfoLogger 以指定级别输出
+//   - WithValues 添加字段、WithName 以 "." 连接名称，且不影响原日志记录器并保持级别
+//   - WithContext 保存的日志记录器可以通过 log.FromContext 取出
+//   - Write 以 info 级别输出并去掉末尾的换行符
+//   - 键值对数量为奇数时不 panic，Panic 系列方法总是 panic
+//
+// Fatal 系列方法会退出进程，不在校验范围内
+func RunConformance(t *testing.T, newSubject NewSubject) {
+	t.Helper()
+
+	for _, tc := range conformanceCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newSubject(t, log.InfoLevel)
+			c := &checker{t: t, s: s}
+			tc.run(c, s.Logger)
+		})
+	}
+}
+
+type conformanceCase struct {
+	name string
+	run  func(c *checker, l log.Logger)
+}
+
+var conformanceCases = []conformanceCase{
+	{"levels", func(c *checker, l log.Logger) {
+		l.Debug("debug", log.String("k", "v"))
+		l.Debugf("debug %d", 1)
+		l.Debugw("debug", "k", "v")
+		l.Info("info", log.String("k", "v"))
+		l.Infof("info %d", 1)
+		l.Infow("info", "k", "v")
+		l.Warn("warn", log.String("k", "v"))
+		l.Warnf("warn %d", 1)
+		l.Warnw("warn", "k", "v")
+		l.Error("error", log.String("k", "v"))
+		l.Errorf("error %d", 1)
+		l.Errorw("error", "k", "v")
+
+		c.expect(
+			Entry{Level: log.InfoLevel, Message: "info", Fields: map[string]any{"k": "v"}},
+			Entry{Level: log.InfoLevel, Message: "info 1"},
+			Entry{Level: log.InfoLevel, Message: "info", Fields: map[string]any{"k": "v"}},
+			Entry{Level: log.WarnLevel, Message: "warn", Fields: map[string]any{"k": "v"}},
+			Entry{Level: log.WarnLevel, Message: "warn 1"},
+			Entry{Level: log.WarnLevel, Message: "warn", Fields: map[string]any{"k": "v"}},
+			Entry{Level: log.ErrorLevel, Message: "error", Fields: map[string]any{"k": "v"}},
+			Entry{Level: log.ErrorLevel, Message: "error 1"},
+			Entry{Level: log.ErrorLevel, Message: "error", Fields: map[string]any{"k": "v"}},
+		)
+	}},
+	{"enabled", func(c *checker, l log.Logger) {
+		c.enabled("Enabled()", l.Enabled(), true)
+		c.enabled("V(debug).Enabled()", l.V(log.DebugLevel).Enabled(), false)
+		c.enabled("V(warn).Enabled()", l.V(log.WarnLevel).Enabled(), true)
+
+		l.V(log.DebugLevel).Info("hidden")
+		l.V(log.WarnLevel).Info("v warn")
+		l.V(log.WarnLevel).Infof("v warn %d", 1)
+		l.V(log.WarnLevel).Infow("v warn", "k", "v")
+
+		c.expect(
+			Entry{Level: log.WarnLevel, Message: "v warn"},
+			Entry{Level: log.WarnLevel, Message: "v warn 1"},
+			Entry{Level: log.WarnLevel, Message: "v warn", Fields: map[string]any{"k": "v"}},
+		)
+	}},
+	{"with-values", func(c *checker, l log.Logger) {
+		child := l.WithValues("k", "v")
+		child.Info("child")
+		l.Info("parent")
+		child.Debug("hidden")
+		c.enabled("WithValues().V(debug).Enabled()", child.V(log.DebugLevel).Enabled(), false)
+
+		c.expect(
+			Entry{Level: log.InfoLevel, Message: "child", Fields: map[string]any{"k": "v"}},
+			Entry{Level: log.InfoLevel, Message: "parent"},
+		)
+		c.noField(1, "k")
+	}},
+	{"with-name", func(c *checker, l log.Logger) {
+		child := l.WithName("a").WithName("b")
+		child.Info("named")
+		child.Debug("hidden")
+		c.enabled("WithName().Enabled()", child.Enabled(), true)
+
+		c.expect(Entry{Level: log.InfoLevel, Name: "a.b", Message: "named"})
+	}},
+	{"context", func(c *checker, l log.Logger) {
+		ctx := l.WithValues("k", "v").WithContext(context.Background())
+		log.FromContext(ctx).Info("from context")
+
+		c.expect(Entry{Level: log.InfoLevel, Message: "from context", Fields: map[string]any{"k": "v"}})
+	}},
+	{"write", func(c *checker, l log.Logger) {
+		p := []byte("written\n")
+		if n, err := l.Write(p); n != len(p) || err != nil {
+			c.t.Errorf("Write() = %d, %v, want %d, nil", n, err, len(p))
+		}
+
+		c.expect(Entry{Level: log.InfoLevel, Message: "written"})
+	}},
+	{"odd-key-values", func(c *checker, l log.Logger) {
+		c.notPanics("Infow", func() { l.Infow("odd", "k") })
+		c.notPanics("WithValues", func() { l.WithValues("k") })
+	}},
+	{"panic", func(c *checker, l log.Logger) {
+		c.panics("Panic", func() { l.Panic("panic") })
+		c.panics("Panicf", func() { l.Panicf("panic %d", 1) })
+		c.panics("Panicw", func() { l.Panicw("panic", "k", "v") })
+	}},
+	{"flush", func(c *checker, l log.Logger) {
+		if err := l.Flush(); err != nil {
+			c.t.Errorf("Flush() = %v", err)
+		}
+	}},
+}
+
+type checker struct {
+	t *testing.T
+	s Subject
+}
+
+// enabled 校验 Enabled 的结果，不输出日志的实现总是 false
+func (c *checker) enabled(call string, got, want bool) {
+	c.t.Helper()
+
+	if c.s.Entries == nil {
+		want = false
+	}
+	if got != want {
+		c.t.Errorf("%s = %v, want %v", call, got, want)
+	}
+}
+
+// expect 校验输出的日志依次与 want 匹配，want 中的字段需要存在且值相等，未列出的字段不校验
+func (c *checker) expect(want ...Entry) {
+	c.t.Helper()
+
+	if c.s.Entries == nil {
+		return
+	}
+	got := c.s.Entries()
+	if len(got) != len(want) {
+		c.t.Fatalf("got %d entries, want %d:\n%+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Level != w.Level || g.Name != w.Name || g.Message != w.Message {
+			c.t.Errorf("entry %d = %s %q %q, want %s %q %q", i, g.Level, g.Name, g.Message, w.Level, w.Name, w.Message)
+		}
+		for k, v := range w.Fields {
+			if gv, ok := g.Fields[k]; !ok || fmt.Sprint(gv) != fmt.Sprint(v) {
+				c.t.Errorf("entry %d field %s = %v, want %v", i, k, gv, v)
+			}
+		}
+	}
+}
+
+// noField 校验第 i 条日志没有字段 key
+func (c *checker) noField(i int, key string) {
+	c.t.Helper()
+
+	if c.s.Entries == nil {
+		return
+	}
+	if _, ok := c.s.Entries()[i].Fields[key]; ok {
+		c.t.Errorf("entry %d has unexpected field %s", i, key)
+	}
+}
+
+func (c *checker) panics(call string, fn func()) {
+	c.t.Helper()
+
+	defer func() {
+		if recover() == nil {
+			c.t.Errorf("%s did not panic", call)
+		}
+	}()
+	fn()
+}
+
+func (c *checker) notPanics(call string, fn func()) {
+	c.t.Helper()
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.t.Errorf("%s panicked: %v", call, r)
+		}
+	}()
+	fn()
+}
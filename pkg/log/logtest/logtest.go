@@ -0,0 +1,83 @@
+// Package logtest 提供测试日志输出的工具：替换默认日志记录器的 Observe，
+// 以及任何 log.Logger 实现都需要通过的一致性测试 RunConformance
+package logtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ahang7/go-IAM/pkg/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// Entry 一条日志
+type Entry struct {
+	Level   log.Level
+	Name    string
+	Message string
+	Fields  map[string]any
+}
+
+// Observe 将默认日志记录器替换为最低级别为 level 的内存日志记录器，测试结束后恢复，
+// 用于断言通过包级别日志函数输出的日志
+func Observe(t testing.TB, level log.Level) *log.ObservedLogs {
+	t.Helper()
+
+	l, logs := log.NewObserver(level)
+	old := log.Default()
+	log.ReplaceDefault(l)
+	t.Cleanup(func() { log.ReplaceDefault(old) })
+
+	return logs
+}
+
+// FromObserved 转换 log.NewObserver 保存的日志
+func FromObserved(logs *log.ObservedLogs) []Entry {
+	all := logs.All()
+	entries := make([]Entry, 0, len(all))
+	for _, e := range all {
+		entries = append(entries, Entry{
+			Level:   e.Level,
+			Name:    e.LoggerName,
+			Message: e.Message,
+			Fields:  e.ContextMap(),
+		})
+	}
+
+	return entries
+}
+
+// jsonKeys log.New 等创建的 JSON 日志中不属于字段的键
+var jsonKeys = map[string]bool{"level": true, "ts": true, "logger": true, "msg": true, "caller": true, "stacktrace": true}
+
+// ParseJSON 解析 log.New 等创建的日志记录器输出的 JSON 日志，每行一条
+func ParseJSON(data []byte) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var m map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			return nil, err
+		}
+
+		var level zapcore.Level
+		if s, ok := m["level"].(string); ok {
+			if err := level.UnmarshalText([]byte(s)); err != nil {
+				return nil, err
+			}
+		}
+		e := Entry{Level: level, Fields: make(map[string]any)}
+		e.Name, _ = m["logger"].(string)
+		e.Message, _ = m["msg"].(string)
+		for k, v := range m {
+			if !jsonKeys[k] {
+				e.Fields[k] = v
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, scanner.Err()
+}
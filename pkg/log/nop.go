@@ -0,0 +1,49 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// noopLogger 不输出任何日志的 Logger。与 zap 一致，Panic 系列方法仍然 panic，Fatal 系列方法仍然退出进程
+type noopLogger struct {
+	noopInfoLogger
+}
+
+var _ Logger = (*noopLogger)(nil)
+
+// NewNop 返回不输出任何日志的 Logger
+func NewNop() Logger {
+	return &noopLogger{}
+}
+
+func (n *noopLogger) Debug(_ string, _ ...Field)     {}
+func (n *noopLogger) Debugf(_ string, _ ...any)      {}
+func (n *noopLogger) Debugw(_ string, _ ...any)      {}
+func (n *noopLogger) Warn(_ string, _ ...Field)      {}
+func (n *noopLogger) Warnf(_ string, _ ...any)       {}
+func (n *noopLogger) Warnw(_ string, _ ...any)       {}
+func (n *noopLogger) Error(_ string, _ ...Field)     {}
+func (n *noopLogger) Errorf(_ string, _ ...any)      {}
+func (n *noopLogger) Errorw(_ string, _ ...any)      {}
+func (n *noopLogger) Panic(msg string, _ ...Field)   { panic(msg) }
+func (n *noopLogger) Panicf(format string, v ...any) { panic(fmt.Sprintf(format, v...)) }
+func (n *noopLogger) Panicw(msg string, _ ...any)    { panic(msg) }
+func (n *noopLogger) Fatal(_ string, _ ...Field)     { os.Exit(1) }
+func (n *noopLogger) Fatalf(_ string, _ ...any)      { os.Exit(1) }
+func (n *noopLogger) Fatalw(_ string, _ ...any)      { os.Exit(1) }
+
+func (n *noopLogger) V(_ Level) InfoLogger { return disableInfoLogger }
+
+func (n *noopLogger) Write(p []byte) (int, error) { return len(p), nil }
+
+func (n *noopLogger) WithValues(_ ...any) Logger { return n }
+
+func (n *noopLogger) WithName(_ string) Logger { return n }
+
+func (n *noopLogger) WithContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, logContextKey, n)
+}
+
+func (n *noopLogger) Flush() error { return nil }
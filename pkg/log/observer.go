@@ -0,0 +1,25 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type (
+	// ObservedLogs NewObserver 保存的日志
+	ObservedLogs = observer.ObservedLogs
+	// LoggedEntry NewObserver 保存的一条日志
+	LoggedEntry = observer.LoggedEntry
+)
+
+// NewObserver 返回把日志保存在内存中的日志记录器，用于在测试中断言日志输出。
+// 日志与其他日志记录器一样经过级别过滤及敏感信息隐藏，级别可以通过 SetLevel 等修改
+func NewObserver(level Level) (*zapLogger, *ObservedLogs) {
+	core, logs := observer.New(DebugLevel)
+	lv := newLevels(level)
+
+	return &zapLogger{
+		zapL:   zap.New(newLevelCore(newRedactCore(core), lv)),
+		levels: lv,
+	}, logs
+}
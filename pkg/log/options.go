@@ -1,6 +1,16 @@
 package log
 
-import "go.uber.org/zap"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
 
 type Option = zap.Option
 
@@ -18,3 +28,297 @@ var (
 	WithFatalHook = zap.WithFatalHook
 	WithClock     = zap.WithClock
 )
+
+// 日志格式
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// 日志文件轮转方式
+const (
+	RotateByTime = "time"
+	RotateBySize = "size"
+)
+
+// Options 日志配置，实现了 app.FlagsIntf
+type Options struct {
+	// Level 最低输出级别：debug、info、warn、error、panic、fatal
+	Level string `json:"level" mapstructure:"level"`
+	// Format 日志格式：json、console
+	Format string `json:"format" mapstructure:"format"`
+	// EnableColor console 格式下是否按级别输出颜色
+	EnableColor bool `json:"enable-color" mapstructure:"enable-color"`
+	// DisableCaller 是否不输出调用日志函数的文件及行号
+	DisableCaller bool `json:"disable-caller" mapstructure:"disable-caller"`
+	// StacktraceLevel 大于等于该级别的日志附带调用栈
+	StacktraceLevel string `json:"stacktrace-level" mapstructure:"stacktrace-level"`
+	// NamedLevels 按 WithName 名称覆盖的级别，对该名称及以 "名称." 开头的日志记录器生效，
+	// 通过配置文件读取时名称会被转换为小写
+	NamedLevels map[string]string `json:"named-levels" mapstructure:"named-levels"`
+	// Outputs 日志输出位置，每条日志输出到所有级别范围匹配的位置
+	Outputs []OutputOptions `json:"outputs" mapstructure:"outputs"`
+	// Sampling 按级别采样，键为 debug、info、warn、error，未配置的级别不采样
+	Sampling map[string]SamplingOptions `json:"sampling" mapstructure:"sampling"`
+	// Async 异步写入配置
+	Async AsyncOptions `json:"async" mapstructure:"async"`
+}
+
+// AsyncOptions 异步写入配置，启用后每个输出位置使用一个 AsyncWriter
+type AsyncOptions struct {
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// QueueSize 每个输出位置的队列长度，单位为条，队列已满时丢弃日志
+	QueueSize int `json:"queue-size" mapstructure:"queue-size"`
+}
+
+// OutputOptions 一个日志输出位置
+type OutputOptions struct {
+	// Path stdout、stderr 或日志文件路径
+	Path string `json:"path" mapstructure:"path"`
+	// MinLevel、MaxLevel 输出到该位置的日志级别范围，为空时不限制
+	MinLevel string `json:"min-level" mapstructure:"min-level"`
+	MaxLevel string `json:"max-level" mapstructure:"max-level"`
+	// Rotation 日志文件轮转方式：time 按时间轮转，size 按大小轮转，为空时不轮转
+	Rotation     string `json:"rotation" mapstructure:"rotation"`
+	RotateConfig `mapstructure:",squash"`
+}
+
+// NewOptions 返回默认的日志配置：info 级别的 JSON 日志输出到 stderr
+func NewOptions() *Options {
+	return &Options{
+		Level:           InfoLevel.String(),
+		Format:          FormatJSON,
+		StacktraceLevel: PanicLevel.String(),
+		Outputs:         []OutputOptions{{Path: "stderr"}},
+		Async:           AsyncOptions{QueueSize: DefaultQueueSize},
+	}
+}
+
+// AddFlags 添加日志相关的命令行参数，日志输出位置只能通过配置文件设置
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Level, "log.level", o.Level, "Minimum log level: debug, info, warn, error, panic or fatal.")
+	fs.StringVar(&o.Format, "log.format", o.Format, "Log format: json or console.")
+	fs.BoolVar(&o.EnableColor, "log.enable-color", o.EnableColor, "Colorize log levels, console format only.")
+	fs.BoolVar(&o.DisableCaller, "log.disable-caller", o.DisableCaller, "Do not annotate logs with the file and line of the caller.")
+	fs.StringVar(&o.StacktraceLevel, "log.stacktrace-level", o.StacktraceLevel, "Minimum level at which logs carry a stack trace.")
+	fs.BoolVar(&o.Async.Enabled, "log.async.enabled", o.Async.Enabled, "Write logs asynchronously through a bounded queue, logs are dropped when the queue is full.")
+	fs.IntVar(&o.Async.QueueSize, "log.async.queue-size", o.Async.QueueSize, "Number of log entries buffered per output when writing asynchronously.")
+}
+
+// Validate 校验日志配置
+func (o *Options) Validate() []error {
+	var errs []error
+
+	if _, err := zapcore.ParseLevel(o.Level); err != nil {
+		errs = append(errs, fmt.Errorf("--log.level %q is not a valid level", o.Level))
+	}
+	for name, level := range o.NamedLevels {
+		if _, err := zapcore.ParseLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("log.named-levels.%s %q is not a valid level", name, level))
+		}
+	}
+	if o.Format != FormatJSON && o.Format != FormatConsole {
+		errs = append(errs, fmt.Errorf("--log.format %q must be one of [json, console]", o.Format))
+	}
+	if _, err := zapcore.ParseLevel(o.StacktraceLevel); err != nil {
+		errs = append(errs, fmt.Errorf("--log.stacktrace-level %q is not a valid level", o.StacktraceLevel))
+	}
+	if len(o.Outputs) == 0 {
+		errs = append(errs, fmt.Errorf("log.outputs must not be empty"))
+	}
+	for i, out := range o.Outputs {
+		errs = append(errs, out.validate(fmt.Sprintf("log.outputs[%d]", i))...)
+	}
+	for name, s := range o.Sampling {
+		if level, err := zapcore.ParseLevel(name); err != nil || level > ErrorLevel {
+			errs = append(errs, fmt.Errorf("log.sampling.%s must be one of [debug, info, warn, error]", name))
+		}
+		if s.Initial <= 0 || s.Thereafter < 0 {
+			errs = append(errs, fmt.Errorf("log.sampling.%s: initial must be positive and thereafter must not be negative", name))
+		}
+	}
+	if o.Async.Enabled && o.Async.QueueSize <= 0 {
+		errs = append(errs, fmt.Errorf("--log.async.queue-size %d must be positive", o.Async.QueueSize))
+	}
+
+	return errs
+}
+
+func (o *OutputOptions) validate(prefix string) []error {
+	var errs []error
+
+	if o.Path == "" {
+		errs = append(errs, fmt.Errorf("%s.path must not be empty", prefix))
+	}
+	minLevel, minErr := o.levelRange(o.MinLevel, zapcore.DebugLevel)
+	if minErr != nil {
+		errs = append(errs, fmt.Errorf("%s.min-level %q is not a valid level", prefix, o.MinLevel))
+	}
+	maxLevel, maxErr := o.levelRange(o.MaxLevel, zapcore.FatalLevel)
+	if maxErr != nil {
+		errs = append(errs, fmt.Errorf("%s.max-level %q is not a valid level", prefix, o.MaxLevel))
+	}
+	if minErr == nil && maxErr == nil && minLevel > maxLevel {
+		errs = append(errs, fmt.Errorf("%s.min-level %s must not be greater than max-level %s", prefix, minLevel, maxLevel))
+	}
+	switch o.Rotation {
+	case "":
+	case RotateByTime, RotateBySize:
+		if o.Path == "stdout" || o.Path == "stderr" {
+			errs = append(errs, fmt.Errorf("%s.rotation is only supported for files", prefix))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%s.rotation %q must be one of [time, size]", prefix, o.Rotation))
+	}
+
+	return errs
+}
+
+// levelRange 解析级别范围的一端，为空时返回 def
+func (o *OutputOptions) levelRange(level string, def Level) (Level, error) {
+	if level == "" {
+		return def, nil
+	}
+
+	return zapcore.ParseLevel(level)
+}
+
+// Init 使用 opts 创建日志记录器并替换默认日志记录器，已经调用过 InstallDefaults 时重新安装
+func Init(opts *Options) error {
+	l, err := opts.Build()
+	if err != nil {
+		return err
+	}
+	_ = std.Flush()
+	old := std
+	ReplaceDefault(l)
+	if defaultsInstalled.Load() {
+		installDefaults(l)
+	}
+	old.closeWriters()
+
+	return nil
+}
+
+// ApplyLevels 使用配置中的全局级别及按名称覆盖的级别替换默认日志记录器的级别，
+// 并取消所有临时级别，用于配置热加载
+func (o *Options) ApplyLevels() error {
+	level, err := zapcore.ParseLevel(o.Level)
+	if err != nil {
+		return err
+	}
+	for name, l := range o.NamedLevels {
+		if _, err := zapcore.ParseLevel(l); err != nil {
+			return fmt.Errorf("log.named-levels.%s: %w", name, err)
+		}
+	}
+	std.levels.reset(level, o.namedLevels())
+
+	return nil
+}
+
+// namedLevels 解析按名称覆盖的级别，调用方需要先校验配置
+func (o *Options) namedLevels() map[string]Level {
+	named := make(map[string]Level, len(o.NamedLevels))
+	for name, level := range o.NamedLevels {
+		named[name], _ = zapcore.ParseLevel(level)
+	}
+
+	return named
+}
+
+// sampling 解析按级别的采样配置，调用方需要先校验配置
+func (o *Options) sampling() map[Level]SamplingOptions {
+	sampling := make(map[Level]SamplingOptions, len(o.Sampling))
+	for name, s := range o.Sampling {
+		level, _ := zapcore.ParseLevel(name)
+		sampling[level] = s
+	}
+
+	return sampling
+}
+
+// Build 根据配置创建日志记录器，日志级别可以通过 SetLevel、SetTemporaryLevel 动态修改
+func (o *Options) Build() (*zapLogger, error) {
+	if errs := o.Validate(); len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	level, _ := zapcore.ParseLevel(o.Level)
+	stacktrace, _ := zapcore.ParseLevel(o.StacktraceLevel)
+	lv := newLevels(level)
+	lv.reset(level, o.namedLevels())
+	enc := newEncoder(o.Format, o.EnableColor)
+
+	cores := make([]zapcore.Core, 0, len(o.Outputs))
+	var writers []*AsyncWriter
+	for _, out := range o.Outputs {
+		ws, err := out.writer()
+		if err != nil {
+			return nil, err
+		}
+		if o.Async.Enabled {
+			w := NewAsyncWriter(ws, o.Async.QueueSize)
+			writers = append(writers, w)
+			ws = w
+		}
+		minLevel, _ := out.levelRange(out.MinLevel, zapcore.DebugLevel)
+		maxLevel, _ := out.levelRange(out.MaxLevel, zapcore.FatalLevel)
+		// 全局级别及按名称覆盖的级别由 levelCore 过滤
+		enabler := zap.LevelEnablerFunc(func(l Level) bool {
+			return l >= minLevel && l <= maxLevel
+		})
+		cores = append(cores, newRedactCore(zapcore.NewCore(enc.Clone(), ws, enabler)))
+	}
+
+	opts := []Option{AddStacktrace(stacktrace)}
+	if !o.DisableCaller {
+		// 跳过 zapLogger 的方法及包级别的日志函数
+		opts = append(opts, AddCaller(), AddCallerSkip(1))
+	}
+
+	core := newSamplingCore(zapcore.NewTee(cores...), o.sampling())
+
+	return &zapLogger{
+		zapL:    zap.New(newLevelCore(core, lv), opts...),
+		levels:  lv,
+		writers: writers,
+	}, nil
+}
+
+// writer 打开日志输出位置
+func (o *OutputOptions) writer() (zapcore.WriteSyncer, error) {
+	switch o.Path {
+	case "stdout":
+		return zapcore.Lock(os.Stdout), nil
+	case "stderr":
+		return zapcore.Lock(os.Stderr), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(o.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+	cfg := o.RotateConfig
+	cfg.Filename = o.Path
+	switch o.Rotation {
+	case RotateByTime:
+		if cfg.RotationTime <= 0 {
+			cfg.RotationTime = 24 * time.Hour
+		}
+		w, err := newRotateByTime(&cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return zapcore.AddSync(w), nil
+	case RotateBySize:
+		return zapcore.AddSync(NewRotateBySize(&cfg)), nil
+	}
+
+	f, err := os.OpenFile(o.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open log file: %w", err)
+	}
+
+	return zapcore.Lock(f), nil
+}
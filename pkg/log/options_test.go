@@ -0,0 +1,58 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOptionsBuild(t *testing.T) {
+	dir := t.TempDir()
+	info, errs := filepath.Join(dir, "info.log"), filepath.Join(dir, "error.log")
+
+	opts := NewOptions()
+	opts.Level = "debug"
+	opts.Format = FormatConsole
+	opts.Outputs = []OutputOptions{
+		{Path: info, MaxLevel: "warn"},
+		{Path: errs, MinLevel: "error", Rotation: RotateBySize},
+	}
+	l, err := opts.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Debug("debug message")
+	l.Error("error message")
+	l.SetLevel(InfoLevel)
+	l.Debug("hidden message")
+	_ = l.Flush()
+
+	read := func(name string) string {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(data)
+	}
+	if got := read(info); !strings.Contains(got, "DEBUG") || !strings.Contains(got, "options_test.go") ||
+		strings.Contains(got, "error message") || strings.Contains(got, "hidden message") {
+		t.Errorf("unexpected info log:\n%s", got)
+	}
+	if got := read(errs); !strings.Contains(got, "ERROR") || strings.Contains(got, "debug message") {
+		t.Errorf("unexpected error log:\n%s", got)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	opts := NewOptions()
+	opts.Level = "verbose"
+	opts.Format = "text"
+	opts.Outputs = []OutputOptions{{Path: "stdout", MinLevel: "error", MaxLevel: "info", Rotation: RotateByTime}}
+
+	if errs := opts.Validate(); len(errs) != 4 {
+		t.Errorf("expected 4 errors, got %v", errs)
+	}
+}
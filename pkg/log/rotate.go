@@ -1,28 +1,31 @@
 package log
 
 import (
-	rotatelogs "github.com/lestrrat-go/file-rotatelogs"
-	"gopkg.in/natefinch/lumberjack.v2"
+	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 	"time"
+
+	rotatelogs "github.com/lestrrat-go/file-rotatelogs"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // 日志轮转功能 借用file-rotatelogs lumberjack实现
 
 type RotateConfig struct {
 	// 共用配置
-	Filename string // 完整配置名
-	MaxAge   int    // 保留旧日志文件最大时间
+	Filename string `json:"-" mapstructure:"-"`             // 完整配置名
+	MaxAge   int    `json:"max-age" mapstructure:"max-age"` // 保留旧日志文件最大天数
 
 	// 按时间轮转配置
-	RotationTime time.Duration
+	RotationTime time.Duration `json:"rotation-time" mapstructure:"rotation-time"`
 
 	// 按大小轮转配置
-	MaxSize    int  // 日志文件最大大小
-	MaxBackups int  // 保留日志文件的最大数量
-	Compress   bool // 是否对日志文件进行压缩
-	LocalTime  bool // 是否使用本地时间
+	MaxSize    int  `json:"max-size" mapstructure:"max-size"`       // 日志文件最大大小，单位 MB
+	MaxBackups int  `json:"max-backups" mapstructure:"max-backups"` // 保留日志文件的最大数量
+	Compress   bool `json:"compress" mapstructure:"compress"`       // 是否对日志文件进行压缩
+	LocalTime  bool `json:"local-time" mapstructure:"local-time"`   // 是否使用本地时间
 }
 
 func NewProductionRotateByTime(filename string) io.Writer {
@@ -47,24 +50,34 @@ func NewProductionRotateConfig(filename string) *RotateConfig {
 	}
 }
 
+// NewRotateByTime 创建按时间轮转的日志文件，创建失败时返回 nil
 func NewRotateByTime(cfg *RotateConfig) io.Writer {
+	w, err := newRotateByTime(cfg)
+	if err != nil {
+		return nil
+	}
+
+	return w
+}
+
+// newRotateByTime 轮转后的文件名在扩展名之前加上时间，例如 iam.log 轮转为 iam.2006-01-02-15-04-05.log，
+// cfg.Filename 为指向当前文件的软链接
+func newRotateByTime(cfg *RotateConfig) (io.Writer, error) {
 	opts := []rotatelogs.Option{
 		rotatelogs.WithMaxAge(time.Duration(cfg.MaxAge) * time.Hour * 24),
 		rotatelogs.WithRotationTime(cfg.RotationTime),
 		rotatelogs.WithLinkName(cfg.Filename),
 	}
 	if !cfg.LocalTime {
-		rotatelogs.WithClock(rotatelogs.UTC)
+		opts = append(opts, rotatelogs.WithClock(rotatelogs.UTC))
 	}
-	filename := strings.SplitN(cfg.Filename, ".", 2)
-	logs, err := rotatelogs.New(
-		filename[0]+".%Y-%m-%d-%H-%M-%S"+filename[1],
-		opts...,
-	)
+	ext := filepath.Ext(cfg.Filename)
+	logs, err := rotatelogs.New(strings.TrimSuffix(cfg.Filename, ext)+".%Y-%m-%d-%H-%M-%S"+ext, opts...)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("rotate %s by time: %w", cfg.Filename, err)
 	}
-	return logs
+
+	return logs, nil
 }
 
 func NewRotateBySize(cfg *RotateConfig) io.Writer {
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// samplingTick 采样的统计周期
+const samplingTick = time.Second
+
+// SamplingOptions 一个级别的采样配置：每秒内相同级别、相同消息的日志先输出 Initial 条，
+// 之后每 Thereafter 条输出一条，Thereafter 为 0 时丢弃之后的日志
+type SamplingOptions struct {
+	Initial    int `json:"initial" mapstructure:"initial"`
+	Thereafter int `json:"thereafter" mapstructure:"thereafter"`
+}
+
+// WithSampling 返回按级别采样的 Option，用于 New、NewTee 创建的日志记录器，未配置的级别不采样
+func WithSampling(sampling map[Level]SamplingOptions) Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newSamplingCore(core, sampling)
+	})
+}
+
+// samplingCore 按级别选择采样器的 core，未配置采样的级别直接交给被包装的 core
+type samplingCore struct {
+	zapcore.Core
+	samplers map[Level]zapcore.Core
+}
+
+func newSamplingCore(core zapcore.Core, sampling map[Level]SamplingOptions) zapcore.Core {
+	if len(sampling) == 0 {
+		return core
+	}
+
+	hook := zapcore.SamplerHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+		if dec&zapcore.LogDropped != 0 {
+			drops.sampled.Add(1)
+		}
+	})
+	samplers := make(map[Level]zapcore.Core, len(sampling))
+	for level, s := range sampling {
+		samplers[level] = zapcore.NewSamplerWithOptions(core, samplingTick, s.Initial, s.Thereafter, hook)
+	}
+
+	return &samplingCore{Core: core, samplers: samplers}
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	// 采样器的 With 与原采样器共享计数
+	samplers := make(map[Level]zapcore.Core, len(c.samplers))
+	for level, s := range c.samplers {
+		samplers[level] = s.With(fields)
+	}
+
+	return &samplingCore{Core: c.Core.With(fields), samplers: samplers}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if s, ok := c.samplers[ent.Level]; ok {
+		return s.Check(ent, ce)
+	}
+
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,169 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogHandler 使用 zapLogger 输出日志的 slog.Handler，与 zapLogger 共享编码器、级别、采样及敏感信息隐藏
+type slogHandler struct {
+	l *zapLogger
+	// groups 通过 WithGroup 打开但还没有添加属性的分组，没有属性时不输出
+	groups []string
+}
+
+var _ slog.Handler = (*slogHandler)(nil)
+
+// NewSlogHandler 返回使用 l 输出日志的 slog.Handler，slog 的级别按 debug、info、warn、error 区间映射到 zap 的级别，
+// ctx 中有 span 时添加 trace_id 和 span_id
+func NewSlogHandler(l *zapLogger) slog.Handler {
+	return &slogHandler{l: l}
+}
+
+// slogLevel 将 slog 的级别映射为 zap 的级别，如 slog.LevelInfo+2 映射为 info
+func slogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.l.enabled(slogLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	ce := h.l.zapL.Check(slogLevel(r.Level), r.Message)
+	if ce == nil {
+		return nil
+	}
+	// 时间为零值时 zap 不输出时间
+	ce.Time = r.Time
+	// 调用方为 slog.Logger 的调用方，而不是 slog 内部
+	if ce.Caller.Defined && r.PC != 0 {
+		f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ce.Caller = zapcore.NewEntryCaller(f.PC, f.File, f.Line, true)
+	}
+
+	fields := make([]Field, 0, r.NumAttrs()+len(h.groups)+2)
+	r.Attrs(func(a slog.Attr) bool {
+		if f, ok := slogField(a); ok {
+			fields = append(fields, f)
+		}
+
+		return true
+	})
+	if len(fields) > 0 && len(h.groups) > 0 {
+		fields = append(namespaces(h.groups), fields...)
+	}
+	if ctx != nil {
+		fields = append(fields, traceFields(ctx)...)
+	}
+	ce.Write(fields...)
+
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]Field, 0, len(attrs))
+	for _, a := range attrs {
+		if f, ok := slogField(a); ok {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return h
+	}
+
+	return &slogHandler{l: h.l.with(h.l.zapL.With(append(namespaces(h.groups), fields...)...))}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+
+	return &slogHandler{l: h.l, groups: append(groups, name)}
+}
+
+func namespaces(groups []string) []Field {
+	fields := make([]Field, 0, len(groups))
+	for _, g := range groups {
+		fields = append(fields, zap.Namespace(g))
+	}
+
+	return fields
+}
+
+// slogField 将 slog 属性转换为 zap 字段，空属性及没有属性的分组返回 false
+func slogField(a slog.Attr) (Field, bool) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return Field{}, false
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return zap.String(a.Key, a.Value.String()), true
+	case slog.KindInt64:
+		return zap.Int64(a.Key, a.Value.Int64()), true
+	case slog.KindUint64:
+		return zap.Uint64(a.Key, a.Value.Uint64()), true
+	case slog.KindFloat64:
+		return zap.Float64(a.Key, a.Value.Float64()), true
+	case slog.KindBool:
+		return zap.Bool(a.Key, a.Value.Bool()), true
+	case slog.KindDuration:
+		return zap.Duration(a.Key, a.Value.Duration()), true
+	case slog.KindTime:
+		return zap.Time(a.Key, a.Value.Time()), true
+	case slog.KindGroup:
+		var group slogGroup
+		for _, ga := range a.Value.Group() {
+			if _, ok := slogField(ga); ok {
+				group = append(group, ga)
+			}
+		}
+		if len(group) == 0 {
+			return Field{}, false
+		}
+		// 键为空的分组展开到上一级
+		if a.Key == "" {
+			return zap.Inline(group), true
+		}
+
+		return zap.Object(a.Key, group), true
+	default:
+		if err, ok := a.Value.Any().(error); ok {
+			return zap.NamedError(a.Key, err), true
+		}
+
+		return zap.Any(a.Key, a.Value.Any()), true
+	}
+}
+
+// slogGroup 以 JSON 对象输出的 slog 分组，redactCore 看不到对象内部的字段，在这里隐藏敏感信息
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, a := range g {
+		if f, ok := slogField(a); ok {
+			f, _ = redactField(f)
+			f.AddTo(enc)
+		}
+	}
+
+	return nil
+}
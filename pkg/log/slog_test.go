@@ -0,0 +1,116 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/go-logr/logr"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	slogtest.Run(t, func(*testing.T) slog.Handler {
+		buf.Reset()
+
+		return NewSlogHandler(New(&buf, DebugLevel))
+	}, func(t *testing.T) map[string]any {
+		m := map[string]any{}
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		if ts, ok := m["ts"]; ok {
+			m[slog.TimeKey] = ts
+			delete(m, "ts")
+		}
+
+		return m
+	})
+}
+
+func TestSlogHandlerLevelsAndRedaction(t *testing.T) {
+	l, logs := NewObserver(InfoLevel)
+	sl := slog.New(NewSlogHandler(l))
+
+	sl.Debug("hidden")
+	sl.Info("info", slog.Group("db", "password", "s3cret", "host", "127.0.0.1"))
+	sl.Log(context.Background(), slog.LevelWarn+2, "warn")
+	sl.Error("error", "err", errors.New("failed"))
+
+	entries := logs.All()
+	if len(entries) != 3 || entries[0].Level != InfoLevel || entries[1].Level != WarnLevel || entries[2].Level != ErrorLevel {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if db, _ := entries[0].ContextMap()["db"].(map[string]any); db["password"] != Redacted || db["host"] != "127.0.0.1" {
+		t.Errorf("unexpected group %+v", entries[0].ContextMap())
+	}
+	if sl.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected debug to be disabled")
+	}
+}
+
+func TestLogSink(t *testing.T) {
+	l, logs := NewObserver(InfoLevel)
+	lr := logr.New(NewLogSink(l)).WithName("lib").WithValues("k", "v")
+
+	lr.Info("info")
+	lr.V(1).Info("hidden")
+	lr.Error(errors.New("failed"), "error", "token", "s3cret")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if e := entries[0]; e.LoggerName != "lib" || e.Level != InfoLevel || e.ContextMap()["k"] != "v" {
+		t.Errorf("unexpected info entry %+v", e)
+	}
+	if e := entries[1]; e.Level != ErrorLevel || e.ContextMap()["error"] != "failed" || e.ContextMap()["token"] != Redacted {
+		t.Errorf("unexpected error entry %+v", e)
+	}
+	if lr.V(1).Enabled() {
+		t.Error("expected V(1) to be disabled at info level")
+	}
+	l.SetLevel(DebugLevel)
+	if !lr.V(1).Enabled() {
+		t.Error("expected V(1) to be enabled at debug level")
+	}
+}
+
+func TestGRPCLogger(t *testing.T) {
+	var buf bytes.Buffer
+	g := NewGRPCLogger(New(&buf, InfoLevel))
+
+	g.Infof("channel %s", "connecting")
+	g.Warningln("transport", "closed")
+	if g.V(2) {
+		t.Error("expected verbose logs to be disabled at info level")
+	}
+
+	got := buf.String()
+	if strings.Contains(got, "connecting") || !strings.Contains(got, `"msg":"transport closed"`) || !strings.Contains(got, `"logger":"grpc"`) {
+		t.Errorf("unexpected log:\n%s", got)
+	}
+}
+
+func TestInstallDefaults(t *testing.T) {
+	old, oldSlog := Default(), slog.Default()
+	defer func() {
+		ReplaceDefault(old)
+		slog.SetDefault(oldSlog)
+		defaultsInstalled.Store(false)
+	}()
+
+	var buf bytes.Buffer
+	ReplaceDefault(New(&buf, InfoLevel))
+	InstallDefaults()
+
+	slog.Info("from slog", "password", "s3cret")
+	if got := buf.String(); !strings.Contains(got, `"msg":"from slog"`) || strings.Contains(got, "s3cret") {
+		t.Errorf("unexpected log:\n%s", got)
+	}
+}
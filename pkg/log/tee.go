@@ -12,22 +12,45 @@ type LevelEnablerFunc func(level Level) bool
 type TeeOption struct {
 	Out io.Writer
 	LevelEnablerFunc
+	// Encoder 日志编码器，为空时使用 JSON 编码器
+	Encoder zapcore.Encoder
 }
 
 func NewTee(tees []TeeOption, opts ...Option) *zapLogger {
 	var cores []zapcore.Core
 	for _, tee := range tees {
-		cfg := zap.NewProductionEncoderConfig()
-		cfg.EncodeTime = zapcore.RFC3339TimeEncoder
-		core := zapcore.NewCore(
-			zapcore.NewJSONEncoder(cfg),
+		enc := tee.Encoder
+		if enc == nil {
+			enc = newEncoder(FormatJSON, false)
+		}
+		core := newRedactCore(zapcore.NewCore(
+			enc,
 			zapcore.AddSync(tee.Out),
 			zap.LevelEnablerFunc(tee.LevelEnablerFunc),
-		)
+		))
 		cores = append(cores, core)
 	}
+	// 全局级别默认不限制，只按 LevelEnablerFunc 过滤
+	lv := newLevels(DebugLevel)
+
 	return &zapLogger{
-		zapL: zap.New(zapcore.NewTee(cores...), opts...),
-		// al:   nil,
+		zapL:   zap.New(newLevelCore(zapcore.NewTee(cores...), lv), opts...),
+		levels: lv,
+	}
+}
+
+// newEncoder 创建日志编码器，color 只对 console 格式生效
+func newEncoder(format string, color bool) zapcore.Encoder {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.RFC3339TimeEncoder
+	if format != FormatConsole {
+		return zapcore.NewJSONEncoder(cfg)
 	}
+
+	cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+	if color {
+		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+
+	return zapcore.NewConsoleEncoder(cfg)
 }
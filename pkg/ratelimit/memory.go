@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// 每处理 sweepInterval 次请求清理一次已经装满的令牌桶
+const sweepInterval = 1024
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryStore 进程内的令牌桶存储，只适用于单副本部署
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	limits  map[string]Limit
+	calls   int
+
+	now func() time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore 创建进程内的令牌桶存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		limits:  make(map[string]Limit),
+		now:     time.Now,
+	}
+}
+
+// Allow implements Store.
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+	s.limits[key] = limit
+
+	refill(b, limit, now)
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	s.calls++
+	if s.calls%sweepInterval == 0 {
+		s.sweep(now)
+	}
+
+	return newResult(allowed, b.tokens, limit), nil
+}
+
+func refill(b *bucket, limit Limit, now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+		b.last = now
+	}
+}
+
+// sweep 删除已经装满的令牌桶，装满的桶与不存在的桶等价
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		limit := s.limits[key]
+		refill(b, limit, now)
+		if b.tokens >= float64(limit.Burst) {
+			delete(s.buckets, key)
+			delete(s.limits, key)
+		}
+	}
+}
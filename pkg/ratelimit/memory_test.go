@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Allow(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 1, Burst: 2}
+	ctx := context.Background()
+
+	for i, want := range []int{1, 0} {
+		res, _ := s.Allow(ctx, "k", limit)
+		if !res.Allowed || res.Remaining != want {
+			t.Fatalf("request %d: got allowed=%v remaining=%d, want allowed remaining=%d", i, res.Allowed, res.Remaining, want)
+		}
+	}
+
+	res, _ := s.Allow(ctx, "k", limit)
+	if res.Allowed {
+		t.Fatal("request exceeding burst should be denied")
+	}
+	if res.RetryAfter != time.Second {
+		t.Fatalf("RetryAfter = %s, want 1s", res.RetryAfter)
+	}
+
+	// 其它 key 不受影响
+	if res, _ := s.Allow(ctx, "other", limit); !res.Allowed {
+		t.Fatal("independent key should be allowed")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if res, _ := s.Allow(ctx, "k", limit); res.Allowed {
+		t.Fatal("half a token should not be enough")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if res, _ := s.Allow(ctx, "k", limit); !res.Allowed {
+		t.Fatal("refilled token should be allowed")
+	}
+}
@@ -0,0 +1,62 @@
+// Package ratelimit 提供基于令牌桶算法的限流器，支持进程内存储和 Redis 共享存储。
+//
+// 每个 key 对应一个令牌桶，桶容量为 Burst，令牌以每秒 Rate 个的速度补充，
+// 每次请求消耗一个令牌，桶中没有令牌时请求被拒绝。
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit 令牌桶参数
+type Limit struct {
+	// Rate 每秒补充的令牌数
+	Rate float64
+	// Burst 桶容量，即允许的最大突发请求数
+	Burst int
+}
+
+// Result 一次限流检查的结果
+type Result struct {
+	// Allowed 请求是否被允许
+	Allowed bool
+	// Limit 桶容量
+	Limit int
+	// Remaining 本次请求后桶中剩余的令牌数
+	Remaining int
+	// RetryAfter 请求被拒绝时，距离下一个可用令牌的时间
+	RetryAfter time.Duration
+	// ResetAfter 距离令牌桶装满的时间
+	ResetAfter time.Duration
+}
+
+// Store 令牌桶存储
+type Store interface {
+	// Allow 从 key 对应的令牌桶中取出一个令牌
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// newResult 根据取令牌后桶中的令牌数计算限流结果
+func newResult(allowed bool, tokens float64, limit Limit) Result {
+	res := Result{
+		Allowed:    allowed,
+		Limit:      limit.Burst,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+
+	return res
+}
+
+func secondsToDuration(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+
+	return time.Duration(s * float64(time.Second))
+}
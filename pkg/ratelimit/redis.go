@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript 原子地补充并取出令牌，使用 Redis 服务端时间以避免多副本时钟不一致。
+// 返回 {是否允许, 剩余令牌数}
+var tokenBucketScript = redis.NewScript(`
+local key = KEYS[1]
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + tonumber(t[2]) / 1000
+
+local data = redis.call("HMGET", key, "tokens", "ts")
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call("HSET", key, "tokens", tokens, "ts", now)
+redis.call("PEXPIRE", key, math.ceil(burst / rate * 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore 基于 Redis 的令牌桶存储，多个副本共享同一份限流状态
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore 创建基于 Redis 的令牌桶存储，prefix 为 key 的前缀
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Allow implements Store.
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	v, err := tokenBucketScript.Run(ctx, s.client, []string{s.prefix + key},
+		strconv.FormatFloat(limit.Rate, 'f', -1, 64), limit.Burst).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed, _ := v[0].(int64)
+	tokensStr, _ := v[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newResult(allowed == 1, math.Max(tokens, 0), limit), nil
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisStore_Allow(t *testing.T) {
+	mr := miniredis.RunT(t)
+	now := time.Unix(1700000000, 0)
+	mr.SetTime(now)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	s := NewRedisStore(client, "test:")
+
+	limit := Limit{Rate: 1, Burst: 2}
+	ctx := context.Background()
+
+	for i, want := range []int{1, 0} {
+		res, err := s.Allow(ctx, "k", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Allowed || res.Remaining != want {
+			t.Fatalf("request %d: got allowed=%v remaining=%d, want allowed remaining=%d", i, res.Allowed, res.Remaining, want)
+		}
+	}
+
+	res, err := s.Allow(ctx, "k", limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Allowed || res.RetryAfter != time.Second {
+		t.Fatalf("request exceeding burst = allowed=%v retry after %s, want denied and 1s", res.Allowed, res.RetryAfter)
+	}
+	if !mr.Exists("test:k") || mr.TTL("test:k") <= 0 {
+		t.Errorf("bucket key test:k should exist with a ttl, ttl = %s", mr.TTL("test:k"))
+	}
+
+	// 其它 key 不受影响
+	if res, _ := s.Allow(ctx, "other", limit); !res.Allowed {
+		t.Fatal("independent key should be allowed")
+	}
+
+	// 令牌按 Redis 服务端时间补充
+	mr.SetTime(now.Add(500 * time.Millisecond))
+	if res, _ := s.Allow(ctx, "k", limit); res.Allowed {
+		t.Fatal("half a token should not be enough")
+	}
+	mr.SetTime(now.Add(time.Second))
+	if res, _ := s.Allow(ctx, "k", limit); !res.Allowed {
+		t.Fatal("refilled token should be allowed")
+	}
+}
+
+func TestRedisStore_Error(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer client.Close()
+	mr.Close()
+
+	if _, err := NewRedisStore(client, "test:").Allow(context.Background(), "k", Limit{Rate: 1, Burst: 1}); err == nil {
+		t.Error("Allow() should fail when redis is unavailable")
+	}
+}
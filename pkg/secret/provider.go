@@ -0,0 +1,39 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// EnvProvider 从环境变量读取密钥，环境变量未设置时返回 ErrNotFound，设置为空时返回空字符串
+type EnvProvider struct{}
+
+// Resolve 实现 Provider
+func (EnvProvider) Resolve(_ context.Context, ref string) (string, error) {
+	if v, ok := os.LookupEnv(ref); ok {
+		return v, nil
+	}
+
+	return "", fmt.Errorf("environment variable %s is not set: %w", ref, ErrNotFound)
+}
+
+// FileProvider 从文件读取密钥，去除末尾的换行符，适用于 docker/k8s secret 挂载的文件
+type FileProvider struct{}
+
+// Resolve 实现 Provider
+func (FileProvider) Resolve(_ context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %w", ErrNotFound, err)
+		}
+
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,195 @@
+// Package secret 解析配置值中的密钥引用。
+//
+// 引用的格式为 ${scheme:ref}，例如 ${env:IAM_DATABASE_PASSWORD}、${file:/run/secrets/db}、
+// ${vault:secret/data/iam#password}。引用可以带有默认值 ${scheme:ref:-default}，
+// 密钥不存在时使用默认值，$${ 表示字面量 ${。
+// 调用方通过 Track 记录敏感配置项的值，Redact 会将其替换为 ******，用于打印配置及日志
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask 替换密钥的字符串
+const Mask = "******"
+
+// minTrackLength 短于该长度的值不记录，避免 Redact 替换其他配置值中常见的短子串
+const minTrackLength = 6
+
+// ErrNotFound 密钥不存在，Provider 返回的错误包含 ErrNotFound 时使用引用中的默认值
+var ErrNotFound = errors.New("secret not found")
+
+// Provider 密钥提供者，根据引用返回密钥
+type Provider interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// ProviderFunc 函数形式的 Provider
+type ProviderFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve 实现 Provider
+func (f ProviderFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// Resolver 按 scheme 选择 Provider 解析引用，并记录需要隐藏的密钥
+type Resolver struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+	resolved  map[string]struct{}
+}
+
+// NewResolver 创建解析器，默认注册 env 和 file，环境变量 VAULT_ADDR 不为空时注册 vault
+func NewResolver() *Resolver {
+	r := &Resolver{
+		providers: make(map[string]Provider),
+		resolved:  make(map[string]struct{}),
+	}
+	r.Register("env", EnvProvider{})
+	r.Register("file", FileProvider{})
+	if p := NewVaultProviderFromEnv(); p != nil {
+		r.Register("vault", p)
+	}
+
+	return r
+}
+
+// Register 注册 scheme 对应的 Provider，同名时覆盖
+func (r *Resolver) Register(scheme string, p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.providers[scheme] = p
+}
+
+// Expand 解析 s 中的全部引用，没有引用时原样返回
+func (r *Resolver) Expand(ctx context.Context, s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+
+			break
+		}
+		// $${ 转义为字面量 ${
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1])
+			b.WriteString("${")
+			s = s[i+2:]
+
+			continue
+		}
+		end := strings.Index(s[i:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated secret reference in %q", s)
+		}
+		value, err := r.resolve(ctx, s[i+2:i+end])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(s[:i])
+		b.WriteString(value)
+		s = s[i+end+1:]
+	}
+
+	return b.String(), nil
+}
+
+func (r *Resolver) resolve(ctx context.Context, expr string) (string, error) {
+	scheme, ref, ok := strings.Cut(expr, ":")
+	ref, def, hasDefault := strings.Cut(ref, ":-")
+	if !ok || ref == "" {
+		return "", fmt.Errorf("invalid secret reference ${%s}, must be ${scheme:ref}", expr)
+	}
+
+	r.mu.RLock()
+	p, ok := r.providers[scheme]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown secret provider %q in ${%s}", scheme, expr)
+	}
+
+	value, err := p.Resolve(ctx, ref)
+	if err != nil {
+		if hasDefault && errors.Is(err, ErrNotFound) {
+			return def, nil
+		}
+
+		return "", fmt.Errorf("resolve ${%s}: %w", expr, err)
+	}
+
+	return value, nil
+}
+
+// Track 记录需要隐藏的密钥，只应记录敏感配置项的值，短于 6 个字符的值不记录
+func (r *Resolver) Track(value string) {
+	if len(value) < minTrackLength {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resolved[value] = struct{}{}
+}
+
+// Redact 将 s 中通过 Track 记录的密钥替换为 Mask，同时处理 JSON 转义后的形式
+func (r *Resolver) Redact(s string) string {
+	r.mu.RLock()
+	values := make([]string, 0, len(r.resolved))
+	for v := range r.resolved {
+		values = append(values, v)
+	}
+	r.mu.RUnlock()
+
+	// 先替换较长的密钥，避免较短的密钥是其子串时替换不完整
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, Mask)
+		if data, err := json.Marshal(v); err == nil {
+			if escaped := string(data[1 : len(data)-1]); escaped != v {
+				s = strings.ReplaceAll(s, escaped, Mask)
+			}
+		}
+	}
+
+	return s
+}
+
+var std = NewResolver()
+
+// Default 返回默认的解析器
+func Default() *Resolver {
+	return std
+}
+
+// Register 向默认解析器注册 Provider
+func Register(scheme string, p Provider) {
+	std.Register(scheme, p)
+}
+
+// Expand 使用默认解析器解析 s 中的引用
+func Expand(ctx context.Context, s string) (string, error) {
+	return std.Expand(ctx, s)
+}
+
+// Track 向默认解析器记录需要隐藏的密钥
+func Track(value string) {
+	std.Track(value)
+}
+
+// Redact 使用默认解析器隐藏 s 中记录的密钥
+func Redact(s string) string {
+	return std.Redact(s)
+}
@@ -0,0 +1,102 @@
+package secret
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolver_Expand(t *testing.T) {
+	t.Setenv("SECRET_TEST_PASSWORD", "p@ss\"word")
+	t.Setenv("SECRET_TEST_EMPTY", "")
+	file := filepath.Join(t.TempDir(), "db")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewResolver()
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"${env:SECRET_TEST_PASSWORD}", "p@ss\"word"},
+		{"${env:SECRET_TEST_MISSING:-127.0.0.1:3306}", "127.0.0.1:3306"},
+		{"${env:SECRET_TEST_EMPTY}", ""},
+		{"${env:SECRET_TEST_EMPTY:-default}", ""},
+		{"${file:" + file + "}", "from-file"},
+		{"user:${env:SECRET_TEST_PASSWORD}@tcp", "user:p@ss\"word@tcp"},
+		{"$${env:SECRET_TEST_PASSWORD}", "${env:SECRET_TEST_PASSWORD}"},
+		{"${file:" + file + ".missing:-none}", "none"},
+	}
+	for _, tt := range tests {
+		got, err := r.Expand(context.Background(), tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Expand(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"${env:SECRET_TEST_MISSING}", "${nope:x}", "${env:X", "${env}"} {
+		if _, err := r.Expand(context.Background(), in); err == nil {
+			t.Errorf("Expand(%q) expected error", in)
+		}
+	}
+
+	// 只有记录的密钥会被隐藏，短值不记录
+	r.Track("p@ss\"word")
+	r.Track("from-file")
+	r.Track("iam")
+	redacted := r.Redact(`{"password":"p@ss\"word","file":"from-file","host":"127.0.0.1:3306","username":"iam"}`)
+	if strings.Contains(redacted, "p@ss") || strings.Contains(redacted, "from-file") ||
+		!strings.Contains(redacted, "127.0.0.1:3306") || !strings.Contains(redacted, `"iam"`) {
+		t.Errorf("secrets not redacted: %s", redacted)
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/iam":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"v2-secret"},"metadata":{"version":1}}}`))
+		case "/v1/kv/iam":
+			_, _ = w.Write([]byte(`{"data":{"password":"v1-secret"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	r := NewResolver()
+	r.Register("vault", NewVaultProvider(srv.URL, "root"))
+
+	for in, want := range map[string]string{
+		"${vault:secret/data/iam#password}": "v2-secret",
+		"${vault:kv/iam#password}":          "v1-secret",
+	} {
+		got, err := r.Expand(context.Background(), in)
+		if err != nil || got != want {
+			t.Errorf("Expand(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"${vault:secret/data/iam#missing}", "${vault:secret/data/none#password}", "${vault:secret/data/iam}"} {
+		if _, err := r.Expand(context.Background(), in); err == nil {
+			t.Errorf("Expand(%q) expected error", in)
+		}
+	}
+
+	r.Register("vault", NewVaultProvider(srv.URL, "bad"))
+	if _, err := r.Expand(context.Background(), "${vault:secret/data/iam#password}"); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("expected permission denied, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultProvider 从 Vault 兼容的 KV 存储读取密钥。
+// ref 的格式为 path#key，path 为 /v1/ 之后的 API 路径，例如 KV v2 的 secret/data/iam#password，
+// 同时兼容 KV v1 和 v2 的返回格式
+type VaultProvider struct {
+	Address string
+	Token   string
+	// Namespace Vault 企业版的命名空间，可以为空
+	Namespace string
+	Client    *http.Client
+}
+
+// NewVaultProvider 创建 Vault 密钥提供者
+func NewVaultProvider(address, token string) *VaultProvider {
+	return &VaultProvider{
+		Address: strings.TrimRight(address, "/"),
+		Token:   token,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewVaultProviderFromEnv 根据环境变量 VAULT_ADDR、VAULT_TOKEN、VAULT_NAMESPACE 创建 Vault 密钥提供者，
+// VAULT_ADDR 为空时返回 nil
+func NewVaultProviderFromEnv() *VaultProvider {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil
+	}
+	p := NewVaultProvider(addr, os.Getenv("VAULT_TOKEN"))
+	p.Namespace = os.Getenv("VAULT_NAMESPACE")
+
+	return p
+}
+
+type vaultResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []string       `json:"errors"`
+}
+
+// Resolve 实现 Provider
+func (p *VaultProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("vault reference %q must be in path#key format", ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Address+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	if p.Token != "" {
+		req.Header.Set("X-Vault-Token", p.Token)
+	}
+	if p.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Namespace)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out vaultResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("decode vault response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("vault path %s: %w", path, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("read vault path %s: %s %s", path, resp.Status, strings.Join(out.Errors, "; "))
+	}
+
+	// KV v2 的数据在 data.data 中，KV v1 直接在 data 中
+	data := out.Data
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, isMeta := data["metadata"]; isMeta {
+			data = inner
+		}
+	}
+	v, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s in vault path %s: %w", key, path, ErrNotFound)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("key %s in vault path %s is not a string", key, path)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// 导出 span 的方式
+const (
+	// ExporterOTLP 通过 OTLP gRPC 协议导出到 collector
+	ExporterOTLP = "otlp"
+	// ExporterStdout 以 JSON 格式输出到标准输出
+	ExporterStdout = "stdout"
+	// ExporterFile 以 JSON 格式追加写入文件
+	ExporterFile = "file"
+)
+
+// Options 链路追踪配置，实现了 app.FlagsIntf
+type Options struct {
+	// Enabled 是否启用链路追踪，不启用时所有 span 都是空操作
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// ServiceName 服务名称，为空时使用程序名称
+	ServiceName string `json:"service-name" mapstructure:"service-name"`
+	// Exporter 导出方式：otlp、stdout、file
+	Exporter string `json:"exporter" mapstructure:"exporter"`
+	// Endpoint OTLP collector 的 gRPC 地址
+	Endpoint string `json:"endpoint" mapstructure:"endpoint"`
+	// Insecure 连接 OTLP collector 时不使用 TLS
+	Insecure bool `json:"insecure" mapstructure:"insecure"`
+	// Path file 导出方式的文件路径
+	Path string `json:"path" mapstructure:"path"`
+	// SampleRatio 没有上游 span 时的采样比例，有上游 span 时沿用上游的采样结果
+	SampleRatio float64 `json:"sample-ratio" mapstructure:"sample-ratio"`
+}
+
+// NewOptions 返回默认的链路追踪配置：不启用，启用后全部采样并导出到本地的 OTLP collector
+func NewOptions() *Options {
+	return &Options{
+		Exporter:    ExporterOTLP,
+		Endpoint:    "127.0.0.1:4317",
+		Insecure:    true,
+		SampleRatio: 1,
+	}
+}
+
+// AddFlags 添加链路追踪相关的命令行参数
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enabled, "tracing.enabled", o.Enabled, "Enable OpenTelemetry tracing.")
+	fs.StringVar(&o.ServiceName, "tracing.service-name", o.ServiceName, "Service name reported with spans, defaults to the program name.")
+	fs.StringVar(&o.Exporter, "tracing.exporter", o.Exporter, "Span exporter: otlp, stdout or file.")
+	fs.StringVar(&o.Endpoint, "tracing.endpoint", o.Endpoint, "gRPC address of the OTLP collector.")
+	fs.BoolVar(&o.Insecure, "tracing.insecure", o.Insecure, "Connect to the OTLP collector without TLS.")
+	fs.StringVar(&o.Path, "tracing.path", o.Path, "File spans are appended to when --tracing.exporter is file.")
+	fs.Float64Var(&o.SampleRatio, "tracing.sample-ratio", o.SampleRatio,
+		"Fraction of root traces to sample, traces started upstream follow the upstream decision.")
+}
+
+// Validate 校验链路追踪配置，未启用时不校验
+func (o *Options) Validate() []error {
+	if !o.Enabled {
+		return nil
+	}
+
+	var errs []error
+	switch o.Exporter {
+	case ExporterOTLP:
+		if o.Endpoint == "" {
+			errs = append(errs, fmt.Errorf("--tracing.endpoint must not be empty for the otlp exporter"))
+		}
+	case ExporterStdout:
+	case ExporterFile:
+		if o.Path == "" {
+			errs = append(errs, fmt.Errorf("--tracing.path must not be empty for the file exporter"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("--tracing.exporter %q must be one of [otlp, stdout, file]", o.Exporter))
+	}
+	if o.SampleRatio < 0 || o.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("--tracing.sample-ratio %v must be between 0 and 1", o.SampleRatio))
+	}
+
+	return errs
+}
@@ -0,0 +1,133 @@
+// Package tracing 基于 OpenTelemetry 的链路追踪。
+//
+// Init 根据 Options 设置全局的 TracerProvider 及 W3C trace context 传播方式，
+// HTTP、gRPC、GORM 等埋点通过 Start 创建 span，未启用时 span 是空操作但仍然传播上游的 trace context
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ahang7/go-IAM/pkg/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName IAM 埋点使用的 Tracer 名称
+const instrumentationName = "github.com/ahang7/go-IAM"
+
+var (
+	mu       sync.Mutex
+	provider *sdktrace.TracerProvider
+	// closer file 导出方式打开的文件
+	closer io.Closer
+)
+
+// Init 根据 opts 设置全局的 TracerProvider，未启用时只设置 trace context 传播方式
+func Init(opts *Options) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !opts.Enabled {
+		return nil
+	}
+
+	exporter, c, err := newExporter(opts)
+	if err != nil {
+		return err
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+		semconv.ServiceVersion(version.Get().GitVersion),
+	)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+
+	mu.Lock()
+	provider, closer = tp, c
+	mu.Unlock()
+	otel.SetTracerProvider(tp)
+
+	return nil
+}
+
+// newExporter 创建 span 导出器，file 导出方式同时返回需要在关闭时关闭的文件
+func newExporter(opts *Options) (sdktrace.SpanExporter, io.Closer, error) {
+	switch opts.Exporter {
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+
+		return exporter, nil, err
+	case ExporterFile:
+		if err := os.MkdirAll(filepath.Dir(opts.Path), 0o755); err != nil {
+			return nil, nil, fmt.Errorf("create trace directory: %w", err)
+		}
+		f, err := os.OpenFile(opts.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+
+			return nil, nil, err
+		}
+
+		return exporter, f, nil
+	default:
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		// 连接是惰性建立的，collector 不可用时不影响启动
+		exporter, err := otlptracegrpc.New(context.Background(), clientOpts...)
+
+		return exporter, nil, err
+	}
+}
+
+// Shutdown 导出尚未导出的 span 并关闭导出器，未启用时直接返回
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	tp, c := provider, closer
+	provider, closer = nil, nil
+	mu.Unlock()
+
+	if tp == nil {
+		return nil
+	}
+	err := tp.Shutdown(ctx)
+	if c != nil {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// Start 使用 IAM 的 Tracer 创建 span，ctx 中有 span 时作为其子 span
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End 结束 span，err 不为 nil 时记录错误并将 span 状态设置为 Error
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
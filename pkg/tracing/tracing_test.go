@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	opts := NewOptions()
+	opts.Enabled = true
+	opts.ServiceName = "iam-test"
+	opts.Exporter = ExporterFile
+	opts.Path = path
+	if errs := opts.Validate(); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if err := Init(opts); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	End(child, errors.New("boom"))
+	End(parent, nil)
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`"Name":"parent"`, `"Name":"child"`, `"Description":"boom"`, "iam-test"} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("expected %s in exported spans:\n%s", s, data)
+		}
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	opts := NewOptions()
+	opts.Exporter = "jaeger"
+	if errs := opts.Validate(); len(errs) != 0 {
+		t.Errorf("disabled tracing should not be validated, got %v", errs)
+	}
+
+	opts.Enabled = true
+	opts.SampleRatio = 2
+	if errs := opts.Validate(); len(errs) != 2 {
+		t.Errorf("expected 2 errors, got %v", errs)
+	}
+}
@@ -0,0 +1,83 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	versionFlagName = "version"
+
+	// VersionFalse 不输出版本信息
+	VersionFalse versionValue = 0
+	// VersionTrue 输出可读的版本信息
+	VersionTrue versionValue = 1
+	// VersionRaw 输出 JSON 格式的完整版本信息
+	VersionRaw versionValue = 2
+)
+
+const strRawVersion = "raw"
+
+// versionValue 实现 pflag.Value，支持 --version、--version=true/false、--version=raw
+type versionValue int
+
+func (v *versionValue) IsBoolFlag() bool {
+	return true
+}
+
+func (v *versionValue) Get() interface{} {
+	return *v
+}
+
+func (v *versionValue) Set(s string) error {
+	if s == strRawVersion {
+		*v = VersionRaw
+
+		return nil
+	}
+	boolVal, err := strconv.ParseBool(s)
+	if boolVal {
+		*v = VersionTrue
+	} else {
+		*v = VersionFalse
+	}
+
+	return err
+}
+
+func (v *versionValue) String() string {
+	if *v == VersionRaw {
+		return strRawVersion
+	}
+
+	return strconv.FormatBool(*v == VersionTrue)
+}
+
+func (v *versionValue) Type() string {
+	return "version"
+}
+
+var versionFlag = VersionFalse
+
+// AddFlags 向 fs 注册 --version 命令行参数
+func AddFlags(fs *pflag.FlagSet) {
+	fs.Var(&versionFlag, versionFlagName, "Print version information and quit. Use --version=raw for the full build info in JSON.")
+	// 允许只输入 --version 而不带参数值
+	fs.Lookup(versionFlagName).NoOptDefVal = "true"
+}
+
+// PrintAndExitIfRequested 如果指定了 --version 则输出版本信息并退出
+func PrintAndExitIfRequested() {
+	switch versionFlag {
+	case VersionRaw:
+		fmt.Println(Get().ToJSON())
+		os.Exit(0)
+	case VersionTrue:
+		fmt.Print(Get().Text())
+		os.Exit(0)
+	case VersionFalse:
+	}
+}
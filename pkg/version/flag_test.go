@@ -0,0 +1,58 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestVersionValue(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    versionValue
+		str     string
+		wantErr bool
+	}{
+		{"true", VersionTrue, "true", false},
+		{"false", VersionFalse, "false", false},
+		{"raw", VersionRaw, "raw", false},
+		{"1", VersionTrue, "true", false},
+		{"json", VersionFalse, "false", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			v := VersionTrue
+			err := v.Set(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if v != tt.want || v.String() != tt.str {
+				t.Errorf("Set(%q) = %v (%q), want %v (%q)", tt.in, v, v.String(), tt.want, tt.str)
+			}
+		})
+	}
+}
+
+func TestAddFlags(t *testing.T) {
+	tests := []struct {
+		args []string
+		want versionValue
+	}{
+		{nil, VersionFalse},
+		{[]string{"--version"}, VersionTrue},
+		{[]string{"--version=raw"}, VersionRaw},
+		{[]string{"--version=false"}, VersionFalse},
+	}
+	for _, tt := range tests {
+		versionFlag = VersionFalse
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		AddFlags(fs)
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatalf("Parse(%v) = %v", tt.args, err)
+		}
+		if versionFlag != tt.want {
+			t.Errorf("Parse(%v) version = %v, want %v", tt.args, versionFlag, tt.want)
+		}
+	}
+	versionFlag = VersionFalse
+}
@@ -0,0 +1,25 @@
+package version
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// NewCollector 返回一个输出构建信息的 prometheus Collector，
+// 指标名为 <namespace>_build_info，值恒为1，版本信息以 label 的形式给出
+func NewCollector(namespace string) prometheus.Collector {
+	info := Get()
+	return prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "build_info",
+			Help:      "A metric with a constant '1' value labeled by version information of the binary.",
+			ConstLabels: prometheus.Labels{
+				"git_version":    info.GitVersion,
+				"git_commit":     info.GitCommit,
+				"git_tree_state": info.GitTreeState,
+				"build_date":     info.BuildDate,
+				"go_version":     info.GoVersion,
+				"platform":       info.Platform,
+			},
+		},
+		func() float64 { return 1 },
+	)
+}
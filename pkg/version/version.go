@@ -0,0 +1,73 @@
+// Package version 提供构建版本信息，相关变量在编译时通过 -ldflags 注入
+//
+//	go build -ldflags "-X github.com/ahang7/go-IAM/pkg/version.GitVersion=v1.0.0 ..."
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// 以下变量通过 -ldflags "-X" 在编译时注入，未注入时使用默认值
+var (
+	// GitVersion 语义化版本号，例如 v1.0.0
+	GitVersion = "v0.0.0-master+$Format:%h$"
+	// GitCommit 构建时的 git commit sha1
+	GitCommit = "$Format:%H$"
+	// GitTreeState 构建时的工作区状态，clean 或 dirty
+	GitTreeState = ""
+	// BuildDate 构建时间，ISO8601 格式
+	BuildDate = "1970-01-01T00:00:00Z"
+)
+
+// Info 版本信息
+type Info struct {
+	GitVersion   string `json:"gitVersion"`
+	GitCommit    string `json:"gitCommit"`
+	GitTreeState string `json:"gitTreeState"`
+	BuildDate    string `json:"buildDate"`
+	GoVersion    string `json:"goVersion"`
+	Compiler     string `json:"compiler"`
+	Platform     string `json:"platform"`
+}
+
+// String 返回 GitVersion
+func (info Info) String() string {
+	return info.GitVersion
+}
+
+// ToJSON 以 JSON 格式返回版本信息
+func (info Info) ToJSON() string {
+	s, _ := json.Marshal(info)
+
+	return string(s)
+}
+
+// Text 以对齐的多行文本格式返回版本信息
+func (info Info) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-14s%s\n", "gitVersion:", info.GitVersion)
+	fmt.Fprintf(&b, "%-14s%s\n", "gitCommit:", info.GitCommit)
+	fmt.Fprintf(&b, "%-14s%s\n", "gitTreeState:", info.GitTreeState)
+	fmt.Fprintf(&b, "%-14s%s\n", "buildDate:", info.BuildDate)
+	fmt.Fprintf(&b, "%-14s%s\n", "goVersion:", info.GoVersion)
+	fmt.Fprintf(&b, "%-14s%s\n", "compiler:", info.Compiler)
+	fmt.Fprintf(&b, "%-14s%s\n", "platform:", info.Platform)
+
+	return b.String()
+}
+
+// Get 返回当前二进制的版本信息
+func Get() Info {
+	return Info{
+		GitVersion:   GitVersion,
+		GitCommit:    GitCommit,
+		GitTreeState: GitTreeState,
+		BuildDate:    BuildDate,
+		GoVersion:    runtime.Version(),
+		Compiler:     runtime.Compiler,
+		Platform:     fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	}
+}
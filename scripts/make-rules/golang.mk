@@ -1 +1,24 @@
-GO := go
\ No newline at end of file
+GO := go
+
+# 版本信息通过 -ldflags 注入 pkg/version
+VERSION_PACKAGE := github.com/ahang7/go-IAM/pkg/version
+GIT_VERSION ?= $(shell git describe --tags --always --match='v*' 2>/dev/null || echo v0.0.0)
+GIT_COMMIT := $(shell git rev-parse HEAD 2>/dev/null)
+GIT_TREE_STATE := $(if $(shell git status --porcelain --untracked-files=no 2>/dev/null),dirty,clean)
+BUILD_DATE := $(shell date -u +'%Y-%m-%dT%H:%M:%SZ')
+
+GO_LDFLAGS += -X $(VERSION_PACKAGE).GitVersion=$(GIT_VERSION) \
+	-X $(VERSION_PACKAGE).GitCommit=$(GIT_COMMIT) \
+	-X $(VERSION_PACKAGE).GitTreeState=$(GIT_TREE_STATE) \
+	-X $(VERSION_PACKAGE).BuildDate=$(BUILD_DATE)
+
+OUTPUT_DIR ?= _output
+COMMANDS ?= $(notdir $(wildcard cmd/*))
+
+.PHONY: go.build
+go.build: $(addprefix go.build., $(COMMANDS))
+
+.PHONY: go.build.%
+go.build.%:
+	@echo "===========> Building binary $* $(GIT_VERSION)"
+	@$(GO) build -ldflags "$(GO_LDFLAGS)" -o $(OUTPUT_DIR)/$* ./cmd/$*
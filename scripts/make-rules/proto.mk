@@ -0,0 +1,8 @@
+.PHONY: proto.gen
+proto.gen: tools.verify.buf tools.verify.protoc-gen-go tools.verify.protoc-gen-go-grpc tools.verify.protoc-gen-openapiv2
+	@echo "===========> Generating protobuf files and OpenAPI document"
+	@cd api && buf generate --path iam
+
+.PHONY: proto.lint
+proto.lint: tools.verify.buf
+	@cd api && buf lint
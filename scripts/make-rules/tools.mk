@@ -23,4 +23,20 @@ install.git-chglog:
 
 .PHONY: install.errcodegen
 install.errcodegen:
-	@$(GO) install $(ROOT_DIR)/tools/errcodegen/gen.go
\ No newline at end of file
+	@$(GO) install $(ROOT_DIR)/tools/errcodegen/gen.go
+
+.PHONY: install.buf
+install.buf:
+	@$(GO) install github.com/bufbuild/buf/cmd/buf@v1.34.0
+
+.PHONY: install.protoc-gen-go
+install.protoc-gen-go:
+	@$(GO) install google.golang.org/protobuf/cmd/protoc-gen-go@v1.34.2
+
+.PHONY: install.protoc-gen-go-grpc
+install.protoc-gen-go-grpc:
+	@$(GO) install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.4.0
+
+.PHONY: install.protoc-gen-openapiv2
+install.protoc-gen-openapiv2:
+	@$(GO) install github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2@v2.20.0